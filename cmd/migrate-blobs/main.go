@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"picture-this/internal/blobstore"
+	"picture-this/internal/config"
+	"picture-this/internal/db"
+)
+
+func main() {
+	if err := config.LoadDotEnv(".env"); err != nil {
+		log.Printf("failed to load .env: %v", err)
+	}
+	cfg := config.Load()
+
+	store, err := blobstore.FromConfig(cfg)
+	if err != nil {
+		log.Fatalf("blob store setup failed: %v", err)
+	}
+	if store == nil {
+		log.Fatal("BLOB_STORE is not set")
+	}
+
+	conn, err := db.Open()
+	if err != nil {
+		log.Fatalf("database connection failed: %v", err)
+	}
+
+	ctx := context.Background()
+	stats, err := db.MoveBlobs(conn, func(data []byte) (string, error) {
+		return store.Put(ctx, data)
+	})
+	log.Printf("moved %d drawings, %d chain links, %d avatars, %d events", stats.Drawings, stats.ChainLinks, stats.Avatars, stats.Events)
+	if err != nil {
+		log.Fatalf("blob migration failed: %v", err)
+	}
+}
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"picture-this/internal/config"
@@ -47,8 +51,22 @@ func main() {
 	if err := srv.RestoreActiveGames(); err != nil {
 		log.Printf("failed to restore active games: %v", err)
 	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	srv.StartCluster(ctx)
+	srv.StartJanitor(ctx)
+	srv.StartPresence(ctx)
+
+	httpServer := &http.Server{Addr: addr, Handler: srv.Handler()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
 	log.Printf("picture-this server listening on %s", addr)
-	if err := http.ListenAndServe(addr, srv.Handler()); err != nil {
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal(err)
 	}
+	srv.ReleaseLeases()
 }
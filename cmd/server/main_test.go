@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"picture-this/internal/db"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// TestClusterOwnerFailover runs two server processes against one database and
+// checks that killing the owner of a game hands it to the surviving node. It
+// needs a disposable Postgres database in TEST_DATABASE_URL.
+func TestClusterOwnerFailover(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	conn, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.Migrate(conn); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	bin := filepath.Join(t.TempDir(), "picture-this")
+	build := exec.Command("go", "build", "-o", bin, ".")
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		t.Fatalf("build server: %v", err)
+	}
+	nodeA, urlA := startNode(t, bin, "node-a", dsn)
+	_, urlB := startNode(t, bin, "node-b", dsn)
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar, Timeout: 5 * time.Second}
+	email := fmt.Sprintf("cluster-%d@example.com", time.Now().UnixNano())
+	postJSON(t, client, urlA+"/api/auth/register", map[string]string{"email": email, "password": "correct horse battery"}, http.StatusCreated).Body.Close()
+	var created struct {
+		GameID string `json:"game_id"`
+	}
+	decodeJSON(t, postJSON(t, client, urlA+"/api/games", nil, http.StatusCreated), &created)
+	dbID, err := strconv.Atoi(strings.TrimPrefix(created.GameID, "game-"))
+	if err != nil {
+		t.Fatalf("unexpected game id %q", created.GameID)
+	}
+	if owner := leaseOwner(t, conn, dbID); owner != "node-a" {
+		t.Fatalf("expected node-a to own the new game, got %q", owner)
+	}
+
+	if status := getStatus(client, urlB+"/api/games/"+created.GameID); status != http.StatusOK {
+		t.Fatalf("expected node-b to forward to the owner, got %d", status)
+	}
+
+	_ = nodeA.Process.Kill()
+	_ = nodeA.Wait()
+
+	deadline := time.Now().Add(20 * time.Second)
+	for time.Now().Before(deadline) {
+		if leaseOwner(t, conn, dbID) == "node-b" && getStatus(client, urlB+"/api/games/"+created.GameID) == http.StatusOK {
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	t.Fatalf("node-b did not take over %s; lease owner=%q", created.GameID, leaseOwner(t, conn, dbID))
+}
+
+func startNode(t *testing.T, bin, nodeID, dsn string) (*exec.Cmd, string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("listen unavailable: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d", port)
+
+	cmd := exec.Command(bin)
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(os.Environ(),
+		"DATABASE_URL="+dsn,
+		"PORT="+strconv.Itoa(port),
+		"NODE_ID="+nodeID,
+		"NODE_URL="+baseURL,
+		"LEASE_TTL_SECONDS=2",
+	)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start %s: %v", nodeID, err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_, _ = cmd.Process.Wait()
+	})
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if getStatus(http.DefaultClient, baseURL+"/") == http.StatusOK {
+			return cmd, baseURL
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("%s did not become ready", nodeID)
+	return nil, ""
+}
+
+func leaseOwner(t *testing.T, conn *gorm.DB, dbID int) string {
+	t.Helper()
+	var lease db.GameLease
+	if err := conn.Where("game_id = ? AND expires_at > now()", dbID).First(&lease).Error; err != nil {
+		return ""
+	}
+	return lease.Owner
+}
+
+func postJSON(t *testing.T, client *http.Client, url string, body any, want int) *http.Response {
+	t.Helper()
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	if resp.StatusCode != want {
+		t.Fatalf("POST %s: expected %d, got %d", url, want, resp.StatusCode)
+	}
+	return resp
+}
+
+func decodeJSON(t *testing.T, resp *http.Response, target any) {
+	t.Helper()
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+}
+
+func getStatus(client *http.Client, url string) int {
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
@@ -0,0 +1,2 @@
+ALTER TABLE games
+  ADD COLUMN IF NOT EXISTS phase_started_at timestamptz NOT NULL DEFAULT now();
@@ -0,0 +1,2 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_events_version ON events (version);
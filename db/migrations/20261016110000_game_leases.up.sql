@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS game_leases (
+  game_id bigint PRIMARY KEY REFERENCES games(id) ON DELETE CASCADE,
+  owner varchar(128) NOT NULL,
+  owner_url varchar(255) NOT NULL,
+  expires_at timestamptz NOT NULL,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_leases_owner ON game_leases(owner);
+CREATE INDEX IF NOT EXISTS idx_game_leases_expires_at ON game_leases(expires_at);
@@ -0,0 +1,2 @@
+ALTER TABLE players DROP COLUMN IF EXISTS team;
+ALTER TABLE games DROP COLUMN IF EXISTS team_count;
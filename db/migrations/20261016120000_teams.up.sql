@@ -0,0 +1,2 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS team_count integer NOT NULL DEFAULT 0;
+ALTER TABLE players ADD COLUMN IF NOT EXISTS team integer NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS chain_links;
+ALTER TABLE rounds DROP COLUMN IF EXISTS chain_step;
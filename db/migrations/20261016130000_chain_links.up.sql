@@ -0,0 +1,18 @@
+ALTER TABLE rounds
+  ADD COLUMN IF NOT EXISTS chain_step integer NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS chain_links (
+  id bigserial PRIMARY KEY,
+  round_id bigint NOT NULL REFERENCES rounds(id) ON DELETE CASCADE,
+  chain_index integer NOT NULL,
+  step integer NOT NULL,
+  player_id bigint NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+  kind varchar(16) NOT NULL,
+  text varchar(140) NOT NULL DEFAULT '',
+  image_data bytea,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT idx_chain_links_round_chain_step UNIQUE (round_id, chain_index, step)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chain_links_round_id ON chain_links(round_id);
+CREATE INDEX IF NOT EXISTS idx_chain_links_player_id ON chain_links(player_id);
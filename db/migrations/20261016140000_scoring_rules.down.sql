@@ -0,0 +1,6 @@
+ALTER TABLE games
+  DROP COLUMN IF EXISTS scoring_like_points,
+  DROP COLUMN IF EXISTS scoring_final_round_multiplier,
+  DROP COLUMN IF EXISTS scoring_artist_bonus,
+  DROP COLUMN IF EXISTS scoring_fooled_player,
+  DROP COLUMN IF EXISTS scoring_correct_guess;
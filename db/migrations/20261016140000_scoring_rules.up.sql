@@ -0,0 +1,6 @@
+ALTER TABLE games
+  ADD COLUMN IF NOT EXISTS scoring_correct_guess integer NOT NULL DEFAULT 1000,
+  ADD COLUMN IF NOT EXISTS scoring_fooled_player integer NOT NULL DEFAULT 500,
+  ADD COLUMN IF NOT EXISTS scoring_artist_bonus integer NOT NULL DEFAULT 500,
+  ADD COLUMN IF NOT EXISTS scoring_final_round_multiplier integer NOT NULL DEFAULT 1,
+  ADD COLUMN IF NOT EXISTS scoring_like_points integer NOT NULL DEFAULT 0;
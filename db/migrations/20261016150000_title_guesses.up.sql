@@ -0,0 +1,2 @@
+ALTER TABLE games
+  ADD COLUMN IF NOT EXISTS scoring_title_guess_bonus integer NOT NULL DEFAULT 250;
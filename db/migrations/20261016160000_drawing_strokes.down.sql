@@ -0,0 +1,2 @@
+ALTER TABLE drawings
+  DROP COLUMN IF EXISTS stroke_data;
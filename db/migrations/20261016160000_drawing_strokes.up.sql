@@ -0,0 +1,2 @@
+ALTER TABLE drawings
+  ADD COLUMN IF NOT EXISTS stroke_data jsonb;
@@ -0,0 +1,9 @@
+-- image_data stays nullable: rows moved to the blob store have no bytes here.
+ALTER TABLE players
+  DROP COLUMN IF EXISTS avatar_hash;
+
+ALTER TABLE chain_links
+  DROP COLUMN IF EXISTS image_hash;
+
+ALTER TABLE drawings
+  DROP COLUMN IF EXISTS image_hash;
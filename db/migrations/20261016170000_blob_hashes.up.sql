@@ -0,0 +1,9 @@
+ALTER TABLE drawings
+  ALTER COLUMN image_data DROP NOT NULL,
+  ADD COLUMN IF NOT EXISTS image_hash varchar(64) NOT NULL DEFAULT '';
+
+ALTER TABLE chain_links
+  ADD COLUMN IF NOT EXISTS image_hash varchar(64) NOT NULL DEFAULT '';
+
+ALTER TABLE players
+  ADD COLUMN IF NOT EXISTS avatar_hash varchar(64) NOT NULL DEFAULT '';
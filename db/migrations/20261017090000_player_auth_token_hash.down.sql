@@ -0,0 +1,2 @@
+ALTER TABLE players
+  DROP COLUMN IF EXISTS auth_token_hash;
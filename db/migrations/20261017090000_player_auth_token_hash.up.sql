@@ -0,0 +1,2 @@
+ALTER TABLE players
+  ADD COLUMN IF NOT EXISTS auth_token_hash varchar(64) NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS title_guesses (
+  id bigserial PRIMARY KEY,
+  game_id bigint NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+  round_id bigint NOT NULL REFERENCES rounds(id) ON DELETE CASCADE,
+  player_id bigint NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+  drawing_index integer NOT NULL,
+  guess varchar(280) NOT NULL,
+  correct boolean NOT NULL DEFAULT true,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT idx_title_guesses_round_player_drawing UNIQUE (round_id, player_id, drawing_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_title_guesses_game_id ON title_guesses(game_id);
+CREATE INDEX IF NOT EXISTS idx_title_guesses_round_id ON title_guesses(round_id);
+CREATE INDEX IF NOT EXISTS idx_title_guesses_player_id ON title_guesses(player_id);
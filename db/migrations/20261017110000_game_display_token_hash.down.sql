@@ -0,0 +1,2 @@
+ALTER TABLE games
+  DROP COLUMN IF EXISTS display_token_hash;
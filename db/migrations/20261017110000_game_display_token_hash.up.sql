@@ -0,0 +1,2 @@
+ALTER TABLE games
+  ADD COLUMN IF NOT EXISTS display_token_hash varchar(64) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE games
+  DROP COLUMN IF EXISTS phase_extension_ms,
+  DROP COLUMN IF EXISTS paused_remaining_ms,
+  DROP COLUMN IF EXISTS paused_phase;
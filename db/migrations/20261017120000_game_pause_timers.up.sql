@@ -0,0 +1,4 @@
+ALTER TABLE games
+  ADD COLUMN IF NOT EXISTS paused_phase varchar(32) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS paused_remaining_ms bigint NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS phase_extension_ms bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+ALTER TABLE games
+  DROP COLUMN IF EXISTS timer_reveal_seconds,
+  DROP COLUMN IF EXISTS timer_vote_seconds,
+  DROP COLUMN IF EXISTS timer_guess_seconds,
+  DROP COLUMN IF EXISTS timer_draw_seconds,
+  DROP COLUMN IF EXISTS timer_preset;
@@ -0,0 +1,6 @@
+ALTER TABLE games
+  ADD COLUMN IF NOT EXISTS timer_preset varchar(16) NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS timer_draw_seconds integer NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS timer_guess_seconds integer NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS timer_vote_seconds integer NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS timer_reveal_seconds integer NOT NULL DEFAULT 0;
@@ -0,0 +1,63 @@
+// Package blobstore keeps drawing and avatar images outside Postgres, keyed by
+// the SHA-256 of their contents so identical images are stored once and a
+// hash can be served with permanent cache headers.
+package blobstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"picture-this/internal/config"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+type BlobStore interface {
+	// Put stores data and returns its hash. Storing a blob that already
+	// exists is a no-op.
+	Put(ctx context.Context, data []byte) (string, error)
+	Get(ctx context.Context, hash string) ([]byte, error)
+}
+
+// Hash is the content address of data: lowercase hex SHA-256.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func ValidHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	for _, r := range hash {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// FromConfig builds the configured store, or returns nil when BLOB_STORE is
+// unset and images stay inline.
+func FromConfig(cfg config.Config) (BlobStore, error) {
+	switch cfg.BlobStore {
+	case "":
+		return nil, nil
+	case "local":
+		return NewLocal(cfg.BlobDir)
+	case "s3":
+		return NewS3(S3Options{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			Prefix:          cfg.S3Prefix,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", cfg.BlobStore)
+	}
+}
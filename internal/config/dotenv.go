@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -28,6 +30,7 @@ type Config struct {
 	RevealGuessesSeconds     int
 	RevealVotesSeconds       int
 	RevealJokeSeconds        int
+	PhaseGraceSeconds        int
 	DBMaxOpenConns           int
 	DBMaxIdleConns           int
 	DBConnMaxLifetimeSeconds int
@@ -38,6 +41,27 @@ type Config struct {
 	PromptSimilarityMax      float64
 	OpenAIPromptSystemPath   string
 	OpenAIPromptUserPath     string
+	NodeID                   string
+	NodeURL                  string
+	LeaseTTLSeconds          int
+	CompleteGameGraceSeconds int
+	IdleLobbyTTLSeconds      int
+	JanitorIntervalSeconds   int
+	PresenceIdleSeconds      int
+	AwaySkipSeconds          int
+	AwayAlertSeconds         int
+	HostAwaySeconds          int
+	BlobStore                string
+	BlobDir                  string
+	S3Endpoint               string
+	S3Region                 string
+	S3Bucket                 string
+	S3Prefix                 string
+	S3AccessKeyID            string
+	S3SecretAccessKey        string
+	// AllowedOrigins lists the origins, besides the server's own, that may
+	// open realtime connections. "*" allows any origin.
+	AllowedOrigins []string
 }
 
 func Default() Config {
@@ -50,6 +74,7 @@ func Default() Config {
 		RevealGuessesSeconds:     6,
 		RevealVotesSeconds:       6,
 		RevealJokeSeconds:        6,
+		PhaseGraceSeconds:        3,
 		DBMaxOpenConns:           10,
 		DBMaxIdleConns:           10,
 		DBConnMaxLifetimeSeconds: 300,
@@ -59,6 +84,16 @@ func Default() Config {
 		PromptSimilarityMax:      0.12,
 		OpenAIPromptSystemPath:   "prompts/openai_drawing_system.txt",
 		OpenAIPromptUserPath:     "prompts/openai_drawing_user.txt",
+		LeaseTTLSeconds:          15,
+		CompleteGameGraceSeconds: 600,
+		IdleLobbyTTLSeconds:      3600,
+		JanitorIntervalSeconds:   60,
+		PresenceIdleSeconds:      60,
+		AwaySkipSeconds:          15,
+		AwayAlertSeconds:         30,
+		HostAwaySeconds:          60,
+		BlobDir:                  "data/blobs",
+		S3Region:                 "us-east-1",
 	}
 }
 
@@ -104,6 +139,11 @@ func Load() Config {
 			cfg.RevealJokeSeconds = value
 		}
 	}
+	if raw := os.Getenv("PHASE_GRACE_SECONDS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value >= 0 {
+			cfg.PhaseGraceSeconds = value
+		}
+	}
 	if raw := os.Getenv("DB_MAX_OPEN_CONNS"); raw != "" {
 		if value, err := strconv.Atoi(raw); err == nil && value > 0 {
 			cfg.DBMaxOpenConns = value
@@ -144,5 +184,68 @@ func Load() Config {
 	if raw := os.Getenv("OPENAI_PROMPT_USER_PATH"); raw != "" {
 		cfg.OpenAIPromptUserPath = raw
 	}
+	cfg.NodeID = os.Getenv("NODE_ID")
+	if cfg.NodeID == "" {
+		hostname, _ := os.Hostname()
+		cfg.NodeID = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+	cfg.NodeURL = os.Getenv("NODE_URL")
+	if raw := os.Getenv("LEASE_TTL_SECONDS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value > 0 {
+			cfg.LeaseTTLSeconds = value
+		}
+	}
+	if raw := os.Getenv("COMPLETE_GAME_GRACE_SECONDS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value >= 0 {
+			cfg.CompleteGameGraceSeconds = value
+		}
+	}
+	if raw := os.Getenv("IDLE_LOBBY_TTL_SECONDS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value >= 0 {
+			cfg.IdleLobbyTTLSeconds = value
+		}
+	}
+	if raw := os.Getenv("JANITOR_INTERVAL_SECONDS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value > 0 {
+			cfg.JanitorIntervalSeconds = value
+		}
+	}
+	if raw := os.Getenv("PRESENCE_IDLE_SECONDS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value >= 0 {
+			cfg.PresenceIdleSeconds = value
+		}
+	}
+	if raw := os.Getenv("AWAY_SKIP_SECONDS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value >= 0 {
+			cfg.AwaySkipSeconds = value
+		}
+	}
+	if raw := os.Getenv("AWAY_ALERT_SECONDS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value >= 0 {
+			cfg.AwayAlertSeconds = value
+		}
+	}
+	if raw := os.Getenv("HOST_AWAY_SECONDS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value >= 0 {
+			cfg.HostAwaySeconds = value
+		}
+	}
+	cfg.BlobStore = os.Getenv("BLOB_STORE")
+	if raw := os.Getenv("BLOB_DIR"); raw != "" {
+		cfg.BlobDir = raw
+	}
+	cfg.S3Endpoint = os.Getenv("S3_ENDPOINT")
+	if raw := os.Getenv("S3_REGION"); raw != "" {
+		cfg.S3Region = raw
+	}
+	cfg.S3Bucket = os.Getenv("S3_BUCKET")
+	cfg.S3Prefix = os.Getenv("S3_PREFIX")
+	cfg.S3AccessKeyID = os.Getenv("S3_ACCESS_KEY_ID")
+	cfg.S3SecretAccessKey = os.Getenv("S3_SECRET_ACCESS_KEY")
+	for _, origin := range strings.Split(os.Getenv("ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			cfg.AllowedOrigins = append(cfg.AllowedOrigins, origin)
+		}
+	}
 	return cfg
 }
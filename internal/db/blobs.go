@@ -0,0 +1,149 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// BlobMoveStats counts the rows MoveBlobs rewrote.
+type BlobMoveStats struct {
+	Drawings   int
+	ChainLinks int
+	Avatars    int
+	Events     int
+}
+
+const blobMoveBatch = 100
+
+// MoveBlobs copies every inline drawing and avatar image into a blob store
+// with put, which returns the blob's hash, then replaces the bytes with that
+// hash. Journal events that embed images are rewritten the same way. Rows are
+// handled one at a time, so an interrupted run can simply be restarted.
+func MoveBlobs(conn *gorm.DB, put func([]byte) (string, error)) (BlobMoveStats, error) {
+	var stats BlobMoveStats
+	if conn == nil {
+		return stats, nil
+	}
+	var err error
+	if stats.Drawings, err = moveImageColumn(conn, &Drawing{}, "drawings", "image_data", "image_hash", put); err != nil {
+		return stats, err
+	}
+	if stats.ChainLinks, err = moveImageColumn(conn, &ChainLink{}, "chain_links", "image_data", "image_hash", put); err != nil {
+		return stats, err
+	}
+	if stats.Avatars, err = moveImageColumn(conn, &Player{}, "players", "avatar_image", "avatar_hash", put); err != nil {
+		return stats, err
+	}
+	stats.Events, err = moveEventImages(conn, put)
+	return stats, err
+}
+
+func moveImageColumn(conn *gorm.DB, model any, table, dataColumn, hashColumn string, put func([]byte) (string, error)) (int, error) {
+	moved := 0
+	lastID := uint(0)
+	for {
+		var rows []struct {
+			ID   uint
+			Data []byte
+		}
+		err := conn.Table(table).
+			Select("id, "+dataColumn+" AS data").
+			Where("id > ? AND "+hashColumn+" = '' AND "+dataColumn+" IS NOT NULL AND length("+dataColumn+") > 0", lastID).
+			Order("id").
+			Limit(blobMoveBatch).
+			Scan(&rows).Error
+		if err != nil {
+			return moved, err
+		}
+		if len(rows) == 0 {
+			return moved, nil
+		}
+		for _, row := range rows {
+			lastID = row.ID
+			hash, err := put(row.Data)
+			if err != nil {
+				return moved, err
+			}
+			if err := conn.Model(model).Where("id = ?", row.ID).Updates(map[string]any{dataColumn: nil, hashColumn: hash}).Error; err != nil {
+				return moved, err
+			}
+			moved++
+		}
+	}
+}
+
+// eventImageFields maps the byte fields journal payloads carry to the hash
+// fields that replace them.
+var eventImageFields = map[string]string{
+	"image_data": "image_hash",
+	"avatar":     "avatar_hash",
+}
+
+func moveEventImages(conn *gorm.DB, put func([]byte) (string, error)) (int, error) {
+	moved := 0
+	lastID := uint(0)
+	for {
+		var events []Event
+		err := conn.Where("id > ? AND (payload ->> 'image_data' IS NOT NULL OR payload ->> 'avatar' IS NOT NULL)", lastID).
+			Order("id").
+			Limit(blobMoveBatch).
+			Find(&events).Error
+		if err != nil {
+			return moved, err
+		}
+		if len(events) == 0 {
+			return moved, nil
+		}
+		for _, event := range events {
+			lastID = event.ID
+			payload, changed, err := moveEventPayload(event.Payload, put)
+			if err != nil {
+				return moved, err
+			}
+			if !changed {
+				continue
+			}
+			if err := conn.Model(&Event{}).Where("id = ?", event.ID).Update("payload", payload).Error; err != nil {
+				return moved, err
+			}
+			moved++
+		}
+	}
+}
+
+func moveEventPayload(payload []byte, put func([]byte) (string, error)) ([]byte, bool, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, false, err
+	}
+	changed := false
+	for dataField, hashField := range eventImageFields {
+		raw, ok := fields[dataField]
+		if !ok {
+			continue
+		}
+		var encoded string
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		hash, err := put(data)
+		if err != nil {
+			return nil, false, err
+		}
+		hashJSON, _ := json.Marshal(hash)
+		fields[hashField] = hashJSON
+		delete(fields, dataField)
+		changed = true
+	}
+	if !changed {
+		return payload, false, nil
+	}
+	out, err := json.Marshal(fields)
+	return out, true, err
+}
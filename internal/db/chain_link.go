@@ -0,0 +1,19 @@
+package db
+
+import "time"
+
+// ChainLink is one drawing or description in a telephone chain. Chains are
+// numbered by the seat of the player who started them.
+type ChainLink struct {
+	ID         uint      `gorm:"primaryKey"`
+	RoundID    uint      `gorm:"index;not null;uniqueIndex:idx_chain_links_round_chain_step"`
+	ChainIndex int       `gorm:"not null;uniqueIndex:idx_chain_links_round_chain_step"`
+	Step       int       `gorm:"not null;uniqueIndex:idx_chain_links_round_chain_step"`
+	PlayerID   uint      `gorm:"index;not null"`
+	Kind       string    `gorm:"size:16;not null"`
+	Text       string    `gorm:"size:140;not null;default:''"`
+	ImageData  []byte    `gorm:"type:bytea"`
+	CreatedAt  time.Time `gorm:"not null"`
+
+	ImageHash string `gorm:"size:64;not null;default:''"`
+}
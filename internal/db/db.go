@@ -33,9 +33,12 @@ func Migrate(conn *gorm.DB) error {
 		&Guess{},
 		&Vote{},
 		&Like{},
+		&ChainLink{},
+		&TitleGuess{},
 		&Event{},
 		&PromptLibrary{},
 		&Session{},
+		&GameLease{},
 	); err != nil {
 		return err
 	}
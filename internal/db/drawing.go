@@ -7,7 +7,10 @@ type Drawing struct {
 	RoundID   uint      `gorm:"index;not null;uniqueIndex:idx_drawings_round_player;uniqueIndex:idx_drawings_round_prompt"`
 	PlayerID  uint      `gorm:"index;not null;uniqueIndex:idx_drawings_round_player"`
 	PromptID  uint      `gorm:"index;not null;uniqueIndex:idx_drawings_round_prompt"`
-	ImageData []byte    `gorm:"type:bytea;not null"`
+	ImageData []byte    `gorm:"type:bytea"`
 	CreatedAt time.Time `gorm:"not null"`
 	UpdatedAt time.Time `gorm:"not null"`
+
+	StrokeData []byte `gorm:"type:jsonb"`
+	ImageHash  string `gorm:"size:64;not null;default:''"`
 }
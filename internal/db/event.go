@@ -12,6 +12,7 @@ type Event struct {
 	RoundID   *uint          `gorm:"index"`
 	PlayerID  *uint          `gorm:"index"`
 	Type      string         `gorm:"size:64;not null"`
+	Version   int64          `gorm:"index;not null;default:0"`
 	Payload   datatypes.JSON `gorm:"type:jsonb;not null"`
 	CreatedAt time.Time      `gorm:"not null"`
 }
@@ -6,6 +6,7 @@ type Game struct {
 	ID               uint      `gorm:"primaryKey"`
 	JoinCode         string    `gorm:"size:12;uniqueIndex;not null"`
 	Phase            string    `gorm:"size:32;not null"`
+	PhaseStartedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
 	PromptsPerPlayer int       `gorm:"not null;default:2"`
 	MinPlayers       int       `gorm:"not null;default:2"`
 	MaxPlayers       int       `gorm:"not null;default:0"`
@@ -15,10 +16,41 @@ type Game struct {
 	AudienceEnabled  bool      `gorm:"not null;default:false"`
 	JokesEnabled     bool      `gorm:"not null;default:false"`
 	PublicReplay     bool      `gorm:"not null;default:false"`
+	TeamCount        int       `gorm:"not null;default:0"`
 	Version          int64     `gorm:"not null;default:0"`
+	DisplayTokenHash string    `gorm:"size:64;not null;default:''"`
 	CreatedAt        time.Time `gorm:"not null"`
 	UpdatedAt        time.Time `gorm:"not null"`
 	Players          []Player
 	Rounds           []Round
 	Events           []Event
+
+	// PausedPhase, PausedRemainingMs, PhaseExtensionMs and PhaseDeadline
+	// keep a game's phase clock for row restores.
+	PausedPhase       string `gorm:"size:32;not null;default:''"`
+	PausedRemainingMs int64  `gorm:"not null;default:0"`
+	PhaseExtensionMs  int64  `gorm:"not null;default:0"`
+	PhaseDeadline     *time.Time
+
+	ScoringRules ScoringRules  `gorm:"embedded;embeddedPrefix:scoring_"`
+	Timers       TimerSettings `gorm:"embedded;embeddedPrefix:timer_"`
+}
+
+// ScoringRules holds a game's point values; see game.ScoringRules.
+type ScoringRules struct {
+	CorrectGuess         int `gorm:"not null;default:1000"`
+	FooledPlayer         int `gorm:"not null;default:500"`
+	ArtistBonus          int `gorm:"not null;default:500"`
+	FinalRoundMultiplier int `gorm:"not null;default:1"`
+	LikePoints           int `gorm:"not null;default:0"`
+	TitleGuessBonus      int `gorm:"not null;default:250"`
+}
+
+// TimerSettings holds a game's phase durations; zero keeps the server's.
+type TimerSettings struct {
+	Preset        string `gorm:"size:16;not null;default:''"`
+	DrawSeconds   int    `gorm:"not null;default:0"`
+	GuessSeconds  int    `gorm:"not null;default:0"`
+	VoteSeconds   int    `gorm:"not null;default:0"`
+	RevealSeconds int    `gorm:"not null;default:0"`
 }
@@ -0,0 +1,13 @@
+package db
+
+import "time"
+
+// GameLease records which server node currently owns a game. A node keeps its
+// leases alive with heartbeats; any node may take over once ExpiresAt passes.
+type GameLease struct {
+	GameID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	Owner     string    `gorm:"size:128;index;not null"`
+	OwnerURL  string    `gorm:"size:255;not null"`
+	ExpiresAt time.Time `gorm:"index;not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
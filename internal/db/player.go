@@ -10,6 +10,7 @@ type Player struct {
 	Color            string    `gorm:"size:16;not null;default:''"`
 	IsHost           bool      `gorm:"not null;default:false"`
 	RecoveryCodeHash string    `gorm:"size:128;not null;default:''"`
+	Team             int       `gorm:"not null;default:0"`
 	JoinedAt         time.Time `gorm:"not null"`
 	CreatedAt        time.Time `gorm:"not null"`
 	UpdatedAt        time.Time `gorm:"not null"`
@@ -18,4 +19,7 @@ type Player struct {
 	Guesses          []Guess
 	Votes            []Vote
 	Events           []Event
+
+	AvatarHash    string `gorm:"size:64;not null;default:''"`
+	AuthTokenHash string `gorm:"size:64;not null;default:''"`
 }
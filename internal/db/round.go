@@ -9,6 +9,7 @@ type Round struct {
 	Status    string    `gorm:"size:32;not null"`
 	ActiveDrawingIndex int       `gorm:"not null;default:0"`
 	RevealStage        string    `gorm:"size:32;not null;default:''"`
+	ChainStep          int       `gorm:"not null;default:0"`
 	CreatedAt time.Time `gorm:"not null"`
 	UpdatedAt time.Time `gorm:"not null"`
 	Prompts   []Prompt
@@ -0,0 +1,16 @@
+package db
+
+import "time"
+
+// TitleGuess records a player typing the real title of the drawing they were
+// asked to write a decoy for.
+type TitleGuess struct {
+	ID           uint      `gorm:"primaryKey"`
+	GameID       uint      `gorm:"index;not null"`
+	RoundID      uint      `gorm:"index;not null;uniqueIndex:idx_title_guesses_round_player_drawing"`
+	PlayerID     uint      `gorm:"index;not null;uniqueIndex:idx_title_guesses_round_player_drawing"`
+	DrawingIndex int       `gorm:"not null;uniqueIndex:idx_title_guesses_round_player_drawing"`
+	Guess        string    `gorm:"size:280;not null"`
+	Correct      bool      `gorm:"not null;default:true"`
+	CreatedAt    time.Time `gorm:"not null"`
+}
@@ -0,0 +1,97 @@
+package game
+
+type AwardKind string
+
+const (
+	AwardMostLikedLie           AwardKind = "most_liked_lie"
+	AwardBestLiar               AwardKind = "best_liar"
+	AwardSharpestEye            AwardKind = "sharpest_eye"
+	AwardMostRecognisableArtist AwardKind = "most_recognisable_artist"
+)
+
+// Award is an end-of-game superlative. Count is the tally that won it and
+// Text carries the winning lie for AwardMostLikedLie.
+type Award struct {
+	Kind     AwardKind
+	Title    string
+	PlayerID int
+	Count    int
+	Text     string
+}
+
+// Awards picks the end-of-game superlatives. An award nobody earned is left
+// out; ties go to whoever joined first, or to the earlier lie.
+func Awards(state State) []Award {
+	fooled := map[int]int{}
+	correct := map[int]int{}
+	recognised := map[int]int{}
+	type lieKey struct{ round, drawing, owner int }
+	lieLikes := map[lieKey]int{}
+	var topLie lieKey
+	topLieLikes := 0
+	for roundIndex, round := range state.Rounds {
+		for _, vote := range round.Votes {
+			if vote.Correct {
+				correct[vote.PlayerID]++
+				if vote.DrawingIndex >= 0 && vote.DrawingIndex < len(round.Drawings) {
+					recognised[round.Drawings[vote.DrawingIndex].ArtistID]++
+				}
+				continue
+			}
+			if owner := lieOwner(round.Lies, vote.DrawingIndex, vote.ChoiceText); owner != 0 {
+				fooled[owner]++
+			}
+		}
+		for _, like := range round.Likes {
+			key := lieKey{roundIndex, like.DrawingIndex, like.OwnerID}
+			lieLikes[key]++
+			if lieLikes[key] > topLieLikes {
+				topLie, topLieLikes = key, lieLikes[key]
+			}
+		}
+	}
+
+	awards := make([]Award, 0, 4)
+	if topLieLikes > 0 {
+		awards = append(awards, Award{
+			Kind:     AwardMostLikedLie,
+			Title:    "Most liked lie",
+			PlayerID: topLie.owner,
+			Count:    topLieLikes,
+			Text:     lieText(state.Rounds[topLie.round].Lies, topLie.drawing, topLie.owner),
+		})
+	}
+	for _, candidate := range []struct {
+		kind   AwardKind
+		title  string
+		counts map[int]int
+	}{
+		{AwardBestLiar, "Best liar", fooled},
+		{AwardSharpestEye, "Sharpest eye", correct},
+		{AwardMostRecognisableArtist, "Most recognisable artist", recognised},
+	} {
+		if playerID, count := leader(state.Players, candidate.counts); count > 0 {
+			awards = append(awards, Award{Kind: candidate.kind, Title: candidate.title, PlayerID: playerID, Count: count})
+		}
+	}
+	return awards
+}
+
+func leader(players []int, counts map[int]int) (int, int) {
+	best, bestCount := 0, 0
+	for _, playerID := range players {
+		if counts[playerID] > bestCount {
+			best, bestCount = playerID, counts[playerID]
+		}
+	}
+	return best, bestCount
+}
+
+func lieText(lies []Lie, drawingIndex, playerID int) string {
+	for _, lie := range lies {
+		if lie.DrawingIndex == drawingIndex && lie.PlayerID == playerID {
+			return lie.Text
+		}
+	}
+	return ""
+}
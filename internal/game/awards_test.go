@@ -0,0 +1,46 @@
+package game
+
+import "testing"
+
+func TestAwardsPickLeaders(t *testing.T) {
+	state := State{Ruleset: RulesetDrawful, Players: []int{1, 2, 3, 4}, Rounds: []Round{{
+		Drawings: []Drawing{{ArtistID: 1}, {ArtistID: 2}},
+		Lies: []Lie{
+			{PlayerID: 3, DrawingIndex: 0, Text: "a sad moon"},
+			{PlayerID: 4, DrawingIndex: 1, Text: "soup tornado"},
+		},
+		Votes: []Vote{
+			{PlayerID: 2, DrawingIndex: 0, ChoiceText: "a sad moon"},
+			{PlayerID: 4, DrawingIndex: 0, ChoiceText: "a sad moon"},
+			{PlayerID: 3, DrawingIndex: 1, Correct: true},
+			{PlayerID: 1, DrawingIndex: 1, Correct: true},
+		},
+		Likes: []Like{
+			{PlayerID: 1, DrawingIndex: 1, OwnerID: 4},
+			{PlayerID: 2, DrawingIndex: 0, OwnerID: 3},
+			{PlayerID: 3, DrawingIndex: 1, OwnerID: 4},
+		},
+	}}}
+	got := Awards(state)
+	want := []Award{
+		{Kind: AwardMostLikedLie, Title: "Most liked lie", PlayerID: 4, Count: 2, Text: "soup tornado"},
+		{Kind: AwardBestLiar, Title: "Best liar", PlayerID: 3, Count: 2},
+		{Kind: AwardSharpestEye, Title: "Sharpest eye", PlayerID: 1, Count: 1},
+		{Kind: AwardMostRecognisableArtist, Title: "Most recognisable artist", PlayerID: 2, Count: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d awards, got %#v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("award %d: got %#v want %#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAwardsSkipUnearned(t *testing.T) {
+	state := State{Ruleset: RulesetDrawful, Players: []int{1, 2}, Rounds: []Round{{Drawings: []Drawing{{ArtistID: 1}}}}}
+	if got := Awards(state); len(got) != 0 {
+		t.Fatalf("expected no awards, got %#v", got)
+	}
+}
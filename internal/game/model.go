@@ -10,13 +10,19 @@ const (
 	PhaseResults  Phase = "results"
 	PhasePaused   Phase = "paused"
 	PhaseComplete Phase = "complete"
+
+	PhaseChainDraw     Phase = "chain-draw"
+	PhaseChainDescribe Phase = "chain-describe"
+	PhaseChainReveal   Phase = "chain-reveal"
 )
 
 type Ruleset string
 
 const (
-	RulesetLegacy  Ruleset = "picture_this_v1"
-	RulesetDrawful Ruleset = "drawful_v1"
+	RulesetLegacy    Ruleset = "picture_this_v1"
+	RulesetDrawful   Ruleset = "drawful_v1"
+	RulesetTeams     Ruleset = "teams_v1"
+	RulesetTelephone Ruleset = "telephone_v1"
 )
 
 type Drawing struct {
@@ -36,19 +42,52 @@ type Vote struct {
 	Correct      bool
 }
 
+// Like is a player liking the decoy title OwnerID wrote for a drawing.
+type Like struct {
+	PlayerID     int
+	DrawingIndex int
+	OwnerID      int
+}
+
+// TitleGuess is a player typing the real title of a drawing as their lie.
+type TitleGuess struct {
+	PlayerID     int
+	DrawingIndex int
+}
+
 type Round struct {
-	Drawings []Drawing
-	Lies     []Lie
-	Votes    []Vote
+	Drawings     []Drawing
+	Lies         []Lie
+	Votes        []Vote
+	Likes        []Like
+	TitleGuesses []TitleGuess
 }
 
 type State struct {
 	Ruleset Ruleset
 	Players []int
-	Rounds  []Round
+	// Teams maps player IDs to team numbers (1-based) in RulesetTeams.
+	Teams  map[int]int
+	Rounds []Round
+	Rules  ScoringRules
+	// TotalRounds is the planned round count; the last one uses the final
+	// round multiplier.
+	TotalRounds int
 }
 
 type Score struct {
 	PlayerID int
 	Points   int
+	// Likes counts likes received on the player's decoy titles.
+	Likes int
+}
+
+type TeamScore struct {
+	Team   int
+	Points int
+}
+
+type Scoreboard struct {
+	Players []Score
+	Teams   []TeamScore
 }
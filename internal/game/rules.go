@@ -9,12 +9,17 @@ func AdaptiveRounds(players int) int {
 	return 2
 }
 
-func Scores(state State) []Score {
+// Scores totals points per player and, in RulesetTeams, per team, using the
+// state's scoring rules.
+func Scores(state State) Scoreboard {
+	rules := state.Rules.Effective()
 	points := make(map[int]int, len(state.Players))
+	likes := make(map[int]int, len(state.Players))
 	for _, playerID := range state.Players {
 		points[playerID] = 0
 	}
-	for _, round := range state.Rounds {
+	for roundIndex, round := range state.Rounds {
+		multiplier := rules.RoundMultiplier(roundIndex, state.TotalRounds)
 		for drawingIndex, drawing := range round.Drawings {
 			fooled, correct := 0, 0
 			for _, vote := range round.Votes {
@@ -22,27 +27,36 @@ func Scores(state State) []Score {
 					continue
 				}
 				if vote.Correct {
-					points[vote.PlayerID] += 1000
+					points[vote.PlayerID] += rules.CorrectGuess * multiplier
 					correct++
 					continue
 				}
 				if owner := lieOwner(round.Lies, drawingIndex, vote.ChoiceText); owner != 0 {
-					points[owner] += 500
+					points[owner] += rules.FooledPlayer * multiplier
 					fooled++
 				}
 			}
-			if state.Ruleset == RulesetDrawful {
-				points[drawing.ArtistID] += 500 * correct
+			if state.Ruleset == RulesetDrawful || state.Ruleset == RulesetTeams {
+				points[drawing.ArtistID] += rules.ArtistBonus * correct * multiplier
 			} else if fooled == 0 {
-				points[drawing.ArtistID] += 1000
+				points[drawing.ArtistID] += rules.CorrectGuess * multiplier
 			} else {
-				points[drawing.ArtistID] += 500 * fooled
+				points[drawing.ArtistID] += rules.ArtistBonus * fooled * multiplier
 			}
 		}
+		if TitleGuessAllowed(state.Ruleset) {
+			for _, guess := range round.TitleGuesses {
+				points[guess.PlayerID] += rules.TitleGuessBonus * multiplier
+			}
+		}
+		for _, like := range round.Likes {
+			points[like.OwnerID] += rules.LikePoints * multiplier
+			likes[like.OwnerID]++
+		}
 	}
 	result := make([]Score, 0, len(state.Players))
 	for _, playerID := range state.Players {
-		result = append(result, Score{PlayerID: playerID, Points: points[playerID]})
+		result = append(result, Score{PlayerID: playerID, Points: points[playerID], Likes: likes[playerID]})
 	}
 	sort.SliceStable(result, func(i, j int) bool {
 		if result[i].Points == result[j].Points {
@@ -50,7 +64,11 @@ func Scores(state State) []Score {
 		}
 		return result[i].Points > result[j].Points
 	})
-	return result
+	board := Scoreboard{Players: result}
+	if state.Ruleset == RulesetTeams {
+		board.Teams = teamTotals(state.Teams, result)
+	}
+	return board
 }
 
 func lieOwner(lies []Lie, drawingIndex int, text string) int {
@@ -11,7 +11,7 @@ func TestScoresDrawful(t *testing.T) {
 			{PlayerID: 3, DrawingIndex: 0, ChoiceText: "lie"},
 		},
 	}}}
-	got := Scores(state)
+	got := Scores(state).Players
 	want := []Score{{PlayerID: 2, Points: 1500}, {PlayerID: 1, Points: 500}, {PlayerID: 3, Points: 0}}
 	for i := range want {
 		if got[i] != want[i] {
@@ -19,3 +19,54 @@ func TestScoresDrawful(t *testing.T) {
 		}
 	}
 }
+
+func TestScoresApplyCustomRules(t *testing.T) {
+	rules := ScoringRules{CorrectGuess: 300, FooledPlayer: 200, ArtistBonus: 100, FinalRoundMultiplier: 2, LikePoints: 50}
+	round := Round{
+		Drawings: []Drawing{{ArtistID: 1}},
+		Lies:     []Lie{{PlayerID: 2, DrawingIndex: 0, Text: "lie"}},
+		Votes: []Vote{
+			{PlayerID: 2, DrawingIndex: 0, Correct: true},
+			{PlayerID: 3, DrawingIndex: 0, ChoiceText: "lie"},
+		},
+		Likes: []Like{{PlayerID: 3, DrawingIndex: 0, OwnerID: 2}},
+	}
+	state := State{Ruleset: RulesetDrawful, Players: []int{1, 2, 3}, Rules: rules, TotalRounds: 2, Rounds: []Round{round, round}}
+	got := Scores(state).Players
+	// Round one scores once, the final round twice: 3x each round's points.
+	want := []Score{{PlayerID: 2, Points: 1650, Likes: 2}, {PlayerID: 1, Points: 300}, {PlayerID: 3, Points: 0}}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("score %d: got %#v want %#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestScoringRulesValidate(t *testing.T) {
+	if err := DefaultScoringRules().Validate(); err != nil {
+		t.Fatalf("defaults should be valid: %v", err)
+	}
+	if err := (ScoringRules{CorrectGuess: 1000, FinalRoundMultiplier: 0}).Validate(); err == nil {
+		t.Fatal("expected a zero multiplier to be rejected")
+	}
+	if err := (ScoringRules{CorrectGuess: -1, FinalRoundMultiplier: 1}).Validate(); err == nil {
+		t.Fatal("expected negative points to be rejected")
+	}
+	if (ScoringRules{}).Effective() != DefaultScoringRules() {
+		t.Fatal("expected unset rules to fall back to defaults")
+	}
+}
+
+func TestScoresTitleGuessBonus(t *testing.T) {
+	round := Round{Drawings: []Drawing{{ArtistID: 1}}, TitleGuesses: []TitleGuess{{PlayerID: 2, DrawingIndex: 0}}}
+	state := State{Ruleset: RulesetDrawful, Players: []int{1, 2}, Rounds: []Round{round}}
+	if got := Scores(state).Players[0]; got.PlayerID != 2 || got.Points != 250 {
+		t.Fatalf("expected title guess bonus, got %#v", got)
+	}
+	state.Ruleset = RulesetLegacy
+	for _, score := range Scores(state).Players {
+		if score.PlayerID == 2 && score.Points != 0 {
+			t.Fatalf("legacy ruleset should not award title guesses, got %#v", score)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package game
+
+import "errors"
+
+const (
+	MaxPointValue           = 10000
+	MaxFinalRoundMultiplier = 5
+)
+
+// ScoringRules are the point values a game is scored with. The artist bonus
+// is paid per correct vote in Drawful-style rulesets and per fooled vote in
+// the legacy ruleset, where an artist nobody was fooled on earns CorrectGuess
+// instead. TitleGuessBonus rewards typing the real title as a lie in rulesets
+// that allow it.
+type ScoringRules struct {
+	CorrectGuess         int `json:"correct_guess"`
+	FooledPlayer         int `json:"fooled_player"`
+	ArtistBonus          int `json:"artist_bonus"`
+	FinalRoundMultiplier int `json:"final_round_multiplier"`
+	LikePoints           int `json:"like_points"`
+	TitleGuessBonus      int `json:"title_guess_bonus"`
+}
+
+func DefaultScoringRules() ScoringRules {
+	return ScoringRules{
+		CorrectGuess:         1000,
+		FooledPlayer:         500,
+		ArtistBonus:          500,
+		FinalRoundMultiplier: 1,
+		TitleGuessBonus:      250,
+	}
+}
+
+// TitleGuessAllowed reports whether a lie matching the real title is kept as a
+// correct guess (and scored) rather than just rejected.
+func TitleGuessAllowed(ruleset Ruleset) bool {
+	return ruleset == RulesetDrawful || ruleset == RulesetTeams
+}
+
+// Effective returns the rules to score with; games recorded before rules were
+// configurable carry the zero value and score with the defaults.
+func (r ScoringRules) Effective() ScoringRules {
+	if r == (ScoringRules{}) {
+		return DefaultScoringRules()
+	}
+	return r
+}
+
+func (r ScoringRules) Validate() error {
+	for _, points := range []int{r.CorrectGuess, r.FooledPlayer, r.ArtistBonus, r.LikePoints, r.TitleGuessBonus} {
+		if points < 0 || points > MaxPointValue {
+			return errors.New("point values must be between 0 and 10000")
+		}
+	}
+	if r.FinalRoundMultiplier < 1 || r.FinalRoundMultiplier > MaxFinalRoundMultiplier {
+		return errors.New("final round multiplier must be between 1 and 5")
+	}
+	return nil
+}
+
+// RoundMultiplier is the factor applied to every point earned in the given
+// zero-based round of a game with totalRounds rounds.
+func (r ScoringRules) RoundMultiplier(roundIndex, totalRounds int) int {
+	rules := r.Effective()
+	if totalRounds > 0 && roundIndex == totalRounds-1 {
+		return rules.FinalRoundMultiplier
+	}
+	return 1
+}
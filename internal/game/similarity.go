@@ -0,0 +1,92 @@
+package game
+
+import (
+	"strings"
+	"unicode"
+)
+
+var titleStopWords = map[string]struct{}{"a": {}, "an": {}, "the": {}}
+
+// NormalizeTitle reduces a title to the words that matter for comparison:
+// lowercased, without punctuation or articles, with each word stemmed.
+func NormalizeTitle(title string) string {
+	fields := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if _, ok := titleStopWords[field]; ok {
+			continue
+		}
+		words = append(words, stemWord(field))
+	}
+	return strings.Join(words, " ")
+}
+
+// stemWord strips common English inflections so "dogs dancing" and "dog
+// danced" compare equal. It is deliberately crude; edit distance covers what
+// it misses.
+func stemWord(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 5 && strings.HasSuffix(word, "ing"):
+		return word[:len(word)-3]
+	case len(word) > 4 && strings.HasSuffix(word, "ed"):
+		return word[:len(word)-2]
+	case len(word) > 4 && (strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes") || strings.HasSuffix(word, "xes")):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// EditDistance is the Levenshtein distance between a and b in runes.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// TitlesMatch reports whether two titles are close enough that voters could
+// not tell them apart: equal after normalization, or within a small edit
+// distance that grows with the title's length. Spacing is ignored so "ice
+// cream" matches "icecream".
+func TitlesMatch(a, b string) bool {
+	na := strings.ReplaceAll(NormalizeTitle(a), " ", "")
+	nb := strings.ReplaceAll(NormalizeTitle(b), " ", "")
+	if na == nb {
+		return true
+	}
+	if na == "" || nb == "" {
+		return false
+	}
+	return EditDistance(na, nb) <= titleTolerance(min(len([]rune(na)), len([]rune(nb))))
+}
+
+func titleTolerance(length int) int {
+	switch {
+	case length < 5:
+		return 0
+	case length < 12:
+		return 1
+	default:
+		return 2
+	}
+}
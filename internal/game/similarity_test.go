@@ -0,0 +1,32 @@
+package game
+
+import "testing"
+
+func TestTitlesMatch(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"A cat in a hat", "cat in hat!", true},
+		{"Dogs dancing", "the dog danced", true},
+		{"Ice cream", "icecream", true},
+		{"Haunted lighthouse", "haunted lighthuse", true},
+		{"Haunted lighthouse", "haunted house", false},
+		{"cat", "bat", false},
+		{"Moon landing", "Sun landing", false},
+	}
+	for _, tc := range cases {
+		if got := TitlesMatch(tc.a, tc.b); got != tc.want {
+			t.Errorf("TitlesMatch(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	if got := EditDistance("kitten", "sitting"); got != 3 {
+		t.Fatalf("expected 3, got %d", got)
+	}
+	if got := EditDistance("", "abc"); got != 3 {
+		t.Fatalf("expected 3, got %d", got)
+	}
+}
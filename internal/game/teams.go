@@ -0,0 +1,98 @@
+package game
+
+import "sort"
+
+const (
+	DefaultTeamCount = 2
+	MaxTeams         = 4
+)
+
+// BalanceTeams assigns every player to one of teamCount teams. Existing
+// assignments are kept where possible; newcomers join the smallest team and
+// the most recent joiners move off the largest team until sizes differ by at
+// most one. playerIDs must be in join order.
+func BalanceTeams(playerIDs []int, current map[int]int, teamCount int) map[int]int {
+	if teamCount < 2 {
+		teamCount = DefaultTeamCount
+	}
+	if teamCount > MaxTeams {
+		teamCount = MaxTeams
+	}
+	teams := make(map[int]int, len(playerIDs))
+	members := make([][]int, teamCount+1)
+	var unassigned []int
+	for _, playerID := range playerIDs {
+		team := current[playerID]
+		if team < 1 || team > teamCount {
+			unassigned = append(unassigned, playerID)
+			continue
+		}
+		teams[playerID] = team
+		members[team] = append(members[team], playerID)
+	}
+	for _, playerID := range unassigned {
+		team := smallestTeam(members)
+		teams[playerID] = team
+		members[team] = append(members[team], playerID)
+	}
+	for {
+		small, large := smallestTeam(members), largestTeam(members)
+		if len(members[large])-len(members[small]) <= 1 {
+			break
+		}
+		moved := members[large][len(members[large])-1]
+		members[large] = members[large][:len(members[large])-1]
+		members[small] = append(members[small], moved)
+		teams[moved] = small
+	}
+	return teams
+}
+
+// SameTeam reports whether two distinct players share a team.
+func SameTeam(teams map[int]int, a, b int) bool {
+	if a == b {
+		return false
+	}
+	team, ok := teams[a]
+	return ok && team != 0 && teams[b] == team
+}
+
+func smallestTeam(members [][]int) int {
+	best := 1
+	for team := 2; team < len(members); team++ {
+		if len(members[team]) < len(members[best]) {
+			best = team
+		}
+	}
+	return best
+}
+
+func largestTeam(members [][]int) int {
+	best := 1
+	for team := 2; team < len(members); team++ {
+		if len(members[team]) > len(members[best]) {
+			best = team
+		}
+	}
+	return best
+}
+
+func teamTotals(teams map[int]int, scores []Score) []TeamScore {
+	points := map[int]int{}
+	for _, score := range scores {
+		if team := teams[score.PlayerID]; team != 0 {
+			points[team] += score.Points
+		}
+	}
+	result := make([]TeamScore, 0, len(points))
+	for team, total := range points {
+		result = append(result, TeamScore{Team: team, Points: total})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Points == result[j].Points {
+			return result[i].Team < result[j].Team
+		}
+		return result[i].Points > result[j].Points
+	})
+	return result
+}
@@ -0,0 +1,42 @@
+package game
+
+import "testing"
+
+func TestBalanceTeamsKeepsAssignmentsAndEvensSizes(t *testing.T) {
+	got := BalanceTeams([]int{1, 2, 3, 4, 5}, map[int]int{1: 1, 2: 1, 3: 1, 4: 1}, 2)
+	want := map[int]int{1: 1, 2: 1, 3: 1, 4: 2, 5: 2}
+	for playerID, team := range want {
+		if got[playerID] != team {
+			t.Fatalf("player %d: got team %d want %d (%v)", playerID, got[playerID], team, got)
+		}
+	}
+}
+
+func TestScoresTeams(t *testing.T) {
+	state := State{
+		Ruleset: RulesetTeams,
+		Players: []int{1, 2, 3, 4},
+		Teams:   map[int]int{1: 1, 2: 1, 3: 2, 4: 2},
+		Rounds: []Round{{
+			Drawings: []Drawing{{ArtistID: 1}},
+			Lies:     []Lie{{PlayerID: 3, DrawingIndex: 0, Text: "lie"}},
+			Votes: []Vote{
+				{PlayerID: 2, DrawingIndex: 0, Correct: true},
+				{PlayerID: 4, DrawingIndex: 0, Correct: true},
+			},
+		}},
+	}
+	got := Scores(state)
+	want := []TeamScore{{Team: 1, Points: 2000}, {Team: 2, Points: 1000}}
+	if len(got.Teams) != len(want) {
+		t.Fatalf("got %d teams want %d", len(got.Teams), len(want))
+	}
+	for i := range want {
+		if got.Teams[i] != want[i] {
+			t.Fatalf("team %d: got %#v want %#v", i, got.Teams[i], want[i])
+		}
+	}
+	if len(got.Players) != 4 {
+		t.Fatalf("expected individual scores alongside team totals, got %#v", got.Players)
+	}
+}
@@ -0,0 +1,46 @@
+package game
+
+type LinkKind string
+
+const (
+	LinkDrawing     LinkKind = "drawing"
+	LinkDescription LinkKind = "description"
+)
+
+// In the telephone ruleset every player starts a chain from their own prompt.
+// Chains rotate one seat per step and alternate between drawing the previous
+// description and describing the previous drawing, so each player adds exactly
+// one link to every chain.
+
+// ChainSteps returns how many links each chain collects.
+func ChainSteps(players int) int {
+	if players < 0 {
+		return 0
+	}
+	return players
+}
+
+// ChainStepKind returns what players produce at a step; chains open with a
+// drawing of the original prompt.
+func ChainStepKind(step int) LinkKind {
+	if step%2 == 0 {
+		return LinkDrawing
+	}
+	return LinkDescription
+}
+
+// ChainHolder returns the seat that adds the step's link to a chain.
+func ChainHolder(chain, step, players int) int {
+	if players <= 0 {
+		return -1
+	}
+	return (chain + step) % players
+}
+
+// ChainForSeat returns the chain a seat works on at a step.
+func ChainForSeat(seat, step, players int) int {
+	if players <= 0 {
+		return -1
+	}
+	return ((seat-step)%players + players) % players
+}
@@ -0,0 +1,23 @@
+package game
+
+import "testing"
+
+func TestChainRotationVisitsEverySeatOnce(t *testing.T) {
+	const players = 5
+	for chain := 0; chain < players; chain++ {
+		seen := map[int]bool{}
+		for step := 0; step < ChainSteps(players); step++ {
+			seat := ChainHolder(chain, step, players)
+			if seen[seat] {
+				t.Fatalf("chain %d visits seat %d twice", chain, seat)
+			}
+			seen[seat] = true
+			if got := ChainForSeat(seat, step, players); got != chain {
+				t.Fatalf("seat %d step %d: got chain %d want %d", seat, step, got, chain)
+			}
+		}
+	}
+	if ChainStepKind(0) != LinkDrawing || ChainStepKind(1) != LinkDescription {
+		t.Fatal("chains should alternate starting with a drawing")
+	}
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	MinPhaseSeconds = 5
+	MaxPhaseSeconds = 600
+)
+
+const (
+	TimerPresetStandard = "standard"
+	TimerPresetFamily   = "family"
+	TimerPresetSpeed    = "speed"
+	TimerPresetCustom   = "custom"
+)
+
+// TimerSettings are a game's phase durations in seconds. A zero duration
+// keeps the server's configured one, so the standard preset is all zeros.
+// RevealSeconds covers every reveal step except joke narration, which runs
+// as long as its audio.
+type TimerSettings struct {
+	Preset        string `json:"preset"`
+	DrawSeconds   int    `json:"draw_seconds"`
+	GuessSeconds  int    `json:"guess_seconds"`
+	VoteSeconds   int    `json:"vote_seconds"`
+	RevealSeconds int    `json:"reveal_seconds"`
+}
+
+var timerPresets = map[string]TimerSettings{
+	TimerPresetStandard: {Preset: TimerPresetStandard},
+	TimerPresetFamily:   {Preset: TimerPresetFamily, DrawSeconds: 150, GuessSeconds: 90, VoteSeconds: 60, RevealSeconds: 8},
+	TimerPresetSpeed:    {Preset: TimerPresetSpeed, DrawSeconds: 45, GuessSeconds: 30, VoteSeconds: 20, RevealSeconds: 5},
+}
+
+// TimerPresetNames lists the presets in the order hosts are offered them.
+func TimerPresetNames() []string {
+	return []string{TimerPresetFamily, TimerPresetStandard, TimerPresetSpeed}
+}
+
+// Resolve fills in a named preset's durations. Settings without a preset, or
+// marked custom, keep their own durations.
+func (t TimerSettings) Resolve() (TimerSettings, error) {
+	switch t.Preset {
+	case "", TimerPresetCustom:
+		if t == (TimerSettings{}) {
+			return timerPresets[TimerPresetStandard], nil
+		}
+		t.Preset = TimerPresetCustom
+		return t, t.Validate()
+	}
+	preset, ok := timerPresets[t.Preset]
+	if !ok {
+		return TimerSettings{}, errors.New("unknown timer preset")
+	}
+	return preset, nil
+}
+
+func (t TimerSettings) Validate() error {
+	for _, seconds := range []int{t.DrawSeconds, t.GuessSeconds, t.VoteSeconds, t.RevealSeconds} {
+		if seconds != 0 && (seconds < MinPhaseSeconds || seconds > MaxPhaseSeconds) {
+			return fmt.Errorf("phase timers must be between %d and %d seconds", MinPhaseSeconds, MaxPhaseSeconds)
+		}
+	}
+	return nil
+}
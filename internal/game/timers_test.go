@@ -0,0 +1,28 @@
+package game
+
+import "testing"
+
+func TestTimerSettingsResolve(t *testing.T) {
+	speed, err := TimerSettings{Preset: TimerPresetSpeed, DrawSeconds: 500}.Resolve()
+	if err != nil || speed != timerPresets[TimerPresetSpeed] {
+		t.Fatalf("expected a preset to override custom durations, got %#v (%v)", speed, err)
+	}
+	if standard, err := (TimerSettings{}).Resolve(); err != nil || standard.Preset != TimerPresetStandard || standard.DrawSeconds != 0 {
+		t.Fatalf("expected empty settings to be the standard preset, got %#v (%v)", standard, err)
+	}
+	custom, err := TimerSettings{DrawSeconds: 120}.Resolve()
+	if err != nil || custom.Preset != TimerPresetCustom || custom.DrawSeconds != 120 || custom.GuessSeconds != 0 {
+		t.Fatalf("expected custom durations to be kept, got %#v (%v)", custom, err)
+	}
+	if _, err := (TimerSettings{Preset: "glacial"}).Resolve(); err == nil {
+		t.Fatal("expected an unknown preset to be rejected")
+	}
+	if _, err := (TimerSettings{Preset: TimerPresetCustom, VoteSeconds: 2}).Resolve(); err == nil {
+		t.Fatal("expected a too-short timer to be rejected")
+	}
+	for _, name := range TimerPresetNames() {
+		if err := timerPresets[name].Validate(); err != nil {
+			t.Fatalf("preset %s is invalid: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Package jsonpatch computes and applies RFC 6902 JSON Patches between decoded
+// JSON documents, the values encoding/json produces when unmarshalling into
+// an any: maps, slices, strings, float64s, bools and nil.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// Operation is one patch step. Value is ignored for removals.
+type Operation struct {
+	Op    string
+	Path  string
+	Value any
+}
+
+func (o Operation) MarshalJSON() ([]byte, error) {
+	if o.Op == OpRemove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*o = Operation{Op: raw.Op, Path: raw.Path, Value: raw.Value}
+	return nil
+}
+
+// Normalize round-trips v through encoding/json so it can be diffed.
+func Normalize(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Diff returns the operations that turn from into to. Objects are compared
+// key by key and arrays index by index, with items added or removed at the
+// end; anything else that differs is replaced whole. Keys are visited in
+// sorted order so the same inputs always give the same patch.
+func Diff(from, to any) []Operation {
+	var ops []Operation
+	diff(&ops, "", from, to)
+	return ops
+}
+
+func diff(ops *[]Operation, path string, from, to any) {
+	switch a := from.(type) {
+	case map[string]any:
+		b, ok := to.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, key := range sortedKeys(a) {
+			if _, ok := b[key]; !ok {
+				*ops = append(*ops, Operation{Op: OpRemove, Path: path + "/" + escape(key)})
+			}
+		}
+		for _, key := range sortedKeys(b) {
+			value, ok := a[key]
+			if !ok {
+				*ops = append(*ops, Operation{Op: OpAdd, Path: path + "/" + escape(key), Value: b[key]})
+				continue
+			}
+			diff(ops, path+"/"+escape(key), value, b[key])
+		}
+		return
+	case []any:
+		b, ok := to.([]any)
+		if !ok {
+			break
+		}
+		shared := min(len(a), len(b))
+		for i := 0; i < shared; i++ {
+			diff(ops, path+"/"+strconv.Itoa(i), a[i], b[i])
+		}
+		for i := len(a) - 1; i >= len(b); i-- {
+			*ops = append(*ops, Operation{Op: OpRemove, Path: path + "/" + strconv.Itoa(i)})
+		}
+		for i := len(a); i < len(b); i++ {
+			*ops = append(*ops, Operation{Op: OpAdd, Path: path + "/-", Value: b[i]})
+		}
+		return
+	}
+	if !reflect.DeepEqual(from, to) {
+		*ops = append(*ops, Operation{Op: OpReplace, Path: path, Value: to})
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// Apply returns doc with ops applied. doc is modified in place where
+// possible, so callers that need the original should pass a copy.
+func Apply(doc any, ops []Operation) (any, error) {
+	for _, op := range ops {
+		var err error
+		doc, err = apply(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+func apply(doc any, op Operation) (any, error) {
+	if op.Path == "" {
+		if op.Op == OpRemove {
+			return nil, nil
+		}
+		return op.Value, nil
+	}
+	if !strings.HasPrefix(op.Path, "/") {
+		return nil, errors.New("path must start with /")
+	}
+	tokens := strings.Split(op.Path[1:], "/")
+	for i := range tokens {
+		tokens[i] = unescape(tokens[i])
+	}
+	return update(doc, tokens, op)
+}
+
+// update applies op at tokens inside node and returns the new node, so that
+// slices can grow or shrink on the way back up.
+func update(node any, tokens []string, op Operation) (any, error) {
+	last := len(tokens) == 1
+	switch n := node.(type) {
+	case map[string]any:
+		key := tokens[0]
+		if !last {
+			child, ok := n[key]
+			if !ok {
+				return nil, errors.New("path not found")
+			}
+			updated, err := update(child, tokens[1:], op)
+			if err != nil {
+				return nil, err
+			}
+			n[key] = updated
+			return n, nil
+		}
+		switch op.Op {
+		case OpAdd:
+			n[key] = op.Value
+		case OpReplace:
+			if _, ok := n[key]; !ok {
+				return nil, errors.New("path not found")
+			}
+			n[key] = op.Value
+		case OpRemove:
+			if _, ok := n[key]; !ok {
+				return nil, errors.New("path not found")
+			}
+			delete(n, key)
+		default:
+			return nil, errors.New("unsupported op")
+		}
+		return n, nil
+	case []any:
+		if last && op.Op == OpAdd && tokens[0] == "-" {
+			return append(n, op.Value), nil
+		}
+		index, err := strconv.Atoi(tokens[0])
+		if err != nil || index < 0 || index > len(n) || (index == len(n) && !(last && op.Op == OpAdd)) {
+			return nil, errors.New("index out of range")
+		}
+		if !last {
+			updated, err := update(n[index], tokens[1:], op)
+			if err != nil {
+				return nil, err
+			}
+			n[index] = updated
+			return n, nil
+		}
+		switch op.Op {
+		case OpAdd:
+			n = append(n, nil)
+			copy(n[index+1:], n[index:])
+			n[index] = op.Value
+		case OpReplace:
+			n[index] = op.Value
+		case OpRemove:
+			n = append(n[:index], n[index+1:]...)
+		default:
+			return nil, errors.New("unsupported op")
+		}
+		return n, nil
+	}
+	return nil, errors.New("path not found")
+}
@@ -0,0 +1,80 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func decode(t *testing.T, text string) any {
+	t.Helper()
+	var out any
+	if err := json.Unmarshal([]byte(text), &out); err != nil {
+		t.Fatalf("decode %s: %v", text, err)
+	}
+	return out
+}
+
+func TestDiffRoundTrips(t *testing.T) {
+	cases := []struct{ from, to string }{
+		{`{"a":1}`, `{"a":1}`},
+		{`{"a":1,"b":"x"}`, `{"a":2,"c":null}`},
+		{`{"list":[1,2,3]}`, `{"list":[1,5]}`},
+		{`{"list":[1]}`, `{"list":[1,{"k":true},[2]]}`},
+		{`{"nested":{"a/b":{"~c":1}}}`, `{"nested":{"a/b":{"~c":2}}}`},
+		{`{"kind":[1,2]}`, `{"kind":{"0":1}}`},
+		{`{"phase":"lobby","players":["Ada"]}`, `{"phase":"drawings","players":["Ada","Ben"],"round":1}`},
+		{`[1,2]`, `"scalar"`},
+	}
+	for _, tc := range cases {
+		from, to := decode(t, tc.from), decode(t, tc.to)
+		ops := Diff(from, to)
+		encoded, err := json.Marshal(ops)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		var decoded []Operation
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		got, err := Apply(decode(t, tc.from), decoded)
+		if err != nil {
+			t.Fatalf("apply %s -> %s: %v (ops %s)", tc.from, tc.to, err, encoded)
+		}
+		if !reflect.DeepEqual(got, to) {
+			t.Fatalf("apply %s -> %s gave %v (ops %s)", tc.from, tc.to, got, encoded)
+		}
+		if tc.from == tc.to && len(ops) != 0 {
+			t.Fatalf("expected no ops for equal documents, got %s", encoded)
+		}
+	}
+}
+
+func TestDiffIsMinimalForSmallChanges(t *testing.T) {
+	from := decode(t, `{"phase":"guesses","drawings":[{"image":"big-1"},{"image":"big-2"}],"votes":0}`)
+	to := decode(t, `{"phase":"guesses","drawings":[{"image":"big-1"},{"image":"big-2"}],"votes":1}`)
+	encoded, _ := json.Marshal(Diff(from, to))
+	if string(encoded) != `[{"op":"replace","path":"/votes","value":1}]` {
+		t.Fatalf("unexpected patch %s", encoded)
+	}
+}
+
+func TestOperationKeepsNullValues(t *testing.T) {
+	encoded, _ := json.Marshal([]Operation{{Op: OpAdd, Path: "/a", Value: nil}, {Op: OpRemove, Path: "/b"}})
+	if string(encoded) != `[{"op":"add","path":"/a","value":null},{"op":"remove","path":"/b"}]` {
+		t.Fatalf("unexpected encoding %s", encoded)
+	}
+}
+
+func TestApplyRejectsBadPaths(t *testing.T) {
+	doc := decode(t, `{"list":[1]}`)
+	for _, op := range []Operation{
+		{Op: OpReplace, Path: "/missing", Value: 1},
+		{Op: OpRemove, Path: "/list/3"},
+		{Op: OpAdd, Path: "list", Value: 1},
+	} {
+		if _, err := Apply(doc, []Operation{op}); err == nil {
+			t.Fatalf("expected %v to fail", op)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package poster
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+func fillRect(img *image.RGBA, rect image.Rectangle, paint color.RGBA) {
+	draw.Draw(img, rect, image.NewUniform(paint), image.Point{}, draw.Src)
+}
+
+// fillRoundedRect fills rect with antialiased corners of the given radius.
+func fillRoundedRect(img *image.RGBA, rect image.Rectangle, radius int, paint color.RGBA) {
+	mask := image.NewAlpha(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	r := float64(radius)
+	w, h := float64(rect.Dx()), float64(rect.Dy())
+	for y := 0; y < rect.Dy(); y++ {
+		for x := 0; x < rect.Dx(); x++ {
+			// Distance from the pixel centre to the nearest corner centre,
+			// clamped so the straight edges are fully covered.
+			cx := math.Max(r, math.Min(w-r, float64(x)+0.5))
+			cy := math.Max(r, math.Min(h-r, float64(y)+0.5))
+			distance := math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy)
+			mask.Pix[y*mask.Stride+x] = coverage(r - distance)
+		}
+	}
+	draw.DrawMask(img, rect, image.NewUniform(paint), image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// circleMask is an antialiased disc filling a size×size square.
+func circleMask(size int) *image.Alpha {
+	mask := image.NewAlpha(image.Rect(0, 0, size, size))
+	radius := float64(size) / 2
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			distance := math.Hypot(float64(x)+0.5-radius, float64(y)+0.5-radius)
+			mask.Pix[y*mask.Stride+x] = coverage(radius - distance)
+		}
+	}
+	return mask
+}
+
+// coverage converts a signed distance inside an edge to an alpha value,
+// blending over one pixel.
+func coverage(inside float64) uint8 {
+	return uint8(math.Max(0, math.Min(1, inside+0.5)) * 255)
+}
+
+// fitRect is the largest rectangle with the aspect ratio of src that fits
+// centred inside frame.
+func fitRect(src, frame image.Rectangle) image.Rectangle {
+	if src.Dx() <= 0 || src.Dy() <= 0 {
+		return image.Rectangle{}
+	}
+	scale := math.Min(float64(frame.Dx())/float64(src.Dx()), float64(frame.Dy())/float64(src.Dy()))
+	w := int(math.Round(float64(src.Dx()) * scale))
+	h := int(math.Round(float64(src.Dy()) * scale))
+	x := frame.Min.X + (frame.Dx()-w)/2
+	y := frame.Min.Y + (frame.Dy()-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// drawScaled resamples src into rect, averaging the source pixels under each
+// destination pixel when shrinking and picking the nearest when growing.
+func drawScaled(dst *image.RGBA, rect image.Rectangle, src image.Image) {
+	rect = rect.Intersect(dst.Bounds())
+	bounds := src.Bounds()
+	if rect.Empty() || bounds.Empty() {
+		return
+	}
+	sx := float64(bounds.Dx()) / float64(rect.Dx())
+	sy := float64(bounds.Dy()) / float64(rect.Dy())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		y0 := bounds.Min.Y + int(float64(y-rect.Min.Y)*sy)
+		y1 := max(y0+1, bounds.Min.Y+int(float64(y-rect.Min.Y+1)*sy))
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			x0 := bounds.Min.X + int(float64(x-rect.Min.X)*sx)
+			x1 := max(x0+1, bounds.Min.X+int(float64(x-rect.Min.X+1)*sx))
+			var r, g, b, a, n uint32
+			for yy := y0; yy < min(y1, bounds.Max.Y); yy++ {
+				for xx := x0; xx < min(x1, bounds.Max.X); xx++ {
+					cr, cg, cb, ca := src.At(xx, yy).RGBA()
+					r, g, b, a, n = r+cr, g+cg, b+cb, a+ca, n+1
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			pixel := color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)}
+			dst.Set(x, y, blend(dst.RGBAAt(x, y), pixel))
+		}
+	}
+}
+
+// blend composites a premultiplied source pixel over dst.
+func blend(dst color.RGBA, src color.RGBA64) color.RGBA {
+	inverse := 0xffff - uint32(src.A)
+	mix := func(d uint8, s uint16) uint8 {
+		return uint8((uint32(s) + uint32(d)*0x101*inverse/0xffff) >> 8)
+	}
+	return color.RGBA{R: mix(dst.R, src.R), G: mix(dst.G, src.G), B: mix(dst.B, src.B), A: mix(dst.A, src.A)}
+}
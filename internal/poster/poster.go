@@ -0,0 +1,270 @@
+// Package poster renders the end-of-game poster: a yearbook-style PNG with
+// every drawing, its prompt and best lie, the artist, and the final scores.
+// Text is set in the Go fonts through golang.org/x/image/font.
+package poster
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+type Poster struct {
+	Title    string
+	Subtitle string
+	Sections []Section
+	Scores   []Score
+}
+
+// Section groups drawings under a heading, such as one round.
+type Section struct {
+	Title    string
+	Drawings []Drawing
+}
+
+type Drawing struct {
+	// Image is the drawing as a PNG.
+	Image  []byte
+	Prompt string
+	// Caption is a line under the prompt, such as the lie that fooled the
+	// most players.
+	Caption string
+	Artist  Player
+}
+
+type Player struct {
+	Name string
+	// Color is the player's #rrggbb colour.
+	Color string
+	// Avatar is an optional PNG.
+	Avatar []byte
+}
+
+type Score struct {
+	Player Player
+	Points int
+}
+
+const (
+	width      = 1200
+	margin     = 48
+	gutter     = 24
+	columns    = 3
+	cardWidth  = (width - 2*margin - (columns-1)*gutter) / columns
+	cardInset  = 14
+	imageWidth = cardWidth - 2*cardInset
+	// Drawings are letterboxed into a 4:3 frame.
+	imageHeight = imageWidth * 3 / 4
+	avatarSize  = 32
+	scoreRow    = 52
+
+	// MaxDrawings bounds the poster's height.
+	MaxDrawings = 64
+)
+
+var (
+	paper     = color.RGBA{0xf7, 0xf1, 0xe3, 0xff}
+	ink       = color.RGBA{0x2b, 0x2d, 0x42, 0xff}
+	muted     = color.RGBA{0x6c, 0x6f, 0x7f, 0xff}
+	cardColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	rule      = color.RGBA{0xe4, 0xdc, 0xc8, 0xff}
+)
+
+var loadFonts = sync.OnceValues(func() ([2]*opentype.Font, error) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return [2]*opentype.Font{}, fmt.Errorf("regular font: %w", err)
+	}
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return [2]*opentype.Font{}, fmt.Errorf("bold font: %w", err)
+	}
+	return [2]*opentype.Font{regular, bold}, nil
+})
+
+type faces struct {
+	title, subtitle, heading, prompt, caption, name, score *Face
+}
+
+// Render draws the poster and encodes it as a PNG.
+func Render(p Poster) ([]byte, error) {
+	fonts, err := loadFonts()
+	if err != nil {
+		return nil, err
+	}
+	regular, bold := fonts[0], fonts[1]
+	var f faces
+	for _, spec := range []struct {
+		face **Face
+		font *opentype.Font
+		size float64
+	}{
+		{&f.title, bold, 44},
+		{&f.subtitle, regular, 18},
+		{&f.heading, bold, 26},
+		{&f.prompt, bold, 18},
+		{&f.caption, regular, 15},
+		{&f.name, bold, 15},
+		{&f.score, bold, 20},
+	} {
+		if *spec.face, err = NewFace(spec.font, spec.size); err != nil {
+			return nil, err
+		}
+	}
+	cardHeight := cardInset + imageHeight + 10 + 2*f.prompt.LineHeight() + 4 + 2*f.caption.LineHeight() + 8 + avatarSize + cardInset
+
+	sections := limitDrawings(p.Sections, MaxDrawings)
+	headerHeight := 150
+	height := headerHeight
+	for _, section := range sections {
+		rows := (len(section.Drawings) + columns - 1) / columns
+		height += 24 + f.heading.LineHeight() + 12 + rows*(cardHeight+gutter)
+	}
+	if len(p.Scores) > 0 {
+		height += 24 + f.heading.LineHeight() + 12 + len(p.Scores)*scoreRow
+	}
+	height += margin
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fillRect(img, img.Bounds(), paper)
+	fillRect(img, image.Rect(0, 0, width, headerHeight-30), ink)
+	f.title.Draw(img, margin, 28, f.title.Fit(p.Title, width-2*margin), paper)
+	f.subtitle.Draw(img, margin, 28+f.title.LineHeight()+4, f.subtitle.Fit(p.Subtitle, width-2*margin), rule)
+
+	y := headerHeight
+	for _, section := range sections {
+		y += 24
+		f.heading.Draw(img, margin, y, f.heading.Fit(section.Title, width-2*margin), ink)
+		y += f.heading.LineHeight() + 12
+		for i, drawing := range section.Drawings {
+			x := margin + (i%columns)*(cardWidth+gutter)
+			top := y + (i/columns)*(cardHeight+gutter)
+			drawCard(img, f, image.Rect(x, top, x+cardWidth, top+cardHeight), drawing)
+		}
+		y += (len(section.Drawings) + columns - 1) / columns * (cardHeight + gutter)
+	}
+	if len(p.Scores) > 0 {
+		y += 24
+		f.heading.Draw(img, margin, y, "Final scores", ink)
+		y += f.heading.LineHeight() + 12
+		drawScores(img, f, y, p.Scores)
+	}
+
+	var out bytes.Buffer
+	if err := png.Encode(&out, img); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func limitDrawings(sections []Section, limit int) []Section {
+	out := make([]Section, 0, len(sections))
+	for _, section := range sections {
+		if limit <= 0 {
+			break
+		}
+		if len(section.Drawings) > limit {
+			section.Drawings = section.Drawings[:limit]
+		}
+		limit -= len(section.Drawings)
+		out = append(out, section)
+	}
+	return out
+}
+
+func drawCard(img *image.RGBA, f faces, card image.Rectangle, drawing Drawing) {
+	fillRoundedRect(img, card, 14, cardColor)
+	frame := image.Rect(card.Min.X+cardInset, card.Min.Y+cardInset, card.Max.X-cardInset, card.Min.Y+cardInset+imageHeight)
+	fillRect(img, frame, paper)
+	if picture, err := png.Decode(bytes.NewReader(drawing.Image)); err == nil {
+		drawScaled(img, fitRect(picture.Bounds(), frame), picture)
+	}
+
+	textWidth := cardWidth - 2*cardInset
+	y := frame.Max.Y + 10
+	for _, line := range f.prompt.Wrap(drawing.Prompt, textWidth, 2) {
+		f.prompt.Draw(img, frame.Min.X, y, line, ink)
+		y += f.prompt.LineHeight()
+	}
+	y = frame.Max.Y + 10 + 2*f.prompt.LineHeight() + 4
+	for _, line := range f.caption.Wrap(drawing.Caption, textWidth, 2) {
+		f.caption.Draw(img, frame.Min.X, y, line, muted)
+		y += f.caption.LineHeight()
+	}
+
+	top := card.Max.Y - cardInset - avatarSize
+	drawAvatar(img, f, image.Rect(frame.Min.X, top, frame.Min.X+avatarSize, top+avatarSize), drawing.Artist)
+	name := f.name.Fit(drawing.Artist.Name, textWidth-avatarSize-10)
+	f.name.Draw(img, frame.Min.X+avatarSize+10, top+(avatarSize-f.name.LineHeight())/2, name, ink)
+}
+
+func drawScores(img *image.RGBA, f faces, y int, scores []Score) {
+	best := 1
+	for _, score := range scores {
+		best = max(best, score.Points)
+	}
+	nameWidth := 320
+	barLeft := margin + 48 + 44 + nameWidth
+	barWidth := width - margin - barLeft - 100
+	for i, score := range scores {
+		top := y + i*scoreRow
+		if i%2 == 0 {
+			fillRoundedRect(img, image.Rect(margin, top, width-margin, top+scoreRow-6), 10, cardColor)
+		}
+		middle := top + (scoreRow-6)/2
+		f.score.Draw(img, margin+14, middle-f.score.LineHeight()/2, strconv.Itoa(i+1), muted)
+		drawAvatar(img, f, image.Rect(margin+48, middle-18, margin+48+36, middle+18), score.Player)
+		f.score.Draw(img, margin+48+44, middle-f.score.LineHeight()/2, f.score.Fit(score.Player.Name, nameWidth-16), ink)
+		length := barWidth * max(score.Points, 0) / best
+		fillRoundedRect(img, image.Rect(barLeft, middle-8, barLeft+max(length, 16), middle+8), 8, parseColor(score.Player.Color))
+		points := strconv.Itoa(score.Points)
+		f.score.Draw(img, width-margin-14-f.score.Measure(points), middle-f.score.LineHeight()/2, points, ink)
+	}
+}
+
+// drawAvatar draws the player's avatar in a circle ringed with their colour,
+// or their initial on that colour when they have no avatar.
+func drawAvatar(img *image.RGBA, f faces, rect image.Rectangle, player Player) {
+	paint := parseColor(player.Color)
+	ring := circleMask(rect.Dx())
+	draw.DrawMask(img, rect, image.NewUniform(paint), image.Point{}, ring, image.Point{}, draw.Over)
+	inner := rect.Inset(3)
+	mask := circleMask(inner.Dx())
+	if avatar, err := png.Decode(bytes.NewReader(player.Avatar)); err == nil {
+		scaled := image.NewRGBA(image.Rect(0, 0, inner.Dx(), inner.Dy()))
+		fillRect(scaled, scaled.Bounds(), cardColor)
+		drawScaled(scaled, scaled.Bounds(), avatar)
+		draw.DrawMask(img, inner, scaled, image.Point{}, mask, image.Point{}, draw.Over)
+		return
+	}
+	initial := strings.ToUpper(firstRune(player.Name))
+	x := rect.Min.X + (rect.Dx()-f.name.Measure(initial))/2
+	y := rect.Min.Y + (rect.Dy()-f.name.LineHeight())/2
+	f.name.Draw(img, x, y, initial, cardColor)
+}
+
+func firstRune(text string) string {
+	for _, r := range strings.TrimSpace(text) {
+		return string(r)
+	}
+	return "?"
+}
+
+func parseColor(value string) color.RGBA {
+	if len(value) == 7 && value[0] == '#' {
+		if parsed, err := strconv.ParseUint(value[1:], 16, 32); err == nil {
+			return color.RGBA{R: uint8(parsed >> 16), G: uint8(parsed >> 8), B: uint8(parsed), A: 0xff}
+		}
+	}
+	return muted
+}
@@ -0,0 +1,76 @@
+package poster
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func testDrawing(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 80, 60))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for x := 10; x < 70; x++ {
+		img.Set(x, 30, color.RGBA{R: 0xff, A: 0xff})
+	}
+	var out bytes.Buffer
+	if err := png.Encode(&out, img); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return out.Bytes()
+}
+
+func TestRenderLaysOutSectionsAndScores(t *testing.T) {
+	ada := Player{Name: "Ada", Color: "#ff6b6b", Avatar: testDrawing(t)}
+	ben := Player{Name: "Ben", Color: "#4dabf7"}
+	drawing := Drawing{Image: testDrawing(t), Prompt: "cat", Caption: "Best lie: “dog” by Ben", Artist: ada}
+	small, err := Render(Poster{Title: "Picture This", Sections: []Section{{Title: "Round 1", Drawings: []Drawing{drawing}}}})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	big, err := Render(Poster{
+		Title:    "Picture This",
+		Subtitle: "2 players",
+		Sections: []Section{
+			{Title: "Round 1", Drawings: []Drawing{drawing, drawing, drawing, drawing}},
+			{Title: "Round 2", Drawings: []Drawing{{Prompt: "missing image", Artist: ben}}},
+		},
+		Scores: []Score{{Player: ada, Points: 2000}, {Player: ben, Points: -100}},
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	smallImage, err := png.Decode(bytes.NewReader(small))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	bigImage, err := png.Decode(bytes.NewReader(big))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if smallImage.Bounds().Dx() != width || bigImage.Bounds().Dx() != width {
+		t.Fatalf("expected a %dpx wide poster", width)
+	}
+	if bigImage.Bounds().Dy() <= smallImage.Bounds().Dy() {
+		t.Fatalf("expected more rows to make a taller poster: %d vs %d", bigImage.Bounds().Dy(), smallImage.Bounds().Dy())
+	}
+}
+
+func TestLimitDrawings(t *testing.T) {
+	sections := []Section{
+		{Title: "1", Drawings: make([]Drawing, 3)},
+		{Title: "2", Drawings: make([]Drawing, 3)},
+		{Title: "3", Drawings: make([]Drawing, 3)},
+	}
+	limited := limitDrawings(sections, 5)
+	if len(limited) != 2 || len(limited[0].Drawings) != 3 || len(limited[1].Drawings) != 2 {
+		t.Fatalf("unexpected sections %+v", limited)
+	}
+	if len(sections[1].Drawings) != 3 {
+		t.Fatal("expected the input to be left alone")
+	}
+}
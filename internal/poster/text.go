@@ -0,0 +1,93 @@
+package poster
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Face draws text in one font at one pixel size. Like the font.Face it wraps,
+// it is not safe for concurrent use.
+type Face struct {
+	face    font.Face
+	metrics font.Metrics
+}
+
+func NewFace(f *opentype.Font, size float64) (*Face, error) {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingNone})
+	if err != nil {
+		return nil, err
+	}
+	return &Face{face: face, metrics: face.Metrics()}, nil
+}
+
+// Ascent is the distance from the top of a line to its baseline.
+func (f *Face) Ascent() int {
+	return f.metrics.Ascent.Ceil()
+}
+
+// LineHeight is the distance between consecutive baselines.
+func (f *Face) LineHeight() int {
+	return (f.metrics.Ascent + f.metrics.Descent).Ceil()
+}
+
+// Measure returns the width of text in pixels.
+func (f *Face) Measure(text string) int {
+	return font.MeasureString(f.face, text).Ceil()
+}
+
+// Draw paints text with its top-left corner at (x, y) and returns the x just
+// past the last glyph.
+func (f *Face) Draw(dst draw.Image, x, y int, text string, paint color.Color) int {
+	drawer := font.Drawer{Dst: dst, Src: image.NewUniform(paint), Face: f.face, Dot: fixed.P(x, y+f.Ascent())}
+	drawer.DrawString(text)
+	return drawer.Dot.X.Ceil()
+}
+
+// Fit shortens text with an ellipsis until it is at most width pixels wide.
+func (f *Face) Fit(text string, width int) string {
+	if f.Measure(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimRight(string(runes), " ") + "…"
+		if f.Measure(candidate) <= width {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// Wrap breaks text into at most maxLines lines no wider than width, ending
+// the last line with an ellipsis when the text does not fit.
+func (f *Face) Wrap(text string, width, maxLines int) []string {
+	words := strings.Fields(text)
+	var lines []string
+	current := ""
+	for i, word := range words {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if f.Measure(candidate) <= width || current == "" {
+			current = candidate
+			continue
+		}
+		if len(lines) == maxLines-1 {
+			return append(lines, f.Fit(strings.Join(append([]string{current}, words[i:]...), " "), width))
+		}
+		lines = append(lines, current)
+		current = word
+	}
+	if current != "" {
+		lines = append(lines, f.Fit(current, width))
+	}
+	return lines
+}
@@ -0,0 +1,67 @@
+package poster
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFaceDrawsGlyphs(t *testing.T) {
+	fonts, err := loadFonts()
+	if err != nil {
+		t.Fatalf("fonts: %v", err)
+	}
+	face, err := NewFace(fonts[0], 20)
+	if err != nil {
+		t.Fatalf("face: %v", err)
+	}
+	if glyph, err := fonts[0].GlyphIndex(nil, 'A'); err != nil || glyph == 0 {
+		t.Fatal("expected a glyph for A")
+	}
+	if face.Measure("WW") <= face.Measure("ii") {
+		t.Fatal("expected proportional widths")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 40, 30))
+	end := face.Draw(img, 2, 2, "Hé", color.Black)
+	if end != 2+face.Measure("Hé") {
+		t.Fatalf("unexpected pen position %d", end)
+	}
+	inked := 0
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] > 0 {
+			inked++
+		}
+	}
+	if inked < 40 {
+		t.Fatalf("expected the text to cover some pixels, got %d", inked)
+	}
+	// The stem of the H runs down the left edge of the glyph.
+	if _, _, _, a := img.At(4, 12).RGBA(); a < 0x8000 {
+		t.Fatal("expected the H stem to be inked")
+	}
+}
+
+func TestWrapAndFit(t *testing.T) {
+	fonts, err := loadFonts()
+	if err != nil {
+		t.Fatalf("fonts: %v", err)
+	}
+	face, err := NewFace(fonts[0], 16)
+	if err != nil {
+		t.Fatalf("face: %v", err)
+	}
+	width := face.Measure("a cat on")
+	lines := face.Wrap("a cat on a skateboard rolling down a very long hill", width, 2)
+	if len(lines) != 2 || lines[0] != "a cat on" {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+	for _, line := range lines {
+		if face.Measure(line) > width {
+			t.Fatalf("line %q is wider than %d", line, width)
+		}
+	}
+	if fitted := face.Fit("skateboard", face.Measure("skate")); fitted == "skateboard" || fitted == "" {
+		t.Fatalf("expected an ellipsis, got %q", fitted)
+	}
+}
@@ -2,16 +2,17 @@ package server
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"strings"
 
-	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func (s *Server) authenticatePlayerRequest(c *gin.Context, game *Game, playerID int, authToken string) (*Player, error) {
+func (s *Server) authenticatePlayerRequest(game *Game, playerID int, authToken string) (*Player, error) {
 	if game == nil {
 		return nil, errors.New("game not found")
 	}
@@ -22,10 +23,10 @@ func (s *Server) authenticatePlayerRequest(c *gin.Context, game *Game, playerID
 	if !ok {
 		return nil, errors.New("player not found")
 	}
-	expected := ensurePlayerAuthToken(game, playerID)
+	expected := playerAuthHash(game, playerID)
 	provided := strings.TrimSpace(authToken)
 	if provided != "" {
-		if subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) == 1 {
+		if subtle.ConstantTimeCompare([]byte(hashAuthToken(provided)), []byte(expected)) == 1 {
 			return player, nil
 		}
 		return nil, errors.New("invalid player authentication")
@@ -33,6 +34,43 @@ func (s *Server) authenticatePlayerRequest(c *gin.Context, game *Game, playerID
 	return nil, errors.New("authentication required")
 }
 
+// playerAuthHash returns the hash a player's token must match. A game loaded
+// from the database only knows the hashes stored on its players rows until a
+// player is issued a new token.
+func playerAuthHash(game *Game, playerID int) string {
+	if hash := game.PlayerAuthHashes[playerID]; hash != "" && game.PlayerAuthTokens[playerID] == "" {
+		return hash
+	}
+	return hashAuthToken(ensurePlayerAuthToken(game, playerID))
+}
+
+func hashAuthToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// authenticateDisplayRequest checks a big screen's display token.
+func authenticateDisplayRequest(game *Game, displayToken string) error {
+	provided := strings.TrimSpace(displayToken)
+	if provided == "" {
+		return errors.New("display token required")
+	}
+	if game == nil || game.DisplayTokenHash == "" || subtle.ConstantTimeCompare([]byte(hashAuthToken(provided)), []byte(game.DisplayTokenHash)) != 1 {
+		return errors.New("invalid display token")
+	}
+	return nil
+}
+
+// issueDisplayToken gives a game a fresh display token and the hash that is
+// stored on its games row.
+func issueDisplayToken(game *Game) {
+	game.DisplayToken = newAuthToken()
+	game.DisplayTokenHash = hashAuthToken(game.DisplayToken)
+}
+
 func newRecoveryCredential() (string, string, error) {
 	raw := make([]byte, 18)
 	if _, err := rand.Read(raw); err != nil {
@@ -47,8 +85,8 @@ func verifyRecoveryCredential(hash, code string) bool {
 	return hash != "" && code != "" && bcrypt.CompareHashAndPassword([]byte(hash), []byte(code)) == nil
 }
 
-func (s *Server) authenticateHostRequest(c *gin.Context, game *Game, playerID int, authToken string) (*Player, error) {
-	player, err := s.authenticatePlayerRequest(c, game, playerID, authToken)
+func (s *Server) authenticateHostRequest(game *Game, playerID int, authToken string) (*Player, error) {
+	player, err := s.authenticatePlayerRequest(game, playerID, authToken)
 	if err != nil {
 		return nil, err
 	}
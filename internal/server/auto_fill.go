@@ -34,6 +34,9 @@ func autoFillMissingGuesses(game *Game) []autoFilledGuess {
 	}
 	pending := pendingGuessersForIndex(game, round, drawingIndex)
 	for _, playerID := range pending {
+		if guessSatisfiedForPlayer(game, round, drawingIndex, playerID) {
+			continue
+		}
 		text := autoGuessText(round, drawingIndex, playerID)
 		round.Guesses = append(round.Guesses, GuessEntry{
 			PlayerID:     playerID,
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"fmt"
+
+	domain "picture-this/internal/game"
+)
+
+// awardsVisible reports whether the end-of-game awards are on screen: the
+// awards reveal stage after the final drawing, and the complete phase.
+func awardsVisible(game *Game) bool {
+	if game == nil || telephoneEnabled(game) {
+		return false
+	}
+	if game.Phase == phaseComplete {
+		return true
+	}
+	round := currentRound(game)
+	return game.Phase == phaseResults && round != nil && round.RevealStage == revealStageAwards
+}
+
+func buildAwards(game *Game) []map[string]any {
+	if !awardsVisible(game) {
+		return nil
+	}
+	names := buildNameMap(game.Players)
+	awards := domain.Awards(domainStateForScores(game))
+	payload := make([]map[string]any, 0, len(awards))
+	for _, award := range awards {
+		payload = append(payload, map[string]any{
+			"kind":        string(award.Kind),
+			"title":       award.Title,
+			"player_id":   award.PlayerID,
+			"player_name": names[award.PlayerID],
+			"count":       award.Count,
+			"text":        award.Text,
+			"detail":      awardDetail(award.Kind, award.Count, award.Text),
+		})
+	}
+	return payload
+}
+
+func awardDetail(kind domain.AwardKind, count int, text string) string {
+	switch kind {
+	case domain.AwardMostLikedLie:
+		return fmt.Sprintf("%q (%d likes)", text, count)
+	case domain.AwardBestLiar:
+		return fmt.Sprintf("Fooled %d players", count)
+	case domain.AwardSharpestEye:
+		return fmt.Sprintf("Found %d real titles", count)
+	case domain.AwardMostRecognisableArtist:
+		return fmt.Sprintf("Drawings guessed correctly %d times", count)
+	}
+	return ""
+}
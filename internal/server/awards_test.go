@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+)
+
+func finalRevealGame() *Game {
+	return &Game{Ruleset: rulesetDrawful, Phase: phaseResults, PromptsPerPlayer: 1, Players: []Player{
+		{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}, {ID: 3, Name: "Cam"},
+	}, Rounds: []RoundState{{
+		Number:      1,
+		RevealStage: revealStageVotes,
+		Drawings:    []DrawingEntry{{PlayerID: 1, Prompt: "real"}},
+		Guesses:     []GuessEntry{{PlayerID: 2, DrawingIndex: 0, Text: "lie"}},
+	}}}
+}
+
+func TestAwardsStageFollowsFinalReveal(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := finalRevealGame()
+	round := &game.Rounds[0]
+	round.Votes = []VoteEntry{{PlayerID: 3, DrawingIndex: 0, ChoiceText: "lie", ChoiceType: voteChoiceGuess}}
+	round.Likes = []LikeEntry{{PlayerID: 3, DrawingIndex: 0, GuessOwnerID: 2}}
+
+	if _, err := srv.advancePhase(game, transitionManual, time.Now()); err != nil {
+		t.Fatalf("advance: %v", err)
+	}
+	if game.Phase != phaseResults || round.RevealStage != revealStageAwards {
+		t.Fatalf("expected awards stage, got %s/%s", game.Phase, round.RevealStage)
+	}
+	awards := buildAwards(game)
+	if len(awards) != 2 || awards[0]["kind"] != "most_liked_lie" || awards[0]["player_name"] != "Ben" {
+		t.Fatalf("unexpected awards: %v", awards)
+	}
+	if display := srv.buildDisplayState(game); len(display.Awards) != 2 || display.StageTitle != "Awards" {
+		t.Fatalf("expected awards on the display, got %+v", display.Awards)
+	}
+	if scores := buildScores(game); scores[0]["likes"] != 1 {
+		t.Fatalf("expected like counts in scores, got %v", scores)
+	}
+
+	if _, err := srv.advancePhase(game, transitionManual, time.Now()); err != nil {
+		t.Fatalf("advance: %v", err)
+	}
+	if game.Phase != phaseComplete || len(buildAwards(game)) != 2 {
+		t.Fatalf("expected awards in the final snapshot, phase %s", game.Phase)
+	}
+}
+
+func TestNoAwardsSkipsStraightToComplete(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := finalRevealGame()
+	if _, err := srv.advancePhase(game, transitionManual, time.Now()); err != nil {
+		t.Fatalf("advance: %v", err)
+	}
+	if game.Phase != phaseComplete {
+		t.Fatalf("expected complete phase, got %s", game.Phase)
+	}
+}
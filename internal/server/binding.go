@@ -5,11 +5,31 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
 type bindMessages map[string]map[string]string
 
+// requestBinding is how a request body is validated and which messages
+// report failures, shared by the REST handlers and websocket commands.
+type requestBinding struct {
+	messages bindMessages
+	fallback string
+}
+
+func (b requestBinding) bind(c *gin.Context, req any) bool {
+	return bindJSON(c, req, b.messages, b.fallback)
+}
+
+// validate runs the same checks as bind on an already decoded request.
+func (b requestBinding) validate(req any) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return badCommand(resolveBindError(err, b.messages, b.fallback))
+	}
+	return nil
+}
+
 func bindJSON(c *gin.Context, req any, messages bindMessages, fallback string) bool {
 	if err := c.ShouldBindJSON(req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": resolveBindError(err, messages, fallback)})
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// serverTimeMillis is the server's clock in Unix milliseconds. Snapshots and
+// realtime messages carry it as server_time so clients can count down to a
+// deadline on the server's clock rather than their own.
+func serverTimeMillis() int64 {
+	return time.Now().UnixMilli()
+}
+
+type clockPingData struct {
+	ClientTime int64 `json:"client_time"`
+}
+
+// clockPongMessage answers a clock_ping NTP style: with the client's send
+// time echoed and the server's receive and send times, the client can
+// estimate both its offset from the server and the round trip.
+type clockPongMessage struct {
+	Type           string `json:"type"`
+	ClientTime     int64  `json:"client_time"`
+	ServerReceived int64  `json:"server_received"`
+	ServerTime     int64  `json:"server_time"`
+}
+
+func (s *Server) answerClockPing(client *wsClient, data json.RawMessage, received int64) {
+	var ping clockPingData
+	if len(data) > 0 && json.Unmarshal(data, &ping) != nil {
+		return
+	}
+	s.ws.Send(client, clockPongMessage{
+		Type:           "clock_pong",
+		ClientTime:     ping.ClientTime,
+		ServerReceived: received,
+		ServerTime:     serverTimeMillis(),
+	})
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestWebsocketClockPingAndServerTime(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := srv.store.CreateGame(1)
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Ada"}}
+		g.PlayerAuthTokens = map[int]string{1: "secret"}
+		return nil
+	}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/games/"+game.ID+"?player_id=1&auth_token=secret", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	lastSeen := func() time.Time {
+		srv.presence.mu.Lock()
+		defer srv.presence.mu.Unlock()
+		return srv.presence.games[game.ID][1].lastSeen
+	}
+
+	var changed stateChangedMessage
+	if err := conn.ReadJSON(&changed); err != nil || changed.Type != "state_changed" {
+		t.Fatalf("expected a state_changed message, got %+v (%v)", changed, err)
+	}
+	if skew := time.Since(time.UnixMilli(changed.ServerTime)); skew < 0 || skew > time.Second {
+		t.Fatalf("expected state_changed to carry the server time, got %d", changed.ServerTime)
+	}
+
+	seen := lastSeen()
+	sent := time.Now().UnixMilli()
+	if err := conn.WriteJSON(map[string]any{"type": "clock_ping", "data": map[string]int64{"client_time": sent}}); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	var pong clockPongMessage
+	for pong.Type != "clock_pong" {
+		if err := conn.ReadJSON(&pong); err != nil {
+			t.Fatalf("expected a clock_pong: %v", err)
+		}
+	}
+	if pong.ClientTime != sent || pong.ServerReceived < sent || pong.ServerTime < pong.ServerReceived {
+		t.Fatalf("expected the pong to echo the ping and stamp both server times, got %+v", pong)
+	}
+	if !lastSeen().Equal(seen) {
+		t.Fatal("expected a clock ping not to count as player activity")
+	}
+
+	snapshot := srv.snapshotForPlayer(game, 1)
+	if _, ok := snapshot["server_time"].(int64); !ok {
+		t.Fatalf("expected snapshots to carry the server time, got %v", snapshot["server_time"])
+	}
+	if _, ok := srv.stateForView(game, playerStateView(1))["server_time"]; ok {
+		t.Fatal("expected delta state to leave the server time to its messages")
+	}
+}
@@ -17,11 +17,17 @@ func cloneGame(source *Game) *Game {
 		game.Rounds[i].Drawings = append([]DrawingEntry(nil), sourceRound.Drawings...)
 		for j := range game.Rounds[i].Drawings {
 			game.Rounds[i].Drawings[j].ImageData = append([]byte(nil), sourceRound.Drawings[j].ImageData...)
+			game.Rounds[i].Drawings[j].Strokes = append([]byte(nil), sourceRound.Drawings[j].Strokes...)
 		}
 		game.Rounds[i].Guesses = append([]GuessEntry(nil), sourceRound.Guesses...)
 		game.Rounds[i].Votes = append([]VoteEntry(nil), sourceRound.Votes...)
 		game.Rounds[i].AudienceVotes = append([]AudienceVoteEntry(nil), sourceRound.AudienceVotes...)
 		game.Rounds[i].Likes = append([]LikeEntry(nil), sourceRound.Likes...)
+		game.Rounds[i].TitleGuesses = append([]TitleGuessEntry(nil), sourceRound.TitleGuesses...)
+		game.Rounds[i].ChainLinks = append([]ChainLinkEntry(nil), sourceRound.ChainLinks...)
+		for j := range game.Rounds[i].ChainLinks {
+			game.Rounds[i].ChainLinks[j].ImageData = append([]byte(nil), sourceRound.ChainLinks[j].ImageData...)
+		}
 	}
 	game.UsedPrompts = cloneStringSet(source.UsedPrompts)
 	game.KickedPlayers = cloneStringSet(source.KickedPlayers)
@@ -29,6 +35,12 @@ func cloneGame(source *Game) *Game {
 	for id, token := range source.PlayerAuthTokens {
 		game.PlayerAuthTokens[id] = token
 	}
+	if source.PlayerAuthHashes != nil {
+		game.PlayerAuthHashes = make(map[int]string, len(source.PlayerAuthHashes))
+		for id, hash := range source.PlayerAuthHashes {
+			game.PlayerAuthHashes[id] = hash
+		}
+	}
 	return &game
 }
 
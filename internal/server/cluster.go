@@ -0,0 +1,515 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"picture-this/internal/db"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+const (
+	clusterChannel      = "picture_this_games"
+	forwardedNodeHeader = "X-Picture-This-Node"
+)
+
+var errGameOwnedElsewhere = errors.New("game is owned by another node")
+
+// cluster tracks the games this node owns when several servers share one
+// Postgres database. Ownership is a lease row per game; other nodes forward
+// game requests to the owner and relay websocket updates announced through
+// LISTEN/NOTIFY.
+type cluster struct {
+	nodeID  string
+	nodeURL string
+	ttl     time.Duration
+	mu      sync.Mutex
+	owned   map[uint]string
+	// replicas caches the games this node mirrors for another owner, so each
+	// notice only replays the events after the cached version.
+	replicas map[uint]*Game
+}
+
+type clusterNotice struct {
+	Node    string `json:"node"`
+	GameID  string `json:"game_id"`
+	DBID    uint   `json:"db_id"`
+	Version int64  `json:"version"`
+}
+
+func newCluster(nodeID, nodeURL string, ttlSeconds int) *cluster {
+	if ttlSeconds <= 0 {
+		ttlSeconds = 15
+	}
+	return &cluster{
+		nodeID:   nodeID,
+		nodeURL:  strings.TrimRight(nodeURL, "/"),
+		ttl:      time.Duration(ttlSeconds) * time.Second,
+		owned:    make(map[uint]string),
+		replicas: make(map[uint]*Game),
+	}
+}
+
+func (c *cluster) track(dbID uint, gameID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.owned[dbID] = gameID
+}
+
+func (c *cluster) forget(dbID uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.owned, dbID)
+}
+
+func (c *cluster) ownedIDs() []uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids := make([]uint, 0, len(c.owned))
+	for id := range c.owned {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (c *cluster) gameID(dbID uint) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.owned[dbID]
+	return id, ok
+}
+
+// StartCluster runs lease heartbeats, expired-lease takeover and the
+// cross-node websocket relay until ctx is cancelled. It is a no-op for a
+// single-node server.
+func (s *Server) StartCluster(ctx context.Context) {
+	if s.cluster == nil {
+		return
+	}
+	log.Printf("cluster node started node=%s url=%s lease_ttl=%s", s.cluster.nodeID, s.cluster.nodeURL, s.cluster.ttl)
+	go s.runLeaseHeartbeats(ctx)
+	go s.listenForGameUpdates(ctx)
+}
+
+func (s *Server) runLeaseHeartbeats(ctx context.Context) {
+	ticker := time.NewTicker(s.cluster.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.renewLeases(); err != nil {
+				log.Printf("lease heartbeat failed node=%s error=%v", s.cluster.nodeID, err)
+			}
+			if err := s.takeOverExpiredGames(); err != nil {
+				log.Printf("lease takeover failed node=%s error=%v", s.cluster.nodeID, err)
+			}
+		}
+	}
+}
+
+// acquireGameLease claims dbID for this node if it is unowned, already ours,
+// or its lease has expired. Lease times come from the database clock so nodes
+// never have to agree on wall time.
+func (s *Server) acquireGameLease(dbID uint, gameID string) (bool, error) {
+	if s.cluster == nil {
+		return true, nil
+	}
+	result := s.db.Exec(
+		`INSERT INTO game_leases (game_id, owner, owner_url, expires_at, updated_at)
+		VALUES (?, ?, ?, now() + make_interval(secs => ?), now())
+		ON CONFLICT (game_id) DO UPDATE SET
+			owner = EXCLUDED.owner,
+			owner_url = EXCLUDED.owner_url,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = EXCLUDED.updated_at
+		WHERE game_leases.owner = EXCLUDED.owner OR game_leases.expires_at < now()`,
+		dbID, s.cluster.nodeID, s.cluster.nodeURL, s.cluster.ttl.Seconds(),
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	s.cluster.track(dbID, gameID)
+	return true, nil
+}
+
+// claimGame takes the lease for a game this node just created.
+func (s *Server) claimGame(game *Game) error {
+	if s.cluster == nil {
+		return nil
+	}
+	owned, err := s.acquireGameLease(game.DBID, game.ID)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return errGameOwnedElsewhere
+	}
+	return nil
+}
+
+// checkGameLease locks this node's live lease on the game for the rest of
+// tx, so a takeover waits for the write to finish and a node whose lease has
+// lapsed fails instead of writing alongside the new owner. Games without a
+// database row yet have no lease to check.
+func (s *Server) checkGameLease(tx *gorm.DB, game *Game) error {
+	if s.cluster == nil || game.DBID == 0 {
+		return nil
+	}
+	var owned []uint
+	if err := tx.Raw(
+		`SELECT game_id FROM game_leases
+		WHERE game_id = ? AND owner = ? AND expires_at > now()
+		FOR SHARE`,
+		game.DBID, s.cluster.nodeID,
+	).Scan(&owned).Error; err != nil {
+		return err
+	}
+	if len(owned) == 0 {
+		log.Printf("write fenced node=%s game_id=%s", s.cluster.nodeID, game.ID)
+		return errGameOwnedElsewhere
+	}
+	return nil
+}
+
+// renewLeases extends every lease this node holds and unloads games whose
+// lease was taken over, e.g. after a long pause in this process.
+func (s *Server) renewLeases() error {
+	ids := s.cluster.ownedIDs()
+	if len(ids) == 0 {
+		return nil
+	}
+	var renewed []uint
+	if err := s.db.Raw(
+		`UPDATE game_leases SET expires_at = now() + make_interval(secs => ?), updated_at = now()
+		WHERE owner = ? AND game_id IN ? RETURNING game_id`,
+		s.cluster.ttl.Seconds(), s.cluster.nodeID, ids,
+	).Scan(&renewed).Error; err != nil {
+		return err
+	}
+	kept := make(map[uint]struct{}, len(renewed))
+	for _, id := range renewed {
+		kept[id] = struct{}{}
+	}
+	for _, id := range ids {
+		if _, ok := kept[id]; ok {
+			continue
+		}
+		gameID, _ := s.cluster.gameID(id)
+		log.Printf("lease lost node=%s game_id=%s", s.cluster.nodeID, gameID)
+		s.cluster.forget(id)
+		s.cancelPhaseTimer(gameID)
+		s.store.DeleteGame(gameID)
+	}
+	return nil
+}
+
+// ReleaseLeases hands every game this node owns back to the cluster so other
+// nodes can take over without waiting for the leases to expire.
+func (s *Server) ReleaseLeases() {
+	if s.cluster == nil {
+		return
+	}
+	ids := s.cluster.ownedIDs()
+	if len(ids) == 0 {
+		return
+	}
+	if err := s.db.Where("owner = ? AND game_id IN ?", s.cluster.nodeID, ids).Delete(&db.GameLease{}).Error; err != nil {
+		log.Printf("lease release failed node=%s error=%v", s.cluster.nodeID, err)
+	}
+}
+
+// takeOverExpiredGames adopts unfinished games whose owner stopped
+// heartbeating.
+func (s *Server) takeOverExpiredGames() error {
+	var ids []uint
+	if err := s.db.Raw(
+		`SELECT game_leases.game_id FROM game_leases
+		JOIN games ON games.id = game_leases.game_id
+		WHERE game_leases.expires_at < now() AND games.phase <> ?`,
+		phaseComplete,
+	).Scan(&ids).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err := s.takeOverGame(id); err != nil {
+			log.Printf("lease takeover failed node=%s game_db_id=%d error=%v", s.cluster.nodeID, id, err)
+		}
+	}
+	return nil
+}
+
+// takeOverGame claims the lease for dbID and loads the game from the database.
+// It returns false when another live node owns the game.
+func (s *Server) takeOverGame(dbID uint) (bool, error) {
+	if _, ok := s.store.GetGame(fmt.Sprintf("game-%d", dbID)); ok {
+		return true, nil
+	}
+	game, _, err := s.loadGameFromDB(strconv.FormatUint(uint64(dbID), 10), false)
+	if errors.Is(err, errGameOwnedElsewhere) {
+		return false, nil
+	}
+	if err != nil {
+		s.cluster.forget(dbID)
+		return false, err
+	}
+	log.Printf("lease acquired node=%s game_id=%s phase=%s", s.cluster.nodeID, game.ID, game.Phase)
+	s.resumeRestoredGame(game)
+	return true, nil
+}
+
+// clusterMiddleware sends game requests this node does not own to the node
+// holding the game's lease. Websockets stay local and are fed through
+// LISTEN/NOTIFY instead.
+func (s *Server) clusterMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		gameID := strings.TrimSpace(c.Param("gameID"))
+		path := c.FullPath()
+		if s.cluster == nil || gameID == "" || strings.HasPrefix(path, "/ws/") || strings.HasPrefix(path, "/sse/") || strings.HasPrefix(path, "/admin") {
+			c.Next()
+			return
+		}
+		if _, ok := s.findGameInStore(gameID); ok {
+			c.Next()
+			return
+		}
+		if c.GetHeader(forwardedNodeHeader) != "" {
+			c.Next()
+			return
+		}
+		ownerURL, err := s.gameOwnerURL(gameID)
+		if err != nil || ownerURL == "" {
+			c.Next()
+			return
+		}
+		target, err := url.Parse(ownerURL)
+		if err != nil {
+			c.Next()
+			return
+		}
+		proxy := httputil.NewSingleHostReverseProxy(target)
+		proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			log.Printf("forward failed node=%s game_id=%s owner=%s error=%v", s.cluster.nodeID, gameID, ownerURL, err)
+			w.WriteHeader(http.StatusBadGateway)
+		}
+		c.Request.Header.Set(forwardedNodeHeader, s.cluster.nodeID)
+		proxy.ServeHTTP(c.Writer, c.Request)
+		c.Abort()
+	}
+}
+
+// gameOwnerURL returns the owner's base URL for a game held by another node.
+// An unowned or expired game is taken over locally and yields "".
+func (s *Server) gameOwnerURL(gameID string) (string, error) {
+	dbID, err := s.resolveClusterGameID(gameID)
+	if err != nil {
+		return "", err
+	}
+	var lease db.GameLease
+	err = s.db.Where("game_id = ? AND expires_at > now()", dbID).First(&lease).Error
+	if err == nil && lease.Owner != s.cluster.nodeID {
+		return lease.OwnerURL, nil
+	}
+	var record db.Game
+	if err := s.db.Select("phase").First(&record, dbID).Error; err != nil {
+		return "", err
+	}
+	if record.Phase == phaseComplete {
+		return "", nil
+	}
+	if _, err := s.takeOverGame(dbID); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+func (s *Server) resolveClusterGameID(gameID string) (uint, error) {
+	for _, prefix := range []string{"game-", "db-"} {
+		if !strings.HasPrefix(gameID, prefix) {
+			continue
+		}
+		if id, err := strconv.ParseUint(strings.TrimPrefix(gameID, prefix), 10, 64); err == nil && id > 0 {
+			return uint(id), nil
+		}
+	}
+	var record db.Game
+	if err := s.db.Select("id").Where("join_code = ?", gameID).First(&record).Error; err != nil {
+		return 0, err
+	}
+	return record.ID, nil
+}
+
+// publishGameUpdate tells the other nodes that a game this node owns changed.
+func (s *Server) publishGameUpdate(game *Game) {
+	if s.cluster == nil || game.DBID == 0 {
+		return
+	}
+	payload, err := json.Marshal(clusterNotice{Node: s.cluster.nodeID, GameID: game.ID, DBID: game.DBID, Version: game.Version})
+	if err != nil {
+		return
+	}
+	if err := s.db.Exec("SELECT pg_notify(?, ?)", clusterChannel, string(payload)).Error; err != nil {
+		log.Printf("cluster notify failed game_id=%s error=%v", game.ID, err)
+	}
+}
+
+func (s *Server) listenForGameUpdates(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := s.listenOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("cluster listen failed node=%s error=%v", s.cluster.nodeID, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// listenOnce holds one pooled connection in LISTEN mode until it fails.
+func (s *Server) listenOnce(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Raw(func(driverConn any) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("database driver does not support LISTEN")
+		}
+		pgConn := stdConn.Conn()
+		if _, err := pgConn.Exec(ctx, "LISTEN "+clusterChannel); err != nil {
+			return err
+		}
+		for {
+			notification, err := pgConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			var notice clusterNotice
+			if err := json.Unmarshal([]byte(notification.Payload), &notice); err != nil {
+				continue
+			}
+			s.relayGameUpdate(notice)
+		}
+	})
+}
+
+// relayGameUpdate pushes another node's change to the websockets connected
+// here, bringing the game's replica up to date from its event stream.
+func (s *Server) relayGameUpdate(notice clusterNotice) {
+	if notice.Node == s.cluster.nodeID {
+		return
+	}
+	if !s.ws.HasSubscribers(notice.GameID) {
+		s.cluster.dropReplica(notice.DBID)
+		return
+	}
+	game, err := s.loadReplicaGame(notice.GameID, notice.DBID, notice.Version)
+	if err != nil {
+		log.Printf("cluster relay failed game_id=%s error=%v", notice.GameID, err)
+		return
+	}
+	s.broadcastLocalGameUpdate(game)
+}
+
+// loadReplicaGame returns a read-only copy of a game owned by another node.
+// A cached replica only applies the events recorded since its version; a
+// missing replica or a version gap replays the whole stream. version is the
+// owner's version from a notice, or zero; every event up to it is already
+// committed, so the replica counts as caught up to it. The returned game is
+// shared and must not be modified.
+func (s *Server) loadReplicaGame(gameID string, dbID uint, version int64) (*Game, error) {
+	if cached := s.cluster.replica(dbID); cached != nil {
+		if cached.Version >= version && version > 0 {
+			return cached, nil
+		}
+		events, err := s.loadGameEventsAfter(dbID, cached.Version)
+		if err != nil {
+			return nil, err
+		}
+		game, err := advanceGameEvents(cached, events)
+		if err == nil {
+			game.Version = max(game.Version, version)
+			return s.cluster.storeReplica(dbID, game), nil
+		}
+		if !errors.Is(err, errEventGap) {
+			return nil, err
+		}
+		log.Printf("cluster replica replayed game_id=%s version=%d error=%v", gameID, cached.Version, err)
+	}
+	events, err := s.loadGameEvents(dbID)
+	if err != nil {
+		return nil, err
+	}
+	game, err := replayGameEvents(events, 0)
+	if err != nil {
+		return nil, err
+	}
+	game.ID = gameID
+	game.DBID = dbID
+	game.Version = max(game.Version, version)
+	return s.cluster.storeReplica(dbID, game), nil
+}
+
+func (c *cluster) replica(dbID uint) *Game {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.replicas[dbID]
+}
+
+// storeReplica caches game unless a newer replica got there first, and
+// returns whichever is cached.
+func (c *cluster) storeReplica(dbID uint, game *Game) *Game {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached := c.replicas[dbID]; cached != nil && cached.Version > game.Version {
+		return cached
+	}
+	c.replicas[dbID] = game
+	return game
+}
+
+func (c *cluster) dropReplica(dbID uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.replicas, dbID)
+}
+
+// clusterReplica returns the replica of a game owned by another node, so a
+// websocket can subscribe here without the game being loaded locally.
+func (s *Server) clusterReplica(gameID string) (*Game, bool) {
+	if s.cluster == nil {
+		return nil, false
+	}
+	dbID, err := s.resolveClusterGameID(gameID)
+	if err != nil {
+		return nil, false
+	}
+	game, err := s.loadReplicaGame(gameID, dbID, 0)
+	if err != nil {
+		return nil, false
+	}
+	return game, true
+}
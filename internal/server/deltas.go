@@ -0,0 +1,221 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"picture-this/internal/jsonpatch"
+)
+
+// stateDeltaHistory is how many deltas each view keeps for clients that
+// reconnect; clients further behind get a full snapshot.
+const stateDeltaHistory = 32
+
+const (
+	stateViewPublic   = "public"
+	stateViewAudience = "audience"
+)
+
+func playerStateView(playerID int) string {
+	return "player:" + strconv.Itoa(playerID)
+}
+
+// stateViewFor picks the snapshot a delta client sees: its own player view
+// when authenticated, otherwise the public or audience view.
+func stateViewFor(role string, playerID int) string {
+	if role == wsRoleAudience {
+		return stateViewAudience
+	}
+	if playerID > 0 && (role == wsRolePlayer || role == wsRoleHost) {
+		return playerStateView(playerID)
+	}
+	return stateViewPublic
+}
+
+// stateForView is the state a delta view tracks. The server time is left
+// out, since it would change every delta; the messages carry their own.
+func (s *Server) stateForView(game *Game, view string) map[string]any {
+	var state map[string]any
+	switch view {
+	case stateViewPublic:
+		state = s.snapshotForPublic(game)
+	case stateViewAudience:
+		state = s.snapshotForAudience(game)
+	default:
+		playerID, _ := strconv.Atoi(strings.TrimPrefix(view, "player:"))
+		state = s.snapshotForPlayer(game, playerID)
+	}
+	delete(state, "server_time")
+	return state
+}
+
+type stateDeltaMessage struct {
+	Type    string                `json:"type"`
+	Stream  string                `json:"stream"`
+	Base    int64                 `json:"base"`
+	Rev     int64                 `json:"rev"`
+	Version int64                 `json:"version"`
+	Ops     []jsonpatch.Operation `json:"ops"`
+	// ServerTime is stamped when the message is sent, not when the delta
+	// was recorded.
+	ServerTime int64 `json:"server_time,omitempty"`
+}
+
+type stateSnapshotMessage struct {
+	Type       string `json:"type"`
+	Stream     string `json:"stream"`
+	Rev        int64  `json:"rev"`
+	Version    int64  `json:"version"`
+	State      any    `json:"state"`
+	ServerTime int64  `json:"server_time,omitempty"`
+}
+
+// viewLog is the latest state of one view and the deltas that led to it.
+// rev counts changes to the view, including ones that do not bump the game
+// version, such as presence.
+type viewLog struct {
+	rev     int64
+	version int64
+	state   any
+	deltas  []stateDeltaMessage
+}
+
+// stateStream holds one game's delta logs on this node. Its id is new each
+// time the stream is created, so clients holding revisions from another
+// node or an earlier process are sent a full snapshot.
+type stateStream struct {
+	mu    sync.Mutex
+	id    string
+	views map[string]*viewLog
+}
+
+type stateStreams struct {
+	mu    sync.Mutex
+	games map[string]*stateStream
+}
+
+func newStateStreams() *stateStreams {
+	return &stateStreams{games: make(map[string]*stateStream)}
+}
+
+func (s *stateStreams) get(gameID string) *stateStream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stream := s.games[gameID]
+	if stream == nil {
+		raw := make([]byte, 8)
+		_, _ = rand.Read(raw)
+		stream = &stateStream{id: hex.EncodeToString(raw), views: make(map[string]*viewLog)}
+		s.games[gameID] = stream
+	}
+	return stream
+}
+
+func (s *stateStreams) Forget(gameID string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.games, gameID)
+}
+
+// advanceLocked records state as the view's latest and returns the delta
+// from the previous state when something changed. Broadcasts can race, so a
+// state from an older game version than the log already has is ignored.
+func (st *stateStream) advanceLocked(view string, version int64, state any) (stateDeltaMessage, bool) {
+	history := st.views[view]
+	if history == nil {
+		st.views[view] = &viewLog{rev: 1, version: version, state: state}
+		return stateDeltaMessage{}, false
+	}
+	if version < history.version {
+		return stateDeltaMessage{}, false
+	}
+	ops := jsonpatch.Diff(history.state, state)
+	if len(ops) == 0 {
+		return stateDeltaMessage{}, false
+	}
+	delta := stateDeltaMessage{
+		Type:    "state_delta",
+		Stream:  st.id,
+		Base:    history.rev,
+		Rev:     history.rev + 1,
+		Version: version,
+		Ops:     ops,
+	}
+	history.rev, history.version, history.state = delta.Rev, version, state
+	history.deltas = append(history.deltas, delta)
+	if len(history.deltas) > stateDeltaHistory {
+		history.deltas = history.deltas[len(history.deltas)-stateDeltaHistory:]
+	}
+	return delta, true
+}
+
+// catchUpLocked returns what a client last at rev on stream needs: the
+// deltas it missed, or a full snapshot when they are no longer kept.
+func (st *stateStream) catchUpLocked(view, stream string, rev int64) []any {
+	history := st.views[view]
+	if history == nil {
+		return nil
+	}
+	if stream == st.id && rev == history.rev {
+		return nil
+	}
+	if stream == st.id && rev > 0 && rev < history.rev {
+		for i, delta := range history.deltas {
+			if delta.Base != rev {
+				continue
+			}
+			missed := make([]any, 0, len(history.deltas)-i)
+			for _, delta := range history.deltas[i:] {
+				delta.ServerTime = serverTimeMillis()
+				missed = append(missed, delta)
+			}
+			return missed
+		}
+	}
+	return []any{stateSnapshotMessage{Type: "state_snapshot", Stream: st.id, Rev: history.rev, Version: history.version, State: history.state, ServerTime: serverTimeMillis()}}
+}
+
+// publishState sends every delta client of the game the change to its view.
+func (s *Server) publishState(game *Game) {
+	views := s.ws.Views(game.ID)
+	if len(views) == 0 {
+		return
+	}
+	stream := s.streams.get(game.ID)
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	for _, view := range views {
+		s.advanceViewLocked(stream, game, view)
+	}
+}
+
+func (s *Server) advanceViewLocked(stream *stateStream, game *Game, view string) {
+	state, err := jsonpatch.Normalize(s.stateForView(game, view))
+	if err != nil {
+		log.Printf("state delta failed game_id=%s view=%s error=%v", game.ID, view, err)
+		return
+	}
+	if delta, ok := stream.advanceLocked(view, game.Version, state); ok {
+		delta.ServerTime = serverTimeMillis()
+		s.ws.SendView(game.ID, view, delta)
+	}
+}
+
+// syncClientState brings a delta client from the revision it last saw up to
+// the current state of its view.
+func (s *Server) syncClientState(game *Game, client *wsClient, streamID string, rev int64) {
+	stream := s.streams.get(game.ID)
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	s.advanceViewLocked(stream, game, client.view)
+	for _, message := range stream.catchUpLocked(client.view, streamID, rev) {
+		s.ws.Send(client, message)
+	}
+}
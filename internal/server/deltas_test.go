@@ -0,0 +1,184 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+	"picture-this/internal/jsonpatch"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestStateStreamCatchUp(t *testing.T) {
+	stream := &stateStream{id: "s1", views: make(map[string]*viewLog)}
+	if _, ok := stream.advanceLocked("public", 1, map[string]any{"phase": "lobby"}); ok {
+		t.Fatal("the first state has nothing to diff against")
+	}
+	for version := int64(2); version <= 4; version++ {
+		delta, ok := stream.advanceLocked("public", version, map[string]any{"phase": "lobby", "version": float64(version)})
+		if !ok || delta.Base != version-1 || delta.Rev != version {
+			t.Fatalf("unexpected delta %+v", delta)
+		}
+	}
+	if _, ok := stream.advanceLocked("public", 3, map[string]any{"phase": "stale"}); ok {
+		t.Fatal("expected a state from an older version to be ignored")
+	}
+	if _, ok := stream.advanceLocked("public", 4, map[string]any{"phase": "lobby", "version": float64(4)}); ok {
+		t.Fatal("expected no delta when nothing changed")
+	}
+
+	missed := stream.catchUpLocked("public", "s1", 2)
+	if len(missed) != 2 || missed[0].(stateDeltaMessage).Base != 2 {
+		t.Fatalf("expected the two missed deltas, got %+v", missed)
+	}
+	if got := stream.catchUpLocked("public", "s1", 4); len(got) != 0 {
+		t.Fatalf("expected an up-to-date client to get nothing, got %+v", got)
+	}
+	for _, stale := range []struct {
+		stream string
+		rev    int64
+	}{{"other", 2}, {"s1", 0}, {"s1", 9}} {
+		got := stream.catchUpLocked("public", stale.stream, stale.rev)
+		if len(got) != 1 || got[0].(stateSnapshotMessage).Rev != 4 {
+			t.Fatalf("expected a full snapshot for %+v, got %+v", stale, got)
+		}
+	}
+
+	for version := int64(5); version < 5+stateDeltaHistory; version++ {
+		stream.advanceLocked("public", version, map[string]any{"version": float64(version)})
+	}
+	if got := stream.catchUpLocked("public", "s1", 2); len(got) != 1 || got[0].(stateSnapshotMessage).Type != "state_snapshot" {
+		t.Fatalf("expected a snapshot once the deltas are gone, got %d messages", len(got))
+	}
+}
+
+// deltaClient mirrors static/state_stream.js.
+type deltaClient struct {
+	conn   *websocket.Conn
+	stream string
+	rev    int64
+	state  any
+	// snapshots and deltas count what the client was sent.
+	snapshots, deltas int
+}
+
+func (c *deltaClient) readUntil(t *testing.T, want any) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !reflect.DeepEqual(c.state, want) {
+		_ = c.conn.SetReadDeadline(deadline)
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("waiting for state: %v\nhave %v\nwant %v", err, c.state, want)
+		}
+		var message struct {
+			Type   string                `json:"type"`
+			Stream string                `json:"stream"`
+			Base   int64                 `json:"base"`
+			Rev    int64                 `json:"rev"`
+			State  any                   `json:"state"`
+			Ops    []jsonpatch.Operation `json:"ops"`
+		}
+		if err := json.Unmarshal(data, &message); err != nil {
+			t.Fatalf("decode %s: %v", data, err)
+		}
+		switch message.Type {
+		case "state_snapshot":
+			c.snapshots++
+			c.stream, c.rev, c.state = message.Stream, message.Rev, message.State
+		case "state_delta":
+			if message.Rev <= c.rev {
+				continue
+			}
+			if message.Stream != c.stream || message.Base != c.rev {
+				t.Fatalf("delta %d->%d does not follow rev %d", message.Base, message.Rev, c.rev)
+			}
+			c.deltas++
+			if c.state, err = jsonpatch.Apply(c.state, message.Ops); err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+			c.rev = message.Rev
+		default:
+			t.Fatalf("unexpected message %s", data)
+		}
+	}
+}
+
+func TestWebsocketStateDeltas(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := srv.store.CreateGame(1)
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}}
+		g.PlayerAuthTokens = map[int]string{1: "secret"}
+		return nil
+	}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	base := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/games/" + game.ID
+	url := base + "?player_id=1&auth_token=secret&deltas=1"
+	expected := func() any {
+		current, _ := srv.store.GetGame(game.ID)
+		state, err := jsonpatch.Normalize(srv.stateForView(current, playerStateView(1)))
+		if err != nil {
+			t.Fatalf("normalize: %v", err)
+		}
+		return state
+	}
+	rename := func(name string) {
+		updated, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+			g.Players[1].Name = name
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		srv.broadcastLocalGameUpdate(updated)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	legacy, _, err := websocket.DefaultDialer.Dial(base, nil)
+	if err != nil {
+		t.Fatalf("dial legacy: %v", err)
+	}
+	defer legacy.Close()
+	client := &deltaClient{conn: conn}
+	client.readUntil(t, expected())
+	rename("Bea")
+	client.readUntil(t, expected())
+	if client.snapshots != 1 || client.deltas == 0 {
+		t.Fatalf("expected one snapshot and then deltas, got %d and %d", client.snapshots, client.deltas)
+	}
+	if messageType := readWSMessageType(t, legacy, time.Second); messageType != "state-changed" {
+		t.Fatalf("expected clients without deltas to keep getting state_changed, got %s", messageType)
+	}
+
+	// Keep a second client connected so the stream survives the reconnect.
+	other, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer other.Close()
+	conn.Close()
+	rename("Bo")
+	conn, _, err = websocket.DefaultDialer.Dial(url+"&stream="+client.stream+"&since="+strconv.FormatInt(client.rev, 10), nil)
+	if err != nil {
+		t.Fatalf("redial: %v", err)
+	}
+	defer conn.Close()
+	client.conn = conn
+	client.readUntil(t, expected())
+	if client.snapshots != 1 {
+		t.Fatalf("expected the reconnect to be caught up with deltas, got %d snapshots", client.snapshots)
+	}
+}
@@ -46,21 +46,34 @@ func (s *Server) buildDisplayState(game *Game) web.DisplayState {
 			phaseEndsAt = game.PhaseStartedAt.Add(duration).UTC().Format(time.RFC3339)
 		}
 	}
+	presence := s.playerPresence(game)
 	players := make([]web.DisplayPlayer, 0, len(game.Players))
 	for _, player := range game.Players {
 		players = append(players, web.DisplayPlayer{
-			Name:   player.Name,
-			Avatar: encodeImageData(player.Avatar),
-			IsHost: player.ID == game.HostID,
+			Name:     player.Name,
+			Avatar:   imageSource(player.Avatar, player.AvatarHash),
+			IsHost:   player.ID == game.HostID,
+			Presence: presence[player.ID],
 		})
 	}
 	scores := make([]web.DisplayScore, 0)
 	for _, entry := range buildScores(game) {
 		name, _ := entry["player_name"].(string)
 		score, _ := entry["score"].(int)
+		team, _ := entry["team"].(int)
 		scores = append(scores, web.DisplayScore{
 			Name:  name,
 			Score: score,
+			Team:  team,
+		})
+	}
+	teamScores := make([]web.DisplayScore, 0)
+	for _, entry := range buildTeamScores(game) {
+		team, _ := entry["team"].(int)
+		score, _ := entry["score"].(int)
+		teamScores = append(teamScores, web.DisplayScore{
+			Name:  fmt.Sprintf("Team %d", team),
+			Score: score,
 		})
 	}
 	drawingSubmitted := 0
@@ -95,6 +108,7 @@ func (s *Server) buildDisplayState(game *Game) web.DisplayState {
 		JoinCode:           game.JoinCode,
 		Phase:              phase,
 		PhaseEndsAt:        phaseEndsAt,
+		PausedSeconds:      pausedRemainingSeconds(game),
 		RevealStage:        revealStage,
 		RevealJokeAudio:    revealJokeAudio,
 		RevealVoteSequence: revealVoteSequence,
@@ -110,8 +124,11 @@ func (s *Server) buildDisplayState(game *Game) web.DisplayState {
 		StageStatus:        stageStatus,
 		StageImage:         stageImage,
 		Options:            options,
+		ChainLinks:         buildDisplayChainLinks(game),
+		Awards:             buildDisplayAwards(game),
 		Players:            players,
 		Scores:             scores,
+		TeamScores:         teamScores,
 		ShowScoreboard:     showScoreboard,
 		ShowFinal:          showFinal,
 		PlayerCount:        len(game.Players),
@@ -131,6 +148,9 @@ func (s *Server) renderDisplayHTML(game *Game) string {
 func buildDisplayStage(game *Game) (string, string, string, []string) {
 	phase := game.Phase
 	if phase == phasePaused {
+		if allPlayersClaimed(game.Players) {
+			return "Game paused", "The host paused the game. Hang tight.", "", nil
+		}
 		status := "Game is paused. Players should rejoin and claim their name."
 		if game.PausedPhase != "" {
 			status = "Game paused during " + game.PausedPhase + ". Players should rejoin and claim their name."
@@ -158,19 +178,62 @@ func buildDisplayStage(game *Game) (string, string, string, []string) {
 		switch stage {
 		case revealStageGuesses:
 			status = "Revealing guesses."
+			// Replay the drawing being made while the guesses come in.
+			if timeLapse, _ := reveal["timelapse_url"].(string); timeLapse != "" {
+				image = timeLapse
+			}
 		case revealStageVotes:
 			status = "Revealing votes."
 		case revealStageJoke:
 			status = "Narrator is reading the joke."
+		case revealStageAwards:
+			return "Awards", "And the awards go to...", "", nil
 		}
 		return "Drawing results", status, image, options
 	}
+	if phase == phaseChainDraw {
+		return "Telephone: drawing", chainStepLabel(game), "", nil
+	}
+	if phase == phaseChainDescribe {
+		return "Telephone: describing", chainStepLabel(game), "", nil
+	}
+	if phase == phaseChainReveal {
+		reveal := buildChainReveal(game)
+		owner, _ := reveal["owner_name"].(string)
+		prompt, _ := reveal["prompt"].(string)
+		return owner + "'s chain", "Started from: " + prompt, "", nil
+	}
 	if phase == phaseComplete {
 		return "Game complete", "Thanks for playing!", "", nil
 	}
 	return "Waiting for updates", "Loading game status.", "", nil
 }
 
+func buildDisplayChainLinks(game *Game) []web.DisplayChainLink {
+	reveal := buildChainReveal(game)
+	links, _ := reveal["links"].([]map[string]any)
+	result := make([]web.DisplayChainLink, 0, len(links))
+	for _, link := range links {
+		name, _ := link["player_name"].(string)
+		text, _ := link["text"].(string)
+		image, _ := link["drawing_image"].(string)
+		result = append(result, web.DisplayChainLink{PlayerName: name, Text: text, Image: image})
+	}
+	return result
+}
+
+func buildDisplayAwards(game *Game) []web.DisplayAward {
+	awards := buildAwards(game)
+	result := make([]web.DisplayAward, 0, len(awards))
+	for _, award := range awards {
+		title, _ := award["title"].(string)
+		name, _ := award["player_name"].(string)
+		detail, _ := award["detail"].(string)
+		result = append(result, web.DisplayAward{Title: title, PlayerName: name, Detail: detail})
+	}
+	return result
+}
+
 func buildGuessStage(game *Game) (string, string, string, []string) {
 	round := currentRound(game)
 	if round == nil {
@@ -207,7 +270,7 @@ func buildGuessStage(game *Game) (string, string, string, []string) {
 	if pendingCount := len(pending); pendingCount > 0 {
 		status += " " + strconv.Itoa(pendingCount) + " left on this drawing."
 	}
-	image := encodeImageData(round.Drawings[drawingIndex].ImageData)
+	image := imageSource(round.Drawings[drawingIndex].ImageData, round.Drawings[drawingIndex].ImageHash)
 	return "Guessing prompts", status, image, nil
 }
 
@@ -247,7 +310,7 @@ func buildVoteStage(game *Game) (string, string, string, []string) {
 	if pendingCount := len(pending); pendingCount > 0 {
 		status += " " + strconv.Itoa(pendingCount) + " left on this drawing."
 	}
-	image := encodeImageData(round.Drawings[drawingIndex].ImageData)
+	image := imageSource(round.Drawings[drawingIndex].ImageData, round.Drawings[drawingIndex].ImageHash)
 	options := voteOptionsForDrawing(round, drawingIndex)
 	return "Vote for the real prompt", status, image, options
 }
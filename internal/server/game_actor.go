@@ -1,6 +1,16 @@
 package server
 
-import "errors"
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var errGameUnloaded = errors.New("game unloaded")
+
+// runningGameActors counts actor goroutines that have not exited yet.
+var runningGameActors atomic.Int64
 
 type actorCommand struct {
 	apply   func(*Game) error
@@ -15,53 +25,202 @@ type actorCommand struct {
 type gameActor struct {
 	game     *Game
 	commands chan actorCommand
+	// journal receives the typed events for every committed command. A game
+	// that has not been journaled yet first records its initial state.
+	journal   func(*Game, []GameEvent) error
+	journaled bool
+	// transact runs a command's row writes and journal insert as one unit, so
+	// a failure in either leaves both untouched.
+	transact func(*Game, func() error) error
+	// lastActive is the unix time in nanoseconds of the last committed command.
+	lastActive atomic.Int64
+	stop       chan struct{}
+	stopOnce   sync.Once
+	done       chan struct{}
+	// summary is what the janitor and metrics need from the committed game,
+	// so they can read it without a round trip or a clone.
+	summary atomic.Pointer[ResidentGame]
 }
 
-func newGameActor(game *Game) *gameActor {
-	actor := &gameActor{game: game, commands: make(chan actorCommand, 64)}
+func newGameActor(game *Game, journal func(*Game, []GameEvent) error, transact func(*Game, func() error) error, journaled bool) *gameActor {
+	actor := &gameActor{
+		game:      game,
+		commands:  make(chan actorCommand, 64),
+		journal:   journal,
+		journaled: journaled,
+		transact:  transact,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	actor.lastActive.Store(time.Now().UnixNano())
+	actor.publish()
+	runningGameActors.Add(1)
 	go actor.run()
 	return actor
 }
 
 func (a *gameActor) run() {
-	for command := range a.commands {
-		if command.read != nil {
-			command.read <- cloneGame(a.game)
-			continue
+	defer runningGameActors.Add(-1)
+	defer close(a.done)
+	for {
+		select {
+		case <-a.stop:
+			return
+		case command := <-a.commands:
+			a.handle(command)
 		}
-		if command.apply == nil {
-			command.result <- errors.New("empty game command")
-			continue
-		}
-		candidate := cloneGame(a.game)
-		err := command.apply(candidate)
-		if err == nil {
-			candidate.Version++
-			if command.persist != nil {
-				err = command.persist(candidate)
+	}
+}
+
+func (a *gameActor) handle(command actorCommand) {
+	if command.read != nil {
+		command.read <- cloneGame(a.game)
+		return
+	}
+	if command.apply == nil {
+		command.result <- errors.New("empty game command")
+		return
+	}
+	candidate := cloneGame(a.game)
+	err := command.apply(candidate)
+	if err == nil {
+		candidate.Version++
+		err = a.commit(candidate, command.persist)
+	}
+	if err == nil {
+		a.game = candidate
+		a.lastActive.Store(time.Now().UnixNano())
+		a.publish()
+	}
+	command.result <- err
+}
+
+// commit writes the command's rows and its journal events together. Nothing
+// about the candidate is kept unless both succeed.
+func (a *gameActor) commit(candidate *Game, persist func(*Game) error) error {
+	events, err := a.pendingEvents(candidate)
+	if err != nil {
+		return err
+	}
+	write := func() error {
+		if persist != nil {
+			if err := persist(candidate); err != nil {
+				return err
 			}
 		}
-		if err == nil {
-			a.game = candidate
+		if a.journal == nil || len(events) == 0 {
+			return nil
+		}
+		return a.journal(candidate, events)
+	}
+	if a.transact != nil {
+		err = a.transact(candidate, write)
+	} else {
+		err = write()
+	}
+	if err != nil {
+		return err
+	}
+	if len(events) > 0 {
+		a.journaled = true
+	}
+	return nil
+}
+
+// shutdown stops the actor goroutine. Commands still queued fail with
+// errGameUnloaded; reads keep returning the last committed state.
+func (a *gameActor) shutdown() {
+	a.stopOnce.Do(func() { close(a.stop) })
+	<-a.done
+}
+
+func (a *gameActor) idleSince() time.Time {
+	return time.Unix(0, a.lastActive.Load())
+}
+
+func (a *gameActor) publish() {
+	a.summary.Store(&ResidentGame{
+		ID:             a.game.ID,
+		DBID:           a.game.DBID,
+		Phase:          a.game.Phase,
+		PhaseStartedAt: a.game.PhaseStartedAt,
+	})
+}
+
+// resident returns the committed game's summary without going through the
+// actor goroutine.
+func (a *gameActor) resident() ResidentGame {
+	summary := *a.summary.Load()
+	summary.IdleSince = a.idleSince()
+	return summary
+}
+
+// pendingEvents describes the change to candidate as journal events. A game
+// that has not been journaled yet first records its initial state.
+func (a *gameActor) pendingEvents(candidate *Game) ([]GameEvent, error) {
+	if a.journal == nil {
+		return nil, nil
+	}
+	var events []GameEvent
+	if !a.journaled {
+		initial, err := diffGameEvents(nil, a.game)
+		if err != nil {
+			return nil, err
 		}
-		command.result <- err
+		events = append(events, initial...)
 	}
+	changes, err := diffGameEvents(a.game, candidate)
+	if err != nil {
+		return nil, err
+	}
+	return append(events, changes...), nil
 }
 
 func (a *gameActor) execute(apply func(*Game) error) error {
-	result := make(chan error, 1)
-	a.commands <- actorCommand{apply: apply, result: result}
-	return <-result
+	return a.submit(actorCommand{apply: apply})
 }
 
 func (a *gameActor) executeDurably(apply, persist func(*Game) error) error {
+	return a.submit(actorCommand{apply: apply, persist: persist})
+}
+
+func (a *gameActor) submit(command actorCommand) error {
 	result := make(chan error, 1)
-	a.commands <- actorCommand{apply: apply, persist: persist, result: result}
-	return <-result
+	command.result = result
+	select {
+	case a.commands <- command:
+	case <-a.done:
+		return errGameUnloaded
+	}
+	select {
+	case err := <-result:
+		return err
+	case <-a.done:
+		select {
+		case err := <-result:
+			return err
+		default:
+			return errGameUnloaded
+		}
+	}
 }
 
 func (a *gameActor) snapshot() *Game {
 	result := make(chan *Game, 1)
-	a.commands <- actorCommand{read: result}
-	return <-result
+	select {
+	case a.commands <- actorCommand{read: result}:
+	case <-a.done:
+		return cloneGame(a.game)
+	}
+	select {
+	case game := <-result:
+		return game
+	case <-a.done:
+		select {
+		case game := <-result:
+			return game
+		default:
+			return cloneGame(a.game)
+		}
+	}
 }
@@ -0,0 +1,674 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	domain "picture-this/internal/game"
+)
+
+// Typed state events are dot-namespaced so they never collide with the
+// underscore audit events written by persistEvent.
+const (
+	gameEventSettings      = "game.settings"
+	gameEventPhase         = "game.phase"
+	gameEventPlayerJoined  = "player.joined"
+	gameEventPlayerUpdated = "player.updated"
+	gameEventPlayerRemoved = "player.removed"
+	gameEventAudience      = "audience.joined"
+	gameEventRoundStarted  = "round.started"
+	gameEventRoundUpdated  = "round.updated"
+	gameEventRoundReset    = "round.reset"
+	gameEventRoundRemoved  = "round.removed"
+	gameEventPrompt        = "prompt.assigned"
+	gameEventDrawing       = "drawing.submitted"
+	gameEventGuess         = "guess.submitted"
+	gameEventVote          = "vote.submitted"
+	gameEventAudienceVote  = "audience_vote.submitted"
+	gameEventLike          = "like.added"
+	gameEventTitleGuess    = "title.guessed"
+	gameEventChainLink     = "chain.link"
+)
+
+var gameEventTypes = []string{
+	gameEventSettings,
+	gameEventPhase,
+	gameEventPlayerJoined,
+	gameEventPlayerUpdated,
+	gameEventPlayerRemoved,
+	gameEventAudience,
+	gameEventRoundStarted,
+	gameEventRoundUpdated,
+	gameEventRoundReset,
+	gameEventRoundRemoved,
+	gameEventPrompt,
+	gameEventDrawing,
+	gameEventGuess,
+	gameEventVote,
+	gameEventAudienceVote,
+	gameEventLike,
+	gameEventTitleGuess,
+	gameEventChainLink,
+}
+
+// GameEvent is one entry in a game's state stream. Every command committed by
+// a gameActor produces the events needed to turn the previous version into the
+// new one, all stamped with the new Version.
+type GameEvent struct {
+	Version int64
+	Type    string
+	// Round is the round number for round-scoped events; the payload carries
+	// it too so the stream can be reloaded from the events table.
+	Round   int
+	Payload json.RawMessage
+}
+
+type gameSettingsEvent struct {
+	GameID           string   `json:"game_id"`
+	DBID             uint     `json:"db_id,omitempty"`
+	JoinCode         string   `json:"join_code"`
+	MinPlayers       int      `json:"min_players"`
+	MaxPlayers       int      `json:"max_players"`
+	LobbyLocked      bool     `json:"lobby_locked"`
+	PromptsPerPlayer int      `json:"prompts_per_player"`
+	Ruleset          string   `json:"ruleset"`
+	AvatarsEnabled   bool     `json:"avatars_enabled"`
+	AudienceEnabled  bool     `json:"audience_enabled"`
+	JokesEnabled     bool     `json:"jokes_enabled"`
+	PublicReplay     bool     `json:"public_replay"`
+	TeamCount        int      `json:"team_count,omitempty"`
+	HostID           int      `json:"host_id"`
+	KickedPlayers    []string `json:"kicked_players,omitempty"`
+
+	ScoringRules domain.ScoringRules  `json:"scoring_rules"`
+	Timers       domain.TimerSettings `json:"timers"`
+}
+
+type gamePhaseEvent struct {
+	Phase             string    `json:"phase"`
+	PausedPhase       string    `json:"paused_phase,omitempty"`
+	PhaseStartedAt    time.Time `json:"phase_started_at"`
+	PausedRemainingMs int64     `json:"paused_remaining_ms,omitempty"`
+	PhaseExtensionMs  int64     `json:"phase_extension_ms,omitempty"`
+	PhaseDeadline     time.Time `json:"phase_deadline,omitzero"`
+}
+
+// playerEvent deliberately leaves out recovery hashes and auth tokens; those
+// never leave the players table and the in-memory token map.
+type playerEvent struct {
+	ID           int    `json:"id"`
+	DBID         uint   `json:"db_id,omitempty"`
+	Name         string `json:"name"`
+	Avatar       []byte `json:"avatar,omitempty"`
+	AvatarHash   string `json:"avatar_hash,omitempty"`
+	AvatarLocked bool   `json:"avatar_locked,omitempty"`
+	IsHost       bool   `json:"is_host,omitempty"`
+	Color        string `json:"color"`
+	Claimed      bool   `json:"claimed,omitempty"`
+	Team         int    `json:"team,omitempty"`
+}
+
+type playerRemovedEvent struct {
+	PlayerID int `json:"player_id"`
+}
+
+type audienceEvent struct {
+	Index int    `json:"index"`
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+}
+
+type roundEvent struct {
+	Number      int    `json:"number"`
+	DBID        uint   `json:"db_id,omitempty"`
+	RevealIndex int    `json:"reveal_index"`
+	RevealStage string `json:"reveal_stage,omitempty"`
+	ChainStep   int    `json:"chain_step,omitempty"`
+}
+
+type promptEvent struct {
+	Round         int    `json:"round"`
+	Index         int    `json:"index"`
+	PlayerID      int    `json:"player_id"`
+	Text          string `json:"text"`
+	Joke          string `json:"joke,omitempty"`
+	JokeAudioPath string `json:"joke_audio_path,omitempty"`
+	DBID          uint   `json:"db_id,omitempty"`
+}
+
+type drawingEvent struct {
+	Round     int    `json:"round"`
+	Index     int    `json:"index"`
+	PlayerID  int    `json:"player_id"`
+	ImageData []byte `json:"image_data,omitempty"`
+	Prompt    string `json:"prompt"`
+	DBID      uint   `json:"db_id,omitempty"`
+
+	Strokes   []byte `json:"strokes,omitempty"`
+	ImageHash string `json:"image_hash,omitempty"`
+}
+
+type guessEvent struct {
+	Round        int    `json:"round"`
+	Index        int    `json:"index"`
+	PlayerID     int    `json:"player_id"`
+	DrawingIndex int    `json:"drawing_index"`
+	Text         string `json:"text"`
+	DBID         uint   `json:"db_id,omitempty"`
+}
+
+type voteEvent struct {
+	Round        int    `json:"round"`
+	Index        int    `json:"index"`
+	PlayerID     int    `json:"player_id"`
+	DrawingIndex int    `json:"drawing_index"`
+	ChoiceText   string `json:"choice_text"`
+	ChoiceType   string `json:"choice_type"`
+	DBID         uint   `json:"db_id,omitempty"`
+}
+
+type audienceVoteEvent struct {
+	Round        int    `json:"round"`
+	Index        int    `json:"index"`
+	AudienceID   int    `json:"audience_id"`
+	AudienceName string `json:"audience_name"`
+	ChoiceID     string `json:"choice_id"`
+	ChoiceText   string `json:"choice_text"`
+	ChoiceType   string `json:"choice_type"`
+	DrawingIndex int    `json:"drawing_index"`
+}
+
+type likeEvent struct {
+	Round        int  `json:"round"`
+	Index        int  `json:"index"`
+	PlayerID     int  `json:"player_id"`
+	DrawingIndex int  `json:"drawing_index"`
+	GuessOwnerID int  `json:"guess_owner_id"`
+	DBID         uint `json:"db_id,omitempty"`
+}
+
+type titleGuessEvent struct {
+	Round        int `json:"round"`
+	Index        int `json:"index"`
+	PlayerID     int `json:"player_id"`
+	DrawingIndex int `json:"drawing_index"`
+}
+
+type chainLinkEvent struct {
+	Round      int    `json:"round"`
+	Index      int    `json:"index"`
+	ChainIndex int    `json:"chain_index"`
+	Step       int    `json:"step"`
+	PlayerID   int    `json:"player_id"`
+	Kind       string `json:"kind"`
+	Text       string `json:"text,omitempty"`
+	ImageData  []byte `json:"image_data,omitempty"`
+	DBID       uint   `json:"db_id,omitempty"`
+
+	ImageHash string `json:"image_hash,omitempty"`
+}
+
+type gameEventRecorder struct {
+	version int64
+	events  []GameEvent
+	err     error
+}
+
+func (r *gameEventRecorder) add(eventType string, round int, payload any) {
+	if r.err != nil {
+		return
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		r.err = err
+		return
+	}
+	r.events = append(r.events, GameEvent{Version: r.version, Type: eventType, Round: round, Payload: data})
+}
+
+// diffGameEvents describes the change from before to after as typed events.
+// Game state is almost entirely append-only, so most commands produce one or
+// two small events; anything that rewrites history falls back to resetting the
+// affected round.
+func diffGameEvents(before, after *Game) ([]GameEvent, error) {
+	if before == nil {
+		before = &Game{}
+	}
+	recorder := &gameEventRecorder{version: after.Version}
+	settingsBefore, settingsAfter := settingsPayload(before), settingsPayload(after)
+	if !reflect.DeepEqual(settingsBefore, settingsAfter) {
+		recorder.add(gameEventSettings, 0, settingsAfter)
+	}
+	if before.Phase != after.Phase || before.PausedPhase != after.PausedPhase || !before.PhaseStartedAt.Equal(after.PhaseStartedAt) || before.PausedRemaining != after.PausedRemaining || before.PhaseExtension != after.PhaseExtension || !before.PhaseDeadline.Equal(after.PhaseDeadline) {
+		recorder.add(gameEventPhase, 0, gamePhaseEvent{Phase: after.Phase, PausedPhase: after.PausedPhase, PhaseStartedAt: after.PhaseStartedAt, PausedRemainingMs: after.PausedRemaining.Milliseconds(), PhaseExtensionMs: after.PhaseExtension.Milliseconds(), PhaseDeadline: after.PhaseDeadline})
+	}
+	diffPlayers(recorder, before.Players, after.Players)
+	for i, member := range after.Audience {
+		if i < len(before.Audience) && before.Audience[i].ID == member.ID && before.Audience[i].Name == member.Name {
+			continue
+		}
+		recorder.add(gameEventAudience, 0, audienceEvent{Index: i, ID: member.ID, Name: member.Name})
+	}
+	for i := len(after.Rounds); i < len(before.Rounds); i++ {
+		recorder.add(gameEventRoundRemoved, before.Rounds[i].Number, roundEvent{Number: before.Rounds[i].Number})
+	}
+	for i := range after.Rounds {
+		if i < len(before.Rounds) {
+			diffRound(recorder, &before.Rounds[i], &after.Rounds[i])
+			continue
+		}
+		diffRound(recorder, nil, &after.Rounds[i])
+	}
+	return recorder.events, recorder.err
+}
+
+func settingsPayload(game *Game) gameSettingsEvent {
+	kicked := make([]string, 0, len(game.KickedPlayers))
+	for name := range game.KickedPlayers {
+		kicked = append(kicked, name)
+	}
+	sort.Strings(kicked)
+	return gameSettingsEvent{
+		GameID:           game.ID,
+		DBID:             game.DBID,
+		JoinCode:         game.JoinCode,
+		MinPlayers:       game.MinPlayers,
+		MaxPlayers:       game.MaxPlayers,
+		LobbyLocked:      game.LobbyLocked,
+		PromptsPerPlayer: game.PromptsPerPlayer,
+		Ruleset:          game.Ruleset,
+		AvatarsEnabled:   game.AvatarsEnabled,
+		AudienceEnabled:  game.AudienceEnabled,
+		JokesEnabled:     game.JokesEnabled,
+		PublicReplay:     game.PublicReplay,
+		TeamCount:        game.TeamCount,
+		HostID:           game.HostID,
+		KickedPlayers:    kicked,
+		ScoringRules:     game.ScoringRules,
+		Timers:           game.Timers,
+	}
+}
+
+func diffPlayers(recorder *gameEventRecorder, before, after []Player) {
+	previous := make(map[int]Player, len(before))
+	for _, player := range before {
+		previous[player.ID] = player
+	}
+	remaining := make(map[int]struct{}, len(after))
+	for _, player := range after {
+		remaining[player.ID] = struct{}{}
+	}
+	for _, player := range before {
+		if _, ok := remaining[player.ID]; !ok {
+			recorder.add(gameEventPlayerRemoved, 0, playerRemovedEvent{PlayerID: player.ID})
+		}
+	}
+	for _, player := range after {
+		old, ok := previous[player.ID]
+		if !ok {
+			recorder.add(gameEventPlayerJoined, 0, playerPayload(player))
+			continue
+		}
+		if !playersEqual(old, player) {
+			recorder.add(gameEventPlayerUpdated, 0, playerPayload(player))
+		}
+	}
+}
+
+func playerPayload(player Player) playerEvent {
+	return playerEvent{
+		ID:           player.ID,
+		DBID:         player.DBID,
+		Name:         player.Name,
+		Avatar:       player.Avatar,
+		AvatarHash:   player.AvatarHash,
+		AvatarLocked: player.AvatarLocked,
+		IsHost:       player.IsHost,
+		Color:        player.Color,
+		Claimed:      player.Claimed,
+		Team:         player.Team,
+	}
+}
+
+func playersEqual(a, b Player) bool {
+	return a.ID == b.ID && a.DBID == b.DBID && a.Name == b.Name && bytes.Equal(a.Avatar, b.Avatar) && a.AvatarHash == b.AvatarHash &&
+		a.AvatarLocked == b.AvatarLocked && a.IsHost == b.IsHost && a.Color == b.Color && a.Claimed == b.Claimed &&
+		a.Team == b.Team
+}
+
+func diffRound(recorder *gameEventRecorder, before, after *RoundState) {
+	number := after.Number
+	header := roundEvent{Number: number, DBID: after.DBID, RevealIndex: after.RevealIndex, RevealStage: after.RevealStage, ChainStep: after.ChainStep}
+	if before == nil {
+		recorder.add(gameEventRoundStarted, number, header)
+		before = &RoundState{Number: number, DBID: after.DBID, RevealIndex: after.RevealIndex, RevealStage: after.RevealStage, ChainStep: after.ChainStep}
+	} else if before.Number != after.Number || before.DBID != after.DBID || before.RevealIndex != after.RevealIndex || before.RevealStage != after.RevealStage || before.ChainStep != after.ChainStep {
+		recorder.add(gameEventRoundUpdated, number, header)
+	}
+	if !roundEntriesArePrefix(before, after) {
+		recorder.add(gameEventRoundReset, number, roundEvent{Number: number})
+		before = &RoundState{Number: number}
+	}
+	for i := len(before.Prompts); i < len(after.Prompts); i++ {
+		entry := after.Prompts[i]
+		recorder.add(gameEventPrompt, number, promptEvent{Round: number, Index: i, PlayerID: entry.PlayerID, Text: entry.Text, Joke: entry.Joke, JokeAudioPath: entry.JokeAudioPath, DBID: entry.DBID})
+	}
+	for i := len(before.Drawings); i < len(after.Drawings); i++ {
+		entry := after.Drawings[i]
+		recorder.add(gameEventDrawing, number, drawingEvent{Round: number, Index: i, PlayerID: entry.PlayerID, ImageData: entry.ImageData, Prompt: entry.Prompt, DBID: entry.DBID, Strokes: entry.Strokes, ImageHash: entry.ImageHash})
+	}
+	for i := len(before.Guesses); i < len(after.Guesses); i++ {
+		entry := after.Guesses[i]
+		recorder.add(gameEventGuess, number, guessEvent{Round: number, Index: i, PlayerID: entry.PlayerID, DrawingIndex: entry.DrawingIndex, Text: entry.Text, DBID: entry.DBID})
+	}
+	for i := len(before.Votes); i < len(after.Votes); i++ {
+		entry := after.Votes[i]
+		recorder.add(gameEventVote, number, voteEvent{Round: number, Index: i, PlayerID: entry.PlayerID, DrawingIndex: entry.DrawingIndex, ChoiceText: entry.ChoiceText, ChoiceType: entry.ChoiceType, DBID: entry.DBID})
+	}
+	for i := len(before.AudienceVotes); i < len(after.AudienceVotes); i++ {
+		entry := after.AudienceVotes[i]
+		recorder.add(gameEventAudienceVote, number, audienceVoteEvent{Round: number, Index: i, AudienceID: entry.AudienceID, AudienceName: entry.AudienceName, ChoiceID: entry.ChoiceID, ChoiceText: entry.ChoiceText, ChoiceType: entry.ChoiceType, DrawingIndex: entry.DrawingIndex})
+	}
+	for i := len(before.Likes); i < len(after.Likes); i++ {
+		entry := after.Likes[i]
+		recorder.add(gameEventLike, number, likeEvent{Round: number, Index: i, PlayerID: entry.PlayerID, DrawingIndex: entry.DrawingIndex, GuessOwnerID: entry.GuessOwnerID, DBID: entry.DBID})
+	}
+	for i := len(before.TitleGuesses); i < len(after.TitleGuesses); i++ {
+		entry := after.TitleGuesses[i]
+		recorder.add(gameEventTitleGuess, number, titleGuessEvent{Round: number, Index: i, PlayerID: entry.PlayerID, DrawingIndex: entry.DrawingIndex})
+	}
+	for i := len(before.ChainLinks); i < len(after.ChainLinks); i++ {
+		entry := after.ChainLinks[i]
+		recorder.add(gameEventChainLink, number, chainLinkEvent{Round: number, Index: i, ChainIndex: entry.ChainIndex, Step: entry.Step, PlayerID: entry.PlayerID, Kind: entry.Kind, Text: entry.Text, ImageData: entry.ImageData, DBID: entry.DBID, ImageHash: entry.ImageHash})
+	}
+}
+
+// roundEntriesArePrefix reports whether every entry list in before is an
+// unchanged prefix of the matching list in after.
+func roundEntriesArePrefix(before, after *RoundState) bool {
+	if len(before.Prompts) > len(after.Prompts) || len(before.Drawings) > len(after.Drawings) ||
+		len(before.Guesses) > len(after.Guesses) || len(before.Votes) > len(after.Votes) ||
+		len(before.AudienceVotes) > len(after.AudienceVotes) || len(before.Likes) > len(after.Likes) ||
+		len(before.TitleGuesses) > len(after.TitleGuesses) || len(before.ChainLinks) > len(after.ChainLinks) {
+		return false
+	}
+	for i := range before.Prompts {
+		if before.Prompts[i] != after.Prompts[i] {
+			return false
+		}
+	}
+	for i := range before.Drawings {
+		a, b := before.Drawings[i], after.Drawings[i]
+		if a.PlayerID != b.PlayerID || a.Prompt != b.Prompt || a.DBID != b.DBID || !bytes.Equal(a.ImageData, b.ImageData) || !bytes.Equal(a.Strokes, b.Strokes) || a.ImageHash != b.ImageHash {
+			return false
+		}
+	}
+	for i := range before.Guesses {
+		if before.Guesses[i] != after.Guesses[i] {
+			return false
+		}
+	}
+	for i := range before.Votes {
+		if before.Votes[i] != after.Votes[i] {
+			return false
+		}
+	}
+	for i := range before.AudienceVotes {
+		if before.AudienceVotes[i] != after.AudienceVotes[i] {
+			return false
+		}
+	}
+	for i := range before.Likes {
+		if before.Likes[i] != after.Likes[i] {
+			return false
+		}
+	}
+	for i := range before.TitleGuesses {
+		if before.TitleGuesses[i] != after.TitleGuesses[i] {
+			return false
+		}
+	}
+	for i := range before.ChainLinks {
+		a, b := before.ChainLinks[i], after.ChainLinks[i]
+		if a.ChainIndex != b.ChainIndex || a.Step != b.Step || a.PlayerID != b.PlayerID || a.Kind != b.Kind ||
+			a.Text != b.Text || a.DBID != b.DBID || !bytes.Equal(a.ImageData, b.ImageData) || a.ImageHash != b.ImageHash {
+			return false
+		}
+	}
+	return true
+}
+
+// replayGameEvents folds a game's event stream into a Game. Events newer than
+// version are ignored, so any committed version can be rebuilt; a version of
+// zero or less replays the whole stream.
+func replayGameEvents(events []GameEvent, version int64) (*Game, error) {
+	game := &Game{
+		UsedPrompts:      make(map[string]struct{}),
+		KickedPlayers:    make(map[string]struct{}),
+		PlayerAuthTokens: make(map[int]string),
+	}
+	applied := 0
+	for _, event := range events {
+		if version > 0 && event.Version > version {
+			break
+		}
+		if err := applyGameEvent(game, event); err != nil {
+			return nil, fmt.Errorf("apply %s at version %d: %w", event.Type, event.Version, err)
+		}
+		game.Version = event.Version
+		applied++
+	}
+	if applied == 0 {
+		return nil, errors.New("no events to replay")
+	}
+	game.UsedPrompts = usedPrompts(game.Rounds)
+	return game, nil
+}
+
+// errEventGap reports events that do not follow on from a game's version.
+var errEventGap = errors.New("event stream has a version gap")
+
+// advanceGameEvents applies the events after base's version to a copy of
+// base. It returns errEventGap when the first new event skips a version, so
+// the caller can replay the whole stream instead.
+func advanceGameEvents(base *Game, events []GameEvent) (*Game, error) {
+	game := cloneGame(base)
+	for _, event := range events {
+		if event.Version <= base.Version {
+			continue
+		}
+		if game.Version == base.Version && event.Version != base.Version+1 {
+			return nil, errEventGap
+		}
+		if err := applyGameEvent(game, event); err != nil {
+			return nil, fmt.Errorf("apply %s at version %d: %w", event.Type, event.Version, err)
+		}
+		game.Version = event.Version
+	}
+	game.UsedPrompts = usedPrompts(game.Rounds)
+	return game, nil
+}
+
+func applyGameEvent(game *Game, event GameEvent) error {
+	switch event.Type {
+	case gameEventSettings:
+		var payload gameSettingsEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		game.ID = payload.GameID
+		game.DBID = payload.DBID
+		game.JoinCode = payload.JoinCode
+		game.MinPlayers = payload.MinPlayers
+		game.MaxPlayers = payload.MaxPlayers
+		game.LobbyLocked = payload.LobbyLocked
+		game.PromptsPerPlayer = payload.PromptsPerPlayer
+		game.Ruleset = payload.Ruleset
+		game.AvatarsEnabled = payload.AvatarsEnabled
+		game.AudienceEnabled = payload.AudienceEnabled
+		game.JokesEnabled = payload.JokesEnabled
+		game.PublicReplay = payload.PublicReplay
+		game.TeamCount = payload.TeamCount
+		game.ScoringRules = payload.ScoringRules
+		game.Timers = payload.Timers
+		game.HostID = payload.HostID
+		game.KickedPlayers = make(map[string]struct{}, len(payload.KickedPlayers))
+		for _, name := range payload.KickedPlayers {
+			game.KickedPlayers[name] = struct{}{}
+		}
+	case gameEventPhase:
+		var payload gamePhaseEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		game.Phase = payload.Phase
+		game.PausedPhase = payload.PausedPhase
+		game.PhaseStartedAt = payload.PhaseStartedAt
+		game.PausedRemaining = time.Duration(payload.PausedRemainingMs) * time.Millisecond
+		game.PhaseExtension = time.Duration(payload.PhaseExtensionMs) * time.Millisecond
+		game.PhaseDeadline = payload.PhaseDeadline
+	case gameEventPlayerJoined, gameEventPlayerUpdated:
+		var payload playerEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		player := Player{ID: payload.ID, DBID: payload.DBID, Name: payload.Name, Avatar: payload.Avatar, AvatarHash: payload.AvatarHash, AvatarLocked: payload.AvatarLocked, IsHost: payload.IsHost, Color: payload.Color, Claimed: payload.Claimed, Team: payload.Team}
+		for i := range game.Players {
+			if game.Players[i].ID == player.ID {
+				player.RecoveryHash = game.Players[i].RecoveryHash
+				game.Players[i] = player
+				return nil
+			}
+		}
+		if event.Type == gameEventPlayerUpdated {
+			return errors.New("player not found")
+		}
+		game.Players = append(game.Players, player)
+	case gameEventPlayerRemoved:
+		var payload playerRemovedEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		for i := range game.Players {
+			if game.Players[i].ID == payload.PlayerID {
+				game.Players = append(game.Players[:i], game.Players[i+1:]...)
+				return nil
+			}
+		}
+		return errors.New("player not found")
+	case gameEventAudience:
+		var payload audienceEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		member := AudienceMember{ID: payload.ID, Name: payload.Name}
+		return placeEntry(&game.Audience, payload.Index, member)
+	case gameEventRoundStarted:
+		var payload roundEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		game.Rounds = append(game.Rounds, RoundState{Number: payload.Number, DBID: payload.DBID, RevealIndex: payload.RevealIndex, RevealStage: payload.RevealStage, ChainStep: payload.ChainStep})
+	case gameEventRoundRemoved:
+		for i := range game.Rounds {
+			if game.Rounds[i].Number == event.Round {
+				game.Rounds = append(game.Rounds[:i], game.Rounds[i+1:]...)
+				return nil
+			}
+		}
+		return errors.New("round not found")
+	default:
+		return applyRoundEvent(game, event)
+	}
+	return nil
+}
+
+func applyRoundEvent(game *Game, event GameEvent) error {
+	round := roundByNumber(game, event.Round)
+	if round == nil {
+		return errors.New("round not found")
+	}
+	switch event.Type {
+	case gameEventRoundUpdated:
+		var payload roundEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		round.DBID = payload.DBID
+		round.RevealIndex = payload.RevealIndex
+		round.RevealStage = payload.RevealStage
+		round.ChainStep = payload.ChainStep
+	case gameEventRoundReset:
+		*round = RoundState{Number: round.Number, DBID: round.DBID, RevealIndex: round.RevealIndex, RevealStage: round.RevealStage, ChainStep: round.ChainStep}
+	case gameEventPrompt:
+		var payload promptEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		return placeEntry(&round.Prompts, payload.Index, PromptEntry{PlayerID: payload.PlayerID, Text: payload.Text, Joke: payload.Joke, JokeAudioPath: payload.JokeAudioPath, DBID: payload.DBID})
+	case gameEventDrawing:
+		var payload drawingEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		return placeEntry(&round.Drawings, payload.Index, DrawingEntry{PlayerID: payload.PlayerID, ImageData: payload.ImageData, Prompt: payload.Prompt, DBID: payload.DBID, Strokes: payload.Strokes, ImageHash: payload.ImageHash})
+	case gameEventGuess:
+		var payload guessEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		return placeEntry(&round.Guesses, payload.Index, GuessEntry{PlayerID: payload.PlayerID, DrawingIndex: payload.DrawingIndex, Text: payload.Text, DBID: payload.DBID})
+	case gameEventVote:
+		var payload voteEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		return placeEntry(&round.Votes, payload.Index, VoteEntry{PlayerID: payload.PlayerID, DrawingIndex: payload.DrawingIndex, ChoiceText: payload.ChoiceText, ChoiceType: payload.ChoiceType, DBID: payload.DBID})
+	case gameEventAudienceVote:
+		var payload audienceVoteEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		return placeEntry(&round.AudienceVotes, payload.Index, AudienceVoteEntry{AudienceID: payload.AudienceID, AudienceName: payload.AudienceName, ChoiceID: payload.ChoiceID, ChoiceText: payload.ChoiceText, ChoiceType: payload.ChoiceType, DrawingIndex: payload.DrawingIndex})
+	case gameEventLike:
+		var payload likeEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		return placeEntry(&round.Likes, payload.Index, LikeEntry{PlayerID: payload.PlayerID, DrawingIndex: payload.DrawingIndex, GuessOwnerID: payload.GuessOwnerID, DBID: payload.DBID})
+	case gameEventTitleGuess:
+		var payload titleGuessEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		return placeEntry(&round.TitleGuesses, payload.Index, TitleGuessEntry{PlayerID: payload.PlayerID, DrawingIndex: payload.DrawingIndex})
+	case gameEventChainLink:
+		var payload chainLinkEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		return placeEntry(&round.ChainLinks, payload.Index, ChainLinkEntry{ChainIndex: payload.ChainIndex, Step: payload.Step, PlayerID: payload.PlayerID, Kind: payload.Kind, Text: payload.Text, ImageData: payload.ImageData, DBID: payload.DBID, ImageHash: payload.ImageHash})
+	default:
+		return errors.New("unknown event type")
+	}
+	return nil
+}
+
+// placeEntry appends at the end of the list or overwrites an existing slot,
+// matching how diffGameEvents indexes entries.
+func placeEntry[T any](list *[]T, index int, entry T) error {
+	switch {
+	case index == len(*list):
+		*list = append(*list, entry)
+	case index >= 0 && index < len(*list):
+		(*list)[index] = entry
+	default:
+		return fmt.Errorf("entry index %d out of range", index)
+	}
+	return nil
+}
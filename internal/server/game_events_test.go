@@ -0,0 +1,243 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type memoryJournal struct {
+	events   []GameEvent
+	versions map[int64]*Game
+}
+
+func newJournaledStore() (*Store, *memoryJournal) {
+	journal := &memoryJournal{versions: map[int64]*Game{}}
+	store := NewStore()
+	store.SetJournal(func(game *Game, events []GameEvent) error {
+		journal.events = append(journal.events, events...)
+		journal.versions[game.Version] = cloneGame(game)
+		return nil
+	})
+	return store, journal
+}
+
+// replayable strips the state that is intentionally kept out of the event
+// stream so replayed and live games can be compared directly.
+func replayable(game *Game) string {
+	clone := cloneGame(game)
+	clone.PlayerAuthTokens = nil
+	clone.DisplayToken = ""
+	clone.DisplayTokenHash = ""
+	clone.UsedPrompts = usedPrompts(clone.Rounds)
+	clone.PhaseStartedAt = clone.PhaseStartedAt.Round(0)
+	clone.PhaseDeadline = clone.PhaseDeadline.Round(0)
+	for i := range clone.Players {
+		clone.Players[i].RecoveryHash = ""
+	}
+	return fmt.Sprintf("%+v", *clone)
+}
+
+func TestGameEventsReplayEveryVersion(t *testing.T) {
+	store, journal := newJournaledStore()
+	game := store.CreateGame(1)
+	for _, name := range []string{"Ada", "Ben", "Cam", "Dee"} {
+		if _, _, err := store.AddPlayer(game.ID, name, nil, "hash-"+name); err != nil {
+			t.Fatalf("add player %s: %v", name, err)
+		}
+	}
+	steps := []func(*Game) error{
+		func(g *Game) error {
+			g.KickedPlayers["Dee"] = struct{}{}
+			g.Players = g.Players[:3]
+			g.AudienceEnabled = true
+			g.Audience = append(g.Audience, AudienceMember{ID: 1, Name: "Viewer"})
+			return nil
+		},
+		func(g *Game) error {
+			g.Rounds = append(g.Rounds, RoundState{Number: 1})
+			round := &g.Rounds[0]
+			for _, player := range g.Players {
+				round.Prompts = append(round.Prompts, PromptEntry{PlayerID: player.ID, Text: "prompt " + player.Name})
+			}
+			setPhase(g, phaseDrawings)
+			return nil
+		},
+		func(g *Game) error {
+			round := &g.Rounds[0]
+			for _, prompt := range round.Prompts {
+				round.Drawings = append(round.Drawings, DrawingEntry{PlayerID: prompt.PlayerID, Prompt: prompt.Text, ImageData: []byte{byte(prompt.PlayerID)}})
+			}
+			setPhase(g, phaseGuesses)
+			return nil
+		},
+		func(g *Game) error {
+			round := &g.Rounds[0]
+			round.Guesses = append(round.Guesses, GuessEntry{PlayerID: g.Players[1].ID, DrawingIndex: 0, Text: "a lie"})
+			round.Votes = append(round.Votes, VoteEntry{PlayerID: g.Players[2].ID, DrawingIndex: 0, ChoiceText: "a lie", ChoiceType: "guess"})
+			round.AudienceVotes = append(round.AudienceVotes, AudienceVoteEntry{AudienceID: 1, AudienceName: "Viewer", ChoiceText: "a lie", ChoiceType: "guess"})
+			round.Likes = append(round.Likes, LikeEntry{PlayerID: g.Players[0].ID, DrawingIndex: 0, GuessOwnerID: g.Players[1].ID})
+			round.RevealIndex = 1
+			return nil
+		},
+		func(g *Game) error {
+			g.Rounds[0].Guesses[0].Text = "an edited lie"
+			g.Players[0].Avatar = []byte{9}
+			return nil
+		},
+		func(g *Game) error {
+			g.Rounds = nil
+			setPhase(g, phaseLobby)
+			return nil
+		},
+	}
+	for i, step := range steps {
+		if _, err := store.UpdateGame(game.ID, step); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	final, _ := store.GetGame(game.ID)
+	for version, want := range journal.versions {
+		got, err := replayGameEvents(journal.events, version)
+		if err != nil {
+			t.Fatalf("replay version %d: %v", version, err)
+		}
+		if replayable(got) != replayable(want) {
+			t.Fatalf("version %d mismatch:\n got %s\nwant %s", version, replayable(got), replayable(want))
+		}
+	}
+	got, err := replayGameEvents(journal.events, 0)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if got.Version != final.Version || replayable(got) != replayable(final) {
+		t.Fatalf("full replay mismatch:\n got %s\nwant %s", replayable(got), replayable(final))
+	}
+	for _, event := range journal.events {
+		if bytes.Contains(event.Payload, []byte("hash-")) {
+			t.Fatalf("event %s leaked a recovery hash: %s", event.Type, event.Payload)
+		}
+	}
+}
+
+func TestAdvanceGameEventsAppliesOnlyNewEvents(t *testing.T) {
+	store, journal := newJournaledStore()
+	game := store.CreateGame(1)
+	for _, name := range []string{"Ada", "Ben", "Cam"} {
+		if _, _, err := store.AddPlayer(game.ID, name, nil, ""); err != nil {
+			t.Fatalf("add player %s: %v", name, err)
+		}
+	}
+	base, err := replayGameEvents(journal.events, 0)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	before := replayable(base)
+	if _, err := store.UpdateGame(game.ID, func(g *Game) error {
+		g.Rounds = append(g.Rounds, RoundState{Number: 1})
+		setPhase(g, phaseDrawings)
+		return nil
+	}); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if _, err := store.UpdateGame(game.ID, func(g *Game) error {
+		g.Rounds[0].Drawings = append(g.Rounds[0].Drawings, DrawingEntry{PlayerID: 1, Prompt: "cat", ImageData: []byte{1}})
+		return nil
+	}); err != nil {
+		t.Fatalf("draw: %v", err)
+	}
+
+	final, _ := store.GetGame(game.ID)
+	got, err := advanceGameEvents(base, journal.events)
+	if err != nil {
+		t.Fatalf("advance: %v", err)
+	}
+	if got.Version != final.Version || replayable(got) != replayable(final) {
+		t.Fatalf("advanced replica mismatch:\n got %s\nwant %s", replayable(got), replayable(final))
+	}
+	if replayable(base) != before {
+		t.Fatal("expected the cached replica to be left unchanged")
+	}
+
+	var skipped []GameEvent
+	for _, event := range journal.events {
+		if event.Version != base.Version+1 {
+			skipped = append(skipped, event)
+		}
+	}
+	if _, err := advanceGameEvents(base, skipped); !errors.Is(err, errEventGap) {
+		t.Fatalf("expected a version gap, got %v", err)
+	}
+}
+
+func TestGameEventsJournalFailureRejectsCommand(t *testing.T) {
+	store := NewStore()
+	store.SetJournal(func(*Game, []GameEvent) error {
+		return errors.New("database unavailable")
+	})
+	game := store.CreateGame(1)
+	if _, err := store.UpdateGame(game.ID, func(g *Game) error {
+		g.LobbyLocked = true
+		return nil
+	}); err == nil {
+		t.Fatal("expected journal failure")
+	}
+	got, _ := store.GetGame(game.ID)
+	if got.LobbyLocked || got.Version != game.Version {
+		t.Fatalf("unjournaled command leaked state: locked=%v version=%d", got.LobbyLocked, got.Version)
+	}
+}
+
+func TestGameCommandsPersistAndJournalInOneTransaction(t *testing.T) {
+	store, journal := newJournaledStore()
+	committed := 0
+	failCommit := true
+	store.SetTransaction(func(game *Game, write func() error) error {
+		// Stand in for a database transaction: nothing written is kept
+		// unless the whole unit succeeds.
+		before := len(journal.events)
+		err := write()
+		if err == nil && failCommit {
+			err = errors.New("commit failed")
+		}
+		if err != nil {
+			journal.events = journal.events[:before]
+			return err
+		}
+		committed++
+		return nil
+	})
+	game := store.CreateGame(1)
+	persisted := 0
+	lock := func(g *Game) error {
+		g.LobbyLocked = true
+		return nil
+	}
+	persist := func(*Game) error {
+		persisted++
+		return nil
+	}
+	if _, err := store.UpdateGameDurably(game.ID, lock, persist); err == nil {
+		t.Fatal("expected the failed transaction to reject the command")
+	}
+	if got, _ := store.GetGame(game.ID); got.LobbyLocked || got.Version != game.Version {
+		t.Fatalf("rolled back command leaked state: locked=%v version=%d", got.LobbyLocked, got.Version)
+	}
+	if persisted != 1 || len(journal.events) != 0 {
+		t.Fatalf("expected the row write and journal to run together and roll back, got %d writes and %d events", persisted, len(journal.events))
+	}
+
+	failCommit = false
+	if _, err := store.UpdateGameDurably(game.ID, lock, persist); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	got, err := replayGameEvents(journal.events, 0)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if !got.LobbyLocked || committed != 1 {
+		t.Fatalf("expected the retried command to journal its baseline and change, got locked=%v after %d commits", got.LobbyLocked, committed)
+	}
+}
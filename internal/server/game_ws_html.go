@@ -42,7 +42,7 @@ func buildPlayerListItems(game *Game) []web.PlayerListItem {
 		items = append(items, web.PlayerListItem{
 			ID:     player.ID,
 			Name:   player.Name,
-			Avatar: encodeImageData(player.Avatar),
+			Avatar: imageSource(player.Avatar, player.AvatarHash),
 			Color:  player.Color,
 			IsHost: player.ID == game.HostID,
 		})
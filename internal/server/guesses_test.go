@@ -0,0 +1,91 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func guessingGame(ruleset string) (*Game, *RoundState) {
+	game := &Game{Ruleset: ruleset, Players: []Player{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}, {ID: 3, Name: "Cam"}}}
+	game.Rounds = []RoundState{{
+		Number:   1,
+		Drawings: []DrawingEntry{{PlayerID: 1, Prompt: "Dancing Penguins"}},
+		Guesses:  []GuessEntry{{PlayerID: 2, DrawingIndex: 0, Text: "A birthday party"}},
+	}}
+	return game, &game.Rounds[0]
+}
+
+func TestCheckGuessTextRejectsNearDuplicateLies(t *testing.T) {
+	game, round := guessingGame(rulesetDrawful)
+	for _, text := range []string{"a birthday party", "Birthday parties!", "birthday partt"} {
+		if _, err := checkGuessText(game, round, 0, 3, text); err == nil {
+			t.Fatalf("expected %q to be rejected", text)
+		}
+	}
+	if _, err := checkGuessText(game, round, 0, 3, "A funeral"); err != nil {
+		t.Fatalf("expected a distinct lie to pass, got %v", err)
+	}
+}
+
+func TestCheckGuessTextHandlesTheRealTitle(t *testing.T) {
+	game, round := guessingGame(rulesetDrawful)
+	isTitle, err := checkGuessText(game, round, 0, 3, "dancing penguin!")
+	if err != nil || !isTitle {
+		t.Fatalf("expected a title guess, got %v %v", isTitle, err)
+	}
+	round.TitleGuesses = append(round.TitleGuesses, TitleGuessEntry{PlayerID: 3, DrawingIndex: 0})
+	if _, err := checkGuessText(game, round, 0, 3, "Dancing Penguins"); err == nil || !strings.Contains(err.Error(), "already guessed") {
+		t.Fatalf("expected a second title guess to be rejected, got %v", err)
+	}
+
+	legacy, legacyRound := guessingGame(rulesetLegacy)
+	if isTitle, err := checkGuessText(legacy, legacyRound, 0, 3, "dancing penguins"); err == nil || isTitle {
+		t.Fatalf("expected the legacy ruleset to reject the title, got %v %v", isTitle, err)
+	}
+}
+
+func TestTitleGuessEarnsBonus(t *testing.T) {
+	game, round := guessingGame(rulesetDrawful)
+	round.TitleGuesses = []TitleGuessEntry{{PlayerID: 3, DrawingIndex: 0}}
+	deltas := drawingScoreDeltas(game, round, 0, buildNameMap(game.Players))
+	if len(deltas) != 1 || deltas[0]["player_id"] != 3 || deltas[0]["delta"] != 250 {
+		t.Fatalf("expected the title guess bonus, got %v", deltas)
+	}
+	if scores := buildScores(game); scores[0]["player_id"] != 3 || scores[0]["score"] != 250 {
+		t.Fatalf("expected scores to include the bonus, got %v", scores)
+	}
+}
+
+func TestTitleGuessesReplayFromEvents(t *testing.T) {
+	store, journal := newJournaledStore()
+	game := store.CreateGame(1)
+	steps := []func(*Game) error{
+		func(g *Game) error {
+			g.Players = []Player{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}}
+			g.Rounds = []RoundState{{Number: 1, Drawings: []DrawingEntry{{PlayerID: 1, Prompt: "real"}}}}
+			setPhase(g, phaseGuesses)
+			return nil
+		},
+		func(g *Game) error {
+			round := &g.Rounds[0]
+			round.TitleGuesses = append(round.TitleGuesses, TitleGuessEntry{PlayerID: 2, DrawingIndex: 0})
+			return nil
+		},
+	}
+	for i, step := range steps {
+		if _, err := store.UpdateGame(game.ID, step); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	final, _ := store.GetGame(game.ID)
+	got, err := replayGameEvents(journal.events, 0)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayable(got) != replayable(final) {
+		t.Fatalf("replay mismatch:\n got %s\nwant %s", replayable(got), replayable(final))
+	}
+	if len(got.Rounds[0].TitleGuesses) != 1 {
+		t.Fatalf("expected the title guess to replay, got %+v", got.Rounds[0].TitleGuesses)
+	}
+}
@@ -147,7 +147,7 @@ func (s *Server) loadAdminDataByID(gameDBID uint) (web.AdminData, string) {
 	if err := s.db.Where("game_id = ?", gameDBID).Order("number asc").Find(&data.Rounds).Error; err != nil {
 		return data, "Failed to load rounds."
 	}
-	if err := s.db.Where("game_id = ?", gameDBID).Order("created_at asc").Find(&data.Events).Error; err != nil {
+	if err := s.db.Where("game_id = ? AND type NOT IN ?", gameDBID, gameEventTypes).Order("created_at asc").Find(&data.Events).Error; err != nil {
 		return data, "Failed to load events."
 	}
 
@@ -2,7 +2,9 @@ package server
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -67,13 +69,8 @@ func (s *Server) handleAdminResumeGame(c *gin.Context) {
 		c.Redirect(http.StatusFound, "/admin/"+gameID)
 		return
 	}
-	resumePhase := game.PausedPhase
-	if resumePhase == "" {
-		resumePhase = phaseLobby
-	}
 	game, err := s.store.UpdateGame(game.ID, func(game *Game) error {
-		setPhase(game, resumePhase)
-		game.PausedPhase = ""
+		s.resumePausedPhase(game, time.Now().UTC())
 		return nil
 	})
 	if err != nil {
@@ -88,7 +85,7 @@ func (s *Server) handleAdminResumeGame(c *gin.Context) {
 	} else {
 		s.schedulePhaseTimer(game)
 	}
-	_ = s.persistEvent(game, "game_resumed", EventPayload{Phase: game.Phase, Reason: "resume"})
+	_ = s.persistPhase(game, "game_resumed", EventPayload{Phase: game.Phase, Reason: "resume"})
 	if s.sessions != nil {
 		s.sessions.SetFlash(c.Writer, c.Request, "Game resumed.")
 	}
@@ -96,6 +93,38 @@ func (s *Server) handleAdminResumeGame(c *gin.Context) {
 	c.Redirect(http.StatusFound, "/admin/"+gameID)
 }
 
+func (s *Server) handleAdminGameVersion(c *gin.Context) {
+	if s.db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "database not configured"})
+		return
+	}
+	version, err := strconv.ParseInt(c.Param("version"), 10, 64)
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+	dbID, displayID, err := s.resolveAdminGameID(strings.TrimSpace(c.Param("gameID")))
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	events, err := s.loadGameEvents(dbID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load events"})
+		return
+	}
+	game, err := replayGameEvents(events, version)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"game_id": displayID,
+		"version": game.Version,
+		"state":   snapshotWithConfig(game, s.cfg),
+	})
+}
+
 func (s *Server) findGameInStore(gameID string) (*Game, bool) {
 	if game, ok := s.store.GetGame(gameID); ok {
 		return game, true
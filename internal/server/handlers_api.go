@@ -1,7 +1,9 @@
 package server
 
 import (
+	"context"
 	"crypto/subtle"
+	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
@@ -10,6 +12,7 @@ import (
 	"time"
 
 	"picture-this/internal/db"
+	domain "picture-this/internal/game"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,6 +26,12 @@ type settingsRequest struct {
 	AudienceEnabled bool   `json:"audience_enabled"`
 	JokesEnabled    bool   `json:"jokes_enabled"`
 	PublicReplay    bool   `json:"public_replay"`
+	Ruleset         string `json:"ruleset"`
+	TeamCount       int    `json:"team_count" binding:"omitempty,min=2,max=4"`
+	// ScoringRules is optional; omitting it keeps the game's current rules.
+	ScoringRules *domain.ScoringRules `json:"scoring_rules"`
+	// Timers is optional too; a named preset replaces the durations.
+	Timers *domain.TimerSettings `json:"timers"`
 }
 
 type createGameRequest struct {
@@ -36,6 +45,12 @@ type kickRequest struct {
 	AuthToken string `json:"auth_token"`
 }
 
+type transferHostRequest struct {
+	PlayerID  int    `json:"player_id" binding:"required,gt=0"`
+	TargetID  int    `json:"target_id" binding:"required,gt=0"`
+	AuthToken string `json:"auth_token"`
+}
+
 type playerPromptURI struct {
 	GameID   string `uri:"gameID" binding:"required"`
 	PlayerID int    `uri:"playerID" binding:"required,gt=0"`
@@ -74,11 +89,22 @@ type avatarRequest struct {
 	AvatarData string `json:"avatar_data" binding:"required"`
 	AuthToken  string `json:"auth_token"`
 }
+
+// drawingsRequest carries either a stroke document or, from older clients, a
+// base64 PNG.
 type drawingsRequest struct {
-	PlayerID  int    `json:"player_id" binding:"required,gt=0"`
-	ImageData string `json:"image_data" binding:"required"`
-	Prompt    string `json:"prompt" binding:"required,prompt"`
-	AuthToken string `json:"auth_token"`
+	PlayerID  int             `json:"player_id" binding:"required,gt=0"`
+	ImageData string          `json:"image_data"`
+	Strokes   json.RawMessage `json:"strokes"`
+	Prompt    string          `json:"prompt" binding:"required,prompt"`
+	AuthToken string          `json:"auth_token"`
+}
+
+type chainLinkRequest struct {
+	PlayerID    int    `json:"player_id" binding:"required,gt=0"`
+	ImageData   string `json:"image_data"`
+	Description string `json:"description" binding:"omitempty,prompt"`
+	AuthToken   string `json:"auth_token"`
 }
 
 type guessesRequest struct {
@@ -112,15 +138,154 @@ type endRequest struct {
 	AuthToken string `json:"auth_token"`
 }
 
+var (
+	settingsBinding = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "player_id is required",
+			"gt":       "player_id is required",
+		},
+		"Rounds": {
+			"max": "rounds exceeds maximum",
+		},
+		"TeamCount": {
+			"min": "team count must be at least 2",
+			"max": "team count exceeds maximum",
+		},
+	}, "invalid settings"}
+	kickBinding = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "player_id and target_id are required",
+			"gt":       "player_id and target_id are required",
+		},
+		"TargetID": {
+			"required": "player_id and target_id are required",
+			"gt":       "player_id and target_id are required",
+		},
+	}, "player_id and target_id are required"}
+	transferHostBinding = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "player_id and target_id are required",
+			"gt":       "player_id and target_id are required",
+		},
+		"TargetID": {
+			"required": "player_id and target_id are required",
+			"gt":       "player_id and target_id are required",
+		},
+	}, "player_id and target_id are required"}
+	startBinding = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "player_id is required",
+			"gt":       "player_id is required",
+		},
+	}, "player_id is required"}
+	drawingsBinding = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "drawings are required",
+			"gt":       "drawings are required",
+		},
+		"Prompt": {
+			"required": "drawings are required",
+			"prompt":   "prompt is invalid",
+		},
+	}, "drawings are required"}
+	guessesBinding = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "guesses are required",
+			"gt":       "guesses are required",
+		},
+		"Guess": {
+			"required": "guesses are required",
+			"guess":    "guess is invalid",
+		},
+	}, "guesses are required"}
+	chainLinkBinding = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "player_id is required",
+			"gt":       "player_id is required",
+		},
+		"Description": {
+			"prompt": "description is invalid",
+		},
+	}, "invalid chain link"}
+	votesBinding = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "votes are required",
+			"gt":       "votes are required",
+		},
+	}, "votes are required"}
+	likesBinding   = requestBinding{bindMessages{}, "invalid like"}
+	advanceBinding = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "player_id is required",
+			"gt":       "player_id is required",
+		},
+	}, "player_id is required"}
+	pauseBinding = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "player_id is required",
+			"gt":       "player_id is required",
+		},
+	}, "player_id is required"}
+	addTimeBinding = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "player_id is required",
+			"gt":       "player_id is required",
+		},
+	}, "player_id is required"}
+	resumeBinding = requestBinding{bindMessages{}, "player_id is required"}
+	endBinding    = requestBinding{bindMessages{
+		"PlayerID": {
+			"required": "player_id is required",
+			"gt":       "player_id is required",
+		},
+	}, "player_id is required"}
+)
+
+// commandError is a rejected request that is not a conflict with the game's
+// state, such as malformed input.
+type commandError struct {
+	status  int
+	message string
+}
+
+func (e *commandError) Error() string {
+	return e.message
+}
+
+func badCommand(message string) error {
+	return &commandError{status: http.StatusBadRequest, message: message}
+}
+
+func internalCommandError(message string) error {
+	return &commandError{status: http.StatusInternalServerError, message: message}
+}
+
+// mutationErrorStatus maps an error from a game mutation to the HTTP status
+// it is reported with.
+func mutationErrorStatus(err error) int {
+	var cmdErr *commandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.status
+	}
+	if err.Error() == "game not found" {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, errGameOwnedElsewhere) {
+		return http.StatusMisdirectedRequest
+	}
+	return http.StatusConflict
+}
+
 func respondGameMutationError(c *gin.Context, err error) bool {
 	if err == nil {
 		return false
 	}
-	if err.Error() == "game not found" {
-		c.Status(http.StatusNotFound)
+	status := mutationErrorStatus(err)
+	if status == http.StatusNotFound {
+		c.Status(status)
 		return true
 	}
-	c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	c.JSON(status, gin.H{"error": err.Error()})
 	return true
 }
 
@@ -158,6 +323,11 @@ func (s *Server) handleCreateGame(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create game"})
 		return
 	}
+	if err := s.claimGame(game); err != nil {
+		s.deleteFailedGame(game)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create game"})
+		return
+	}
 	recoveryCode, recoveryHash, err := newRecoveryCredential()
 	if err != nil {
 		s.deleteFailedGame(game)
@@ -165,7 +335,7 @@ func (s *Server) handleCreateGame(c *gin.Context) {
 		return
 	}
 	createdGame := game
-	game, host, err := s.store.AddPlayerDurably(game.ID, user.Username, nil, recoveryHash, func(game *Game, player *Player) error {
+	game, host, err := s.store.AddPlayerDurably(game.ID, user.Username, nil, "", recoveryHash, func(game *Game, player *Player) error {
 		_, err := s.persistPlayer(game, player)
 		return err
 	})
@@ -209,7 +379,7 @@ func (s *Server) handlePlayerPrompt(c *gin.Context) {
 		c.Status(http.StatusNotFound)
 		return
 	}
-	if _, err := s.authenticatePlayerRequest(c, game, player.ID, c.Query("auth_token")); err != nil {
+	if _, err := s.authenticatePlayerRequest(game, player.ID, c.Query("auth_token")); err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
@@ -231,11 +401,7 @@ func (s *Server) handlePlayerPrompt(c *gin.Context) {
 }
 
 func (s *Server) handleGetGame(c *gin.Context) {
-	gameID := c.Param("gameID")
-	game, ok := s.store.GetGame(gameID)
-	if !ok {
-		game, ok = s.store.FindGameByJoinCode(gameID)
-	}
+	game, ok := s.loadGame(c.Param("gameID"))
 	if !ok {
 		c.Status(http.StatusNotFound)
 		return
@@ -253,7 +419,7 @@ func (s *Server) handlePlayerState(c *gin.Context) {
 		c.Status(http.StatusNotFound)
 		return
 	}
-	if _, err := s.authenticatePlayerRequest(c, game, player.ID, c.Query("auth_token")); err != nil {
+	if _, err := s.authenticatePlayerRequest(game, player.ID, c.Query("auth_token")); err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
@@ -302,23 +468,23 @@ func (s *Server) handleJoinGame(c *gin.Context) {
 	avatar := []byte(nil)
 	if strings.TrimSpace(req.AvatarData) != "" {
 		decoded, err := decodeImageData(req.AvatarData)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "avatar image is required"})
-			return
-		}
-		if len(decoded) > maxDrawingBytes {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "avatar exceeds size limit"})
+		if respondGameMutationError(c, imageError("avatar", err)) {
 			return
 		}
 		avatar = decoded
 	}
+	avatar, avatarHash, err := s.storeImage(c.Request.Context(), avatar)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store avatar"})
+		return
+	}
 
 	recoveryCode, recoveryHash, err := newRecoveryCredential()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create recovery credential"})
 		return
 	}
-	game, player, err := s.store.AddPlayerDurably(gameID, name, avatar, recoveryHash, func(game *Game, player *Player) error {
+	game, player, err := s.store.AddPlayerDurably(gameID, name, avatar, avatarHash, recoveryHash, func(game *Game, player *Player) error {
 		_, err := s.persistPlayer(game, player)
 		return err
 	})
@@ -559,12 +725,12 @@ func (s *Server) handleAvatar(c *gin.Context) {
 		return
 	}
 	avatar, err := decodeImageData(req.AvatarData)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "avatar image is required"})
+	if respondGameMutationError(c, imageError("avatar", err)) {
 		return
 	}
-	if len(avatar) > maxDrawingBytes {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "avatar exceeds size limit"})
+	avatar, avatarHash, err := s.storeImage(c.Request.Context(), avatar)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store avatar"})
 		return
 	}
 	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
@@ -574,7 +740,7 @@ func (s *Server) handleAvatar(c *gin.Context) {
 		if game.Phase != phaseLobby {
 			return errors.New("avatars only available in lobby")
 		}
-		player, err := s.authenticatePlayerRequest(c, game, req.PlayerID, req.AuthToken)
+		player, err := s.authenticatePlayerRequest(game, req.PlayerID, req.AuthToken)
 		if err != nil {
 			return err
 		}
@@ -582,6 +748,7 @@ func (s *Server) handleAvatar(c *gin.Context) {
 			return errors.New("avatar is already locked for this game")
 		}
 		player.Avatar = avatar
+		player.AvatarHash = avatarHash
 		player.AvatarLocked = true
 		return nil
 	}, func(game *Game) error {
@@ -608,7 +775,7 @@ func (s *Server) handleEvents(c *gin.Context) {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "events not available"})
 		return
 	}
-	game, ok := s.store.GetGame(gameID)
+	game, ok := s.loadGame(gameID)
 	if !ok {
 		c.Status(http.StatusNotFound)
 		return
@@ -620,7 +787,7 @@ func (s *Server) handleEvents(c *gin.Context) {
 		}
 	}
 	var records []db.Event
-	if err := s.db.Where("game_id = ?", game.DBID).Order("created_at asc").Find(&records).Error; err != nil {
+	if err := s.db.Where("game_id = ? AND type NOT IN ?", game.DBID, gameEventTypes).Order("created_at asc").Find(&records).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load events"})
 		return
 	}
@@ -636,37 +803,54 @@ func (s *Server) handleEvents(c *gin.Context) {
 		})
 	}
 	c.JSON(http.StatusOK, map[string]any{
-		"game_id": game.ID,
-		"events":  events,
+		"game_id":  game.ID,
+		"events":   events,
+		"drawings": buildReplayDrawings(game),
 	})
 }
 
 func (s *Server) handleSettings(c *gin.Context) {
-	gameID := c.Param("gameID")
 	if !s.enforceRateLimit(c, "settings") {
 		return
 	}
 	var req settingsRequest
-	if !bindJSON(c, &req, bindMessages{
-		"PlayerID": {
-			"required": "player_id is required",
-			"gt":       "player_id is required",
-		},
-		"Rounds": {
-			"max": "rounds exceeds maximum",
-		},
-	}, "invalid settings") {
+	if !settingsBinding.bind(c, &req) {
 		return
 	}
-	if req.Rounds < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid settings"})
+	game, err := s.updateSettings(c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
 		return
 	}
+	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+}
+
+func (s *Server) updateSettings(gameID string, req settingsRequest) (*Game, error) {
+	switch req.Ruleset {
+	case "", rulesetLegacy, rulesetDrawful, rulesetTeams, rulesetTelephone:
+	default:
+		return nil, badCommand("invalid ruleset")
+	}
+	if req.Rounds < 0 {
+		return nil, badCommand("invalid settings")
+	}
+	if req.ScoringRules != nil {
+		if err := req.ScoringRules.Validate(); err != nil {
+			return nil, badCommand(err.Error())
+		}
+	}
+	var timers domain.TimerSettings
+	if req.Timers != nil {
+		resolved, err := req.Timers.Resolve()
+		if err != nil {
+			return nil, badCommand(err.Error())
+		}
+		timers = resolved
+	}
 	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
 		if game.Phase != phaseLobby {
 			return errors.New("settings only available in lobby")
 		}
-		if _, err := s.authenticateHostRequest(c, game, req.PlayerID, req.AuthToken); err != nil {
+		if _, err := s.authenticateHostRequest(game, req.PlayerID, req.AuthToken); err != nil {
 			return err
 		}
 		if req.Rounds > 0 {
@@ -677,40 +861,51 @@ func (s *Server) handleSettings(c *gin.Context) {
 		game.AudienceEnabled = req.AudienceEnabled
 		game.JokesEnabled = req.JokesEnabled
 		game.PublicReplay = req.PublicReplay
+		if req.Ruleset != "" {
+			game.Ruleset = req.Ruleset
+		}
+		if req.TeamCount > 0 {
+			game.TeamCount = req.TeamCount
+		}
+		if req.ScoringRules != nil {
+			game.ScoringRules = *req.ScoringRules
+		}
+		if req.Timers != nil {
+			game.Timers = timers
+		}
+		applyTeamBalance(game)
 		return nil
 	}, func(game *Game) error { return s.persistSettings(game) })
-	if respondGameMutationError(c, err) {
-		return
+	if err != nil {
+		return nil, err
 	}
-	log.Printf("settings updated game_id=%s rounds=%d locked=%t", game.ID, game.PromptsPerPlayer, game.LobbyLocked)
-	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+	log.Printf("settings updated game_id=%s rounds=%d locked=%t timers=%s", game.ID, game.PromptsPerPlayer, game.LobbyLocked, game.Timers.Preset)
 	s.broadcastGameUpdate(game)
+	return game, nil
 }
 
 func (s *Server) handleKick(c *gin.Context) {
-	gameID := c.Param("gameID")
 	if !s.enforceRateLimit(c, "kick") {
 		return
 	}
 	var req kickRequest
-	if !bindJSON(c, &req, bindMessages{
-		"PlayerID": {
-			"required": "player_id and target_id are required",
-			"gt":       "player_id and target_id are required",
-		},
-		"TargetID": {
-			"required": "player_id and target_id are required",
-			"gt":       "player_id and target_id are required",
-		},
-	}, "player_id and target_id are required") {
+	if !kickBinding.bind(c, &req) {
+		return
+	}
+	game, err := s.kickPlayer(c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
 		return
 	}
+	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+}
+
+func (s *Server) kickPlayer(gameID string, req kickRequest) (*Game, error) {
 	var removedDBID uint
 	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
 		if game.Phase != phaseLobby {
 			return errors.New("kick only available in lobby")
 		}
-		if _, err := s.authenticateHostRequest(c, game, req.PlayerID, req.AuthToken); err != nil {
+		if _, err := s.authenticateHostRequest(game, req.PlayerID, req.AuthToken); err != nil {
 			return err
 		}
 		if req.TargetID == game.HostID {
@@ -732,35 +927,56 @@ func (s *Server) handleKick(c *gin.Context) {
 		}
 		game.KickedPlayers[strings.ToLower(game.Players[index].Name)] = struct{}{}
 		game.Players = append(game.Players[:index], game.Players[index+1:]...)
+		applyTeamBalance(game)
 		return nil
 	}, func(game *Game) error {
 		if s.db == nil || removedDBID == 0 {
 			return nil
 		}
-		return s.db.Delete(&db.Player{}, removedDBID).Error
+		if err := s.dbFor(game).Delete(&db.Player{}, removedDBID).Error; err != nil {
+			return err
+		}
+		return s.persistPlayerTeams(game)
 	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("player removed game_id=%s target_id=%d", game.ID, req.TargetID)
+	s.broadcastGameUpdate(game)
+	return game, nil
+}
+
+func (s *Server) handleTransferHost(c *gin.Context) {
+	if !s.enforceRateLimit(c, "transfer_host") {
+		return
+	}
+	var req transferHostRequest
+	if !transferHostBinding.bind(c, &req) {
+		return
+	}
+	game, err := s.transferHost(c.Param("gameID"), req)
 	if respondGameMutationError(c, err) {
 		return
 	}
-	log.Printf("player removed game_id=%s target_id=%d", game.ID, req.TargetID)
 	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
-	s.broadcastGameUpdate(game)
 }
 
 func (s *Server) handleStartGame(c *gin.Context) {
-	gameID := c.Param("gameID")
 	if !s.enforceRateLimit(c, "start") {
 		return
 	}
 	var req startRequest
-	if !bindJSON(c, &req, bindMessages{
-		"PlayerID": {
-			"required": "player_id is required",
-			"gt":       "player_id is required",
-		},
-	}, "player_id is required") {
+	if !startBinding.bind(c, &req) {
 		return
 	}
+	game, err := s.startGame(c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+}
+
+func (s *Server) startGame(gameID string, req startRequest) (*Game, error) {
 	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
 		requiredPlayers := game.MinPlayers
 		if requiredPlayers < 2 {
@@ -769,16 +985,22 @@ func (s *Server) handleStartGame(c *gin.Context) {
 		if len(game.Players) < requiredPlayers {
 			return errors.New("not enough players")
 		}
-		if _, err := s.authenticateHostRequest(c, game, req.PlayerID, req.AuthToken); err != nil {
+		if _, err := s.authenticateHostRequest(game, req.PlayerID, req.AuthToken); err != nil {
 			return err
 		}
 		if game.Phase != phaseLobby {
 			return errors.New("game already started")
 		}
-		if game.Ruleset == rulesetDrawful && game.PromptsPerPlayer <= 0 {
+		if (game.Ruleset == rulesetDrawful || teamsEnabled(game)) && game.PromptsPerPlayer <= 0 {
 			game.PromptsPerPlayer = drawfulRoundsForPlayers(len(game.Players))
 		}
-		setPhase(game, phaseDrawings)
+		if telephoneEnabled(game) {
+			// A telephone game is a single round of chains.
+			game.PromptsPerPlayer = 1
+			setPhase(game, phaseChainDraw)
+		} else {
+			setPhase(game, phaseDrawings)
+		}
 		game.Rounds = append(game.Rounds, RoundState{
 			Number: len(game.Rounds) + 1,
 		})
@@ -792,51 +1014,46 @@ func (s *Server) handleStartGame(c *gin.Context) {
 		}
 		return s.assignPrompts(game)
 	})
-	if respondGameMutationError(c, err) {
-		return
+	if err != nil {
+		return nil, err
 	}
 	log.Printf("game started game_id=%s phase=%s", game.ID, game.Phase)
-	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
 	s.broadcastGameUpdate(game)
 	s.schedulePhaseTimer(game)
+	return game, nil
 }
 
 func (s *Server) handleDrawings(c *gin.Context) {
-	gameID := c.Param("gameID")
 	if !s.enforceRateLimit(c, "drawings") {
 		return
 	}
 	var req drawingsRequest
-	if !bindJSON(c, &req, bindMessages{
-		"PlayerID": {
-			"required": "drawings are required",
-			"gt":       "drawings are required",
-		},
-		"ImageData": {
-			"required": "drawings are required",
-		},
-		"Prompt": {
-			"required": "drawings are required",
-			"prompt":   "prompt is invalid",
-		},
-	}, "drawings are required") {
+	if !drawingsBinding.bind(c, &req) {
 		return
 	}
+	game, err := s.submitDrawing(c.Request.Context(), c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+}
+
+func (s *Server) submitDrawing(ctx context.Context, gameID string, req drawingsRequest) (*Game, error) {
 	promptText := strings.TrimSpace(req.Prompt)
-	image, err := decodeImageData(req.ImageData)
+	image, strokeData, err := decodeDrawing(req.ImageData, req.Strokes)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid image data"})
-		return
+		return nil, imageError("drawing", err)
 	}
-	if len(image) > maxDrawingBytes {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "drawing exceeds size limit"})
-		return
+	image, imageHash, err := s.storeImage(ctx, image)
+	if err != nil {
+		return nil, internalCommandError("failed to store drawing")
 	}
+	settled := false
 	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
 		if game.Phase != phaseDrawings {
 			return errors.New("drawings not accepted in this phase")
 		}
-		player, err := s.authenticatePlayerRequest(c, game, req.PlayerID, req.AuthToken)
+		player, err := s.authenticatePlayerRequest(game, req.PlayerID, req.AuthToken)
 		if err != nil {
 			return err
 		}
@@ -857,56 +1074,57 @@ func (s *Server) handleDrawings(c *gin.Context) {
 			PlayerID:  player.ID,
 			ImageData: image,
 			Prompt:    promptEntry.Text,
+			Strokes:   strokeData,
+			ImageHash: imageHash,
 		})
-		return nil
-	}, func(game *Game) error { return s.persistDrawing(game, req.PlayerID, image, promptText) })
-	if respondGameMutationError(c, err) {
-		return
-	}
-	advanced, updated, err := s.tryAdvanceToGuesses(gameID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to advance game"})
-		return
-	}
-	if advanced {
-		game = updated
-		if err := s.persistPhase(game, "game_advanced", EventPayload{Phase: game.Phase}); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to advance game"})
-			return
+		settled, err = s.settleCompletePhase(game, time.Now().UTC())
+		return err
+	}, func(game *Game) error {
+		if err := s.persistDrawing(game, req.PlayerID, image, imageHash, strokeData, promptText); err != nil {
+			return err
 		}
-		log.Printf("game advanced game_id=%s phase=%s", game.ID, game.Phase)
+		return s.persistSettledPhase(game, phaseDrawings, settled)
+	})
+	if err != nil {
+		return nil, err
 	}
 	log.Printf("drawing submitted game_id=%s player_id=%d", game.ID, req.PlayerID)
-	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
-	s.broadcastGameUpdate(game)
+	s.afterSettledPhase(game, phaseDrawings, settled)
+	return game, nil
 }
 
+const titleGuessNotice = "You guessed it! Now write a lie to fool everyone else."
+
 func (s *Server) handleGuesses(c *gin.Context) {
-	gameID := c.Param("gameID")
 	if !s.enforceRateLimit(c, "guesses") {
 		return
 	}
 	var req guessesRequest
-	if !bindJSON(c, &req, bindMessages{
-		"PlayerID": {
-			"required": "guesses are required",
-			"gt":       "guesses are required",
-		},
-		"Guess": {
-			"required": "guesses are required",
-			"guess":    "guess is invalid",
-		},
-	}, "guesses are required") {
+	if !guessesBinding.bind(c, &req) {
 		return
 	}
+	game, guessedTitle, err := s.submitGuess(c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
+		return
+	}
+	snapshot := s.snapshotForPlayer(game, req.PlayerID)
+	if guessedTitle {
+		snapshot["guess_notice"] = titleGuessNotice
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// submitGuess records a lie for the player's assigned drawing. guessedTitle
+// reports that the guess was the real title, which is not recorded as a lie.
+func (s *Server) submitGuess(gameID string, req guessesRequest) (game *Game, guessedTitle bool, err error) {
 	guessText := normalizeText(req.Guess)
 	drawingIndex := -1
-	phaseAdvanced := false
-	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
+	settled := false
+	game, err = s.store.UpdateGameDurably(gameID, func(game *Game) error {
 		if game.Phase != phaseGuesses {
 			return errors.New("guesses not accepted in this phase")
 		}
-		player, err := s.authenticatePlayerRequest(c, game, req.PlayerID, req.AuthToken)
+		player, err := s.authenticatePlayerRequest(game, req.PlayerID, req.AuthToken)
 		if err != nil {
 			return err
 		}
@@ -918,79 +1136,164 @@ func (s *Server) handleGuesses(c *gin.Context) {
 		if !ok {
 			return errors.New("no guess assignment")
 		}
-		if hasGuessForPlayer(round, assignedDrawing, player.ID) {
+		if guessSatisfiedForPlayer(game, round, assignedDrawing, player.ID) {
 			return errors.New("guess already submitted")
 		}
-		if hasGuessText(round, assignedDrawing, guessText) {
-			return errors.New("guess already used for this drawing")
-		}
 		drawingIndex = assignedDrawing
+		isTitle, err := checkGuessText(game, round, assignedDrawing, player.ID, guessText)
+		if err != nil {
+			return err
+		}
+		if isTitle {
+			round.TitleGuesses = append(round.TitleGuesses, TitleGuessEntry{PlayerID: player.ID, DrawingIndex: assignedDrawing})
+			guessedTitle = true
+			return nil
+		}
 		round.Guesses = append(round.Guesses, GuessEntry{
 			PlayerID:     player.ID,
 			DrawingIndex: assignedDrawing,
 			Text:         guessText,
 		})
-		if activeGuessDrawingIndex(game, round) < 0 {
-			setPhase(game, phaseGuessVotes)
-			phaseAdvanced = true
-		}
-		return nil
+		settled, err = s.settleCompletePhase(game, time.Now().UTC())
+		return err
 	}, func(game *Game) error {
+		if guessedTitle {
+			return s.persistTitleGuess(game, req.PlayerID, drawingIndex, guessText)
+		}
 		if err := s.persistGuess(game, req.PlayerID, drawingIndex, guessText); err != nil {
 			return err
 		}
-		if phaseAdvanced {
-			return s.persistPhase(game, "game_advanced", EventPayload{Phase: game.Phase})
-		}
-		return nil
+		return s.persistSettledPhase(game, phaseGuesses, settled)
 	})
-	if respondGameMutationError(c, err) {
-		return
+	if err != nil {
+		return nil, false, err
 	}
-	if phaseAdvanced && game.Phase == phaseGuessVotes {
-		log.Printf("game advanced game_id=%s phase=%s", game.ID, game.Phase)
+	if guessedTitle {
+		log.Printf("title guessed game_id=%s player_id=%d", game.ID, req.PlayerID)
+		s.broadcastGameUpdate(game)
+		return game, true, nil
 	}
 	log.Printf("guess submitted game_id=%s player_id=%d", game.ID, req.PlayerID)
+	s.afterSettledPhase(game, phaseGuesses, settled)
+	return game, false, nil
+}
+
+func (s *Server) handleChainLink(c *gin.Context) {
+	if !s.enforceRateLimit(c, "chain") {
+		return
+	}
+	var req chainLinkRequest
+	if !chainLinkBinding.bind(c, &req) {
+		return
+	}
+	game, err := s.submitChainLink(c.Request.Context(), c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
+		return
+	}
 	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
-	s.broadcastGameUpdate(game)
-	if phaseAdvanced {
-		s.schedulePhaseTimer(game)
+}
+
+func (s *Server) submitChainLink(ctx context.Context, gameID string, req chainLinkRequest) (*Game, error) {
+	var image []byte
+	if req.ImageData != "" {
+		decoded, err := decodeImageData(req.ImageData)
+		if err != nil {
+			return nil, imageError("drawing", err)
+		}
+		image = decoded
+	}
+	image, imageHash, err := s.storeImage(ctx, image)
+	if err != nil {
+		return nil, internalCommandError("failed to store drawing")
+	}
+	description := normalizeText(req.Description)
+	var link ChainLinkEntry
+	prevPhase := ""
+	settled := false
+	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
+		if !telephoneEnabled(game) || !isChainStepPhase(game.Phase) {
+			return errors.New("chain links not accepted in this phase")
+		}
+		player, err := s.authenticatePlayerRequest(game, req.PlayerID, req.AuthToken)
+		if err != nil {
+			return err
+		}
+		round := currentRound(game)
+		if round == nil {
+			return errors.New("round not started")
+		}
+		task, ok := chainTaskForPlayer(game, round, player.ID)
+		if !ok {
+			return errors.New("no chain assignment")
+		}
+		if _, exists := findChainLink(round, task.ChainIndex, task.Step); exists {
+			return errors.New("chain link already submitted")
+		}
+		link = ChainLinkEntry{ChainIndex: task.ChainIndex, Step: task.Step, PlayerID: player.ID, Kind: string(task.Kind)}
+		if task.Kind == domain.LinkDrawing {
+			if len(image) == 0 && imageHash == "" {
+				return errors.New("drawing is required")
+			}
+			link.ImageData = image
+			link.ImageHash = imageHash
+		} else {
+			if description == "" {
+				return errors.New("description is required")
+			}
+			link.Text = description
+		}
+		round.ChainLinks = append(round.ChainLinks, link)
+		prevPhase = game.Phase
+		settled, err = s.settleCompletePhase(game, time.Now().UTC())
+		return err
+	}, func(game *Game) error {
+		if err := s.persistChainLink(game, link); err != nil {
+			return err
+		}
+		return s.persistSettledPhase(game, prevPhase, settled)
+	})
+	if err != nil {
+		return nil, err
 	}
+	log.Printf("chain link submitted game_id=%s player_id=%d chain=%d step=%d", game.ID, req.PlayerID, link.ChainIndex, link.Step)
+	s.afterSettledPhase(game, prevPhase, settled)
+	return game, nil
 }
 
 func (s *Server) handleVotes(c *gin.Context) {
-	gameID := c.Param("gameID")
 	if !s.enforceRateLimit(c, "votes") {
 		return
 	}
 	var req votesRequest
-	if !bindJSON(c, &req, bindMessages{
-		"PlayerID": {
-			"required": "votes are required",
-			"gt":       "votes are required",
-		},
-	}, "votes are required") {
+	if !votesBinding.bind(c, &req) {
 		return
 	}
+	game, err := s.submitVote(c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+}
+
+func (s *Server) submitVote(gameID string, req votesRequest) (*Game, error) {
 	choiceText := normalizeText(req.Choice)
 	if choiceText == "" {
 		choiceText = normalizeText(req.Guess)
 	}
 	choiceID := strings.TrimSpace(req.ChoiceID)
 	if choiceID == "" && choiceText == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "votes are required"})
-		return
+		return nil, badCommand("votes are required")
 	}
 	voteRoundNumber := 0
 	voteDrawingIndex := -1
 	voteChoiceText := ""
 	voteChoiceType := voteChoiceGuess
-	phaseAdvanced := false
+	settled := false
 	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
 		if game.Phase != phaseGuessVotes {
 			return errors.New("votes not accepted in this phase")
 		}
-		player, err := s.authenticatePlayerRequest(c, game, req.PlayerID, req.AuthToken)
+		player, err := s.authenticatePlayerRequest(game, req.PlayerID, req.AuthToken)
 		if err != nil {
 			return err
 		}
@@ -1016,6 +1319,9 @@ func (s *Server) handleVotes(c *gin.Context) {
 		if selected.Type == voteChoiceGuess && selected.OwnerID == player.ID {
 			return errors.New("cannot vote for your own lie")
 		}
+		if selected.Type == voteChoiceGuess && sameTeam(game, selected.OwnerID, player.ID) {
+			return errors.New("cannot vote for your own team's lie")
+		}
 		voteRoundNumber = round.Number
 		voteDrawingIndex = activeDrawing
 		voteChoiceText = selected.Text
@@ -1026,44 +1332,38 @@ func (s *Server) handleVotes(c *gin.Context) {
 			ChoiceText:   selected.Text,
 			ChoiceType:   selected.Type,
 		})
-		if activeVoteDrawingIndex(game, round) < 0 {
-			setPhase(game, phaseResults)
-			initReveal(round, activeDrawing)
-			phaseAdvanced = true
-		}
-		return nil
+		settled, err = s.settleCompletePhase(game, time.Now().UTC())
+		return err
 	}, func(game *Game) error {
 		if err := s.persistVote(game, req.PlayerID, voteRoundNumber, voteDrawingIndex, voteChoiceText, voteChoiceType); err != nil {
 			return err
 		}
-		if phaseAdvanced {
-			return s.persistPhase(game, "game_advanced", EventPayload{Phase: game.Phase})
-		}
-		return nil
+		return s.persistSettledPhase(game, phaseGuessVotes, settled)
 	})
-	if respondGameMutationError(c, err) {
-		return
-	}
-	if phaseAdvanced && game.Phase == phaseResults {
-		log.Printf("game advanced game_id=%s phase=%s", game.ID, game.Phase)
+	if err != nil {
+		return nil, err
 	}
 	log.Printf("vote submitted game_id=%s player_id=%d", game.ID, req.PlayerID)
-	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
-	s.broadcastGameUpdate(game)
-	if phaseAdvanced {
-		s.schedulePhaseTimer(game)
-	}
+	s.afterSettledPhase(game, phaseGuessVotes, settled)
+	return game, nil
 }
 
 func (s *Server) handleLikes(c *gin.Context) {
-	gameID := c.Param("gameID")
 	var req likesRequest
-	if !bindJSON(c, &req, bindMessages{}, "invalid like") {
+	if !likesBinding.bind(c, &req) {
 		return
 	}
+	game, err := s.likeGuess(c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+}
+
+func (s *Server) likeGuess(gameID string, req likesRequest) (*Game, error) {
 	var entry LikeEntry
 	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
-		player, err := s.authenticatePlayerRequest(c, game, req.PlayerID, req.AuthToken)
+		player, err := s.authenticatePlayerRequest(game, req.PlayerID, req.AuthToken)
 		if err != nil {
 			return err
 		}
@@ -1096,32 +1396,35 @@ func (s *Server) handleLikes(c *gin.Context) {
 		}
 		return s.persistLike(game, &entry)
 	})
-	if respondGameMutationError(c, err) {
-		return
+	if err != nil {
+		return nil, err
 	}
-	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
 	s.broadcastGameUpdate(game)
+	return game, nil
 }
 
 func (s *Server) handleAdvance(c *gin.Context) {
-	gameID := c.Param("gameID")
 	if !s.enforceRateLimit(c, "advance") {
 		return
 	}
 	var req advanceRequest
-	if !bindJSON(c, &req, bindMessages{
-		"PlayerID": {
-			"required": "player_id is required",
-			"gt":       "player_id is required",
-		},
-	}, "player_id is required") {
+	if !advanceBinding.bind(c, &req) {
+		return
+	}
+	game, err := s.advanceGame(c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
 		return
 	}
+	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+}
+
+func (s *Server) advanceGame(gameID string, req advanceRequest) (*Game, error) {
 	prevPhase := ""
 	filledGuesses := make([]autoFilledGuess, 0)
 	filledVotes := make([]autoFilledVote, 0)
+	filledLinks := make([]ChainLinkEntry, 0)
 	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
-		if _, err := s.authenticateHostRequest(c, game, req.PlayerID, req.AuthToken); err != nil {
+		if _, err := s.authenticateHostRequest(game, req.PlayerID, req.AuthToken); err != nil {
 			return err
 		}
 		prevPhase = game.Phase
@@ -1131,6 +1434,9 @@ func (s *Server) handleAdvance(c *gin.Context) {
 		if game.Phase == phaseGuessVotes {
 			filledVotes = append(filledVotes, autoFillMissingVotes(game)...)
 		}
+		if isChainStepPhase(game.Phase) {
+			filledLinks = append(filledLinks, autoFillMissingChainLinks(game)...)
+		}
 		_, err := s.advancePhase(game, transitionManual, time.Time{})
 		return err
 	}, func(game *Game) error {
@@ -1144,7 +1450,12 @@ func (s *Server) handleAdvance(c *gin.Context) {
 				return err
 			}
 		}
-		if game.Phase == phaseDrawings && prevPhase != phaseDrawings {
+		for _, filled := range filledLinks {
+			if err := s.persistChainLink(game, filled); err != nil {
+				return err
+			}
+		}
+		if (game.Phase == phaseDrawings && prevPhase != phaseDrawings) || (game.Phase == phaseChainDraw && prevPhase == phaseLobby) {
 			if err := s.persistRound(game); err != nil {
 				return err
 			}
@@ -1156,61 +1467,163 @@ func (s *Server) handleAdvance(c *gin.Context) {
 		}
 		return s.persistPhase(game, "game_advanced", EventPayload{Phase: game.Phase})
 	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("game advanced game_id=%s phase=%s", game.ID, game.Phase)
+	s.broadcastGameUpdate(game)
+	s.schedulePhaseTimer(game)
+	return game, nil
+}
+
+func (s *Server) handlePauseGame(c *gin.Context) {
+	if !s.enforceRateLimit(c, "pause") {
+		return
+	}
+	var req advanceRequest
+	if !pauseBinding.bind(c, &req) {
+		return
+	}
+	game, err := s.pauseGame(c.Param("gameID"), req)
 	if respondGameMutationError(c, err) {
 		return
 	}
-	log.Printf("game advanced game_id=%s phase=%s", game.ID, game.Phase)
 	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+}
+
+// pauseGame freezes the current phase for the host. The time left on the
+// phase clock is kept so resuming picks the countdown up where it stopped.
+func (s *Server) pauseGame(gameID string, req advanceRequest) (*Game, error) {
+	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
+		if _, err := s.authenticateHostRequest(game, req.PlayerID, req.AuthToken); err != nil {
+			return err
+		}
+		switch game.Phase {
+		case phasePaused:
+			return errors.New("game already paused")
+		case phaseLobby, phaseComplete:
+			return errors.New("game is not in progress")
+		}
+		now := time.Now().UTC()
+		game.PausedRemaining = 0
+		if duration := s.phaseDuration(game); duration > 0 {
+			game.PausedRemaining = phaseRemaining(game, duration, now)
+		}
+		game.PausedPhase = game.Phase
+		setPhaseAt(game, phasePaused, now)
+		return nil
+	}, func(game *Game) error {
+		return s.persistPhase(game, "game_paused", EventPayload{Phase: game.PausedPhase, Reason: "host_pause"})
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("game paused game_id=%s phase=%s remaining=%s", game.ID, game.PausedPhase, game.PausedRemaining)
+	s.cancelPhaseTimer(game.ID)
+	s.broadcastGameUpdate(game)
+	return game, nil
+}
+
+func (s *Server) handleAddTime(c *gin.Context) {
+	if !s.enforceRateLimit(c, "add_time") {
+		return
+	}
+	var req advanceRequest
+	if !addTimeBinding.bind(c, &req) {
+		return
+	}
+	game, err := s.addPhaseTime(c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+}
+
+// addPhaseTime gives the current phase another addTimeStep seconds. The
+// extension lasts until the phase changes and moves the timer's deadline.
+func (s *Server) addPhaseTime(gameID string, req advanceRequest) (*Game, error) {
+	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
+		if _, err := s.authenticateHostRequest(game, req.PlayerID, req.AuthToken); err != nil {
+			return err
+		}
+		if game.Phase == phasePaused {
+			return errors.New("game is paused")
+		}
+		if s.phaseDuration(game) <= 0 || game.PhaseStartedAt.IsZero() {
+			return errors.New("phase has no timer")
+		}
+		if game.PhaseExtension+addTimeStep*time.Second > maxPhaseExtensionSeconds*time.Second {
+			return errors.New("cannot add more time to this phase")
+		}
+		game.PhaseExtension += addTimeStep * time.Second
+		if !game.PhaseDeadline.IsZero() {
+			game.PhaseDeadline = game.PhaseDeadline.Add(addTimeStep * time.Second)
+		}
+		return nil
+	}, func(game *Game) error {
+		return s.persistPhase(game, "phase_extended", EventPayload{Phase: game.Phase, Reason: "host_add_time"})
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("phase extended game_id=%s phase=%s added=%s", game.ID, game.Phase, game.PhaseExtension)
 	s.broadcastGameUpdate(game)
 	s.schedulePhaseTimer(game)
+	return game, nil
 }
 
 func (s *Server) handleResumeGame(c *gin.Context) {
 	var req advanceRequest
-	if !bindJSON(c, &req, bindMessages{}, "player_id is required") {
+	if !resumeBinding.bind(c, &req) {
 		return
 	}
-	game, err := s.store.UpdateGameDurably(c.Param("gameID"), func(game *Game) error {
+	game, err := s.resumeGame(c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+}
+
+func (s *Server) resumeGame(gameID string, req advanceRequest) (*Game, error) {
+	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
 		if game.Phase != phasePaused {
 			return errors.New("game is not paused")
 		}
-		if _, err := s.authenticateHostRequest(c, game, req.PlayerID, req.AuthToken); err != nil {
+		if _, err := s.authenticateHostRequest(game, req.PlayerID, req.AuthToken); err != nil {
 			return err
 		}
-		resume := game.PausedPhase
-		if resume == "" {
-			resume = phaseLobby
-		}
-		setPhase(game, resume)
-		game.PausedPhase = ""
+		s.resumePausedPhase(game, time.Now().UTC())
 		return nil
 	}, func(game *Game) error {
 		return s.persistPhase(game, "game_resumed", EventPayload{Phase: game.Phase, Reason: "host_resume"})
 	})
-	if respondGameMutationError(c, err) {
-		return
+	if err != nil {
+		return nil, err
 	}
-	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+	log.Printf("game resumed game_id=%s phase=%s", game.ID, game.Phase)
 	s.broadcastGameUpdate(game)
 	s.schedulePhaseTimer(game)
+	return game, nil
 }
 
 func (s *Server) handleEndGame(c *gin.Context) {
-	gameID := c.Param("gameID")
 	if !s.enforceRateLimit(c, "end") {
 		return
 	}
 	var req endRequest
-	if !bindJSON(c, &req, bindMessages{
-		"PlayerID": {
-			"required": "player_id is required",
-			"gt":       "player_id is required",
-		},
-	}, "player_id is required") {
+	if !endBinding.bind(c, &req) {
 		return
 	}
+	game, err := s.endGame(c.Param("gameID"), req)
+	if respondGameMutationError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
+}
+
+func (s *Server) endGame(gameID string, req endRequest) (*Game, error) {
 	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
-		if _, err := s.authenticateHostRequest(c, game, req.PlayerID, req.AuthToken); err != nil {
+		if _, err := s.authenticateHostRequest(game, req.PlayerID, req.AuthToken); err != nil {
 			return err
 		}
 		if game.Phase == phaseComplete {
@@ -1219,17 +1632,17 @@ func (s *Server) handleEndGame(c *gin.Context) {
 		setPhase(game, phaseComplete)
 		return nil
 	}, func(game *Game) error { return s.persistPhase(game, "game_ended", EventPayload{Phase: game.Phase}) })
-	if respondGameMutationError(c, err) {
-		return
+	if err != nil {
+		return nil, err
 	}
 	log.Printf("game ended game_id=%s", game.ID)
-	c.JSON(http.StatusOK, s.snapshotForPlayer(game, req.PlayerID))
 	s.broadcastGameUpdate(game)
+	return game, nil
 }
 
 func (s *Server) handleResults(c *gin.Context) {
 	gameID := c.Param("gameID")
-	game, ok := s.store.GetGame(gameID)
+	game, ok := s.loadGame(gameID)
 	if !ok {
 		c.Status(http.StatusNotFound)
 		return
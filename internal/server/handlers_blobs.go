@@ -0,0 +1,51 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"picture-this/internal/blobstore"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleBlob serves an image from the blob store. Blobs are addressed by the
+// hash of their contents, so they never change and can be cached forever.
+func (s *Server) handleBlob(c *gin.Context) {
+	hash := c.Param("hash")
+	if s.blobs == nil || !blobstore.ValidHash(hash) {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	etag := `"` + hash + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	data, err := s.blobs.Get(c.Request.Context(), hash)
+	if errors.Is(err, blobstore.ErrNotFound) {
+		c.Header("Cache-Control", "no-store")
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load image"})
+		return
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, http.DetectContentType(data), data)
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"picture-this/internal/blobstore"
+	"picture-this/internal/config"
+)
+
+func newBlobServer(t *testing.T) *Server {
+	t.Helper()
+	srv := New(nil, config.Default())
+	store, err := blobstore.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("blob store: %v", err)
+	}
+	srv.blobs = store
+	return srv
+}
+
+func TestBlobRouteServesWithETags(t *testing.T) {
+	srv := newBlobServer(t)
+	image, err := decodeImageData(testAvatarData)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	inline, hash, err := srv.storeImage(context.Background(), image)
+	if err != nil || inline != nil || hash != blobstore.Hash(image) {
+		t.Fatalf("expected the image to move to the blob store, got %v %q %v", inline, hash, err)
+	}
+	handler := srv.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, blobPath(hash), nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" || rec.Body.Len() != len(image) {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	etag := rec.Header().Get("ETag")
+	if etag != `"`+hash+`"` || !strings.Contains(rec.Header().Get("Cache-Control"), "immutable") {
+		t.Fatalf("expected cache headers, got %v", rec.Header())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, blobPath(hash), nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rec.Code)
+	}
+
+	for _, path := range []string{blobPath(blobstore.Hash([]byte("missing"))), "/blobs/not-a-hash"} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404, got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestSnapshotsReferenceBlobURLs(t *testing.T) {
+	srv := newBlobServer(t)
+	hash := blobstore.Hash([]byte("drawing"))
+	game := &Game{
+		ID: "game-1", Phase: phaseResults, Ruleset: rulesetDrawful,
+		Players: []Player{{ID: 1, Name: "Ada", AvatarHash: hash}, {ID: 2, Name: "Ben"}},
+		Rounds:  []RoundState{{Number: 1, Drawings: []DrawingEntry{{PlayerID: 1, Prompt: "cat", ImageHash: hash}}}},
+	}
+	snapshot := srv.snapshotForPlayer(game, 2)
+	if avatars, _ := snapshot["player_avatars"].(map[int]string); avatars[1] != blobPath(hash) {
+		t.Fatalf("expected the avatar blob URL, got %v", snapshot["player_avatars"])
+	}
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(encoded), `"drawing_image":"`+blobPath(hash)+`"`) || strings.Contains(string(encoded), "base64") {
+		t.Fatalf("expected drawings to reference blob URLs, got %s", encoded)
+	}
+}
+
+func TestBlobHashesReplayFromEvents(t *testing.T) {
+	store, journal := newJournaledStore()
+	game := store.CreateGame(1)
+	hash := blobstore.Hash([]byte("drawing"))
+	steps := []func(*Game) error{
+		func(g *Game) error {
+			g.Players = []Player{{ID: 1, Name: "Ada", AvatarHash: hash}, {ID: 2, Name: "Ben"}}
+			g.Rounds = []RoundState{{Number: 1}}
+			setPhase(g, phaseDrawings)
+			return nil
+		},
+		func(g *Game) error {
+			g.Rounds[0].Drawings = append(g.Rounds[0].Drawings, DrawingEntry{PlayerID: 1, Prompt: "cat", ImageHash: hash})
+			return nil
+		},
+	}
+	for i, step := range steps {
+		if _, err := store.UpdateGame(game.ID, step); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	for _, event := range journal.events {
+		if strings.Contains(string(event.Payload), "image_data") {
+			t.Fatalf("expected no inline image in the journal, got %s", event.Payload)
+		}
+	}
+	final, _ := store.GetGame(game.ID)
+	got, err := replayGameEvents(journal.events, 0)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayable(got) != replayable(final) {
+		t.Fatalf("replay mismatch:\n got %s\nwant %s", replayable(got), replayable(final))
+	}
+}
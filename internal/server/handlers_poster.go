@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	domain "picture-this/internal/game"
+	"picture-this/internal/poster"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handlePoster renders the end-of-game poster, once per game version. Adding
+// ?download=1 asks the browser to save it.
+func (s *Server) handlePoster(c *gin.Context) {
+	var uri gameURI
+	if !bindURI(c, &uri) {
+		return
+	}
+	game, ok := s.loadGame(uri.GameID)
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if game.Phase != phaseComplete {
+		c.JSON(http.StatusForbidden, gin.H{"error": "the poster is unavailable until the game is complete"})
+		return
+	}
+	key := "poster-" + game.ID + "-" + strconv.FormatInt(game.Version, 10)
+	etag := `"` + key + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	data, err := s.cachedRender(c.Request.Context(), key, func() ([]byte, error) {
+		return poster.Render(s.buildPoster(c.Request.Context(), game))
+	})
+	if err != nil {
+		log.Printf("poster failed game_id=%s error=%v", game.ID, err)
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render poster"})
+		return
+	}
+	if c.Query("download") != "" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-poster.png"`, game.ID))
+	}
+	c.Data(http.StatusOK, "image/png", data)
+}
+
+func (s *Server) buildPoster(ctx context.Context, game *Game) poster.Poster {
+	players := map[int]poster.Player{}
+	for _, player := range game.Players {
+		avatar, err := s.loadImage(ctx, player.Avatar, player.AvatarHash)
+		if err != nil {
+			log.Printf("poster avatar missing game_id=%s player_id=%d error=%v", game.ID, player.ID, err)
+		}
+		players[player.ID] = poster.Player{Name: player.Name, Color: player.Color, Avatar: avatar}
+	}
+	subtitle := []string{fmt.Sprintf("%d players", len(game.Players))}
+	if !game.PhaseStartedAt.IsZero() {
+		subtitle = append(subtitle, game.PhaseStartedAt.Format("January 2, 2006"))
+	}
+	result := poster.Poster{
+		Title:    "Picture This · " + game.JoinCode,
+		Subtitle: strings.Join(subtitle, " · "),
+	}
+	if telephoneEnabled(game) {
+		result.Sections = s.posterChains(ctx, game, players)
+		return result
+	}
+	for i := range game.Rounds {
+		round := &game.Rounds[i]
+		section := poster.Section{Title: fmt.Sprintf("Round %d", round.Number)}
+		for drawingIndex, drawing := range round.Drawings {
+			image, err := s.loadImage(ctx, drawing.ImageData, drawing.ImageHash)
+			if err != nil {
+				log.Printf("poster drawing missing game_id=%s round=%d drawing=%d error=%v", game.ID, round.Number, drawingIndex, err)
+			}
+			section.Drawings = append(section.Drawings, poster.Drawing{
+				Image:   image,
+				Prompt:  drawing.Prompt,
+				Caption: bestLieCaption(game, round, drawingIndex),
+				Artist:  players[drawing.PlayerID],
+			})
+		}
+		if len(section.Drawings) > 0 {
+			result.Sections = append(result.Sections, section)
+		}
+	}
+	for _, score := range buildScores(game) {
+		playerID, _ := score["player_id"].(int)
+		points, _ := score["score"].(int)
+		result.Scores = append(result.Scores, poster.Score{Player: players[playerID], Points: points})
+	}
+	return result
+}
+
+// posterChains gives each telephone chain its own section, captioning every
+// drawing with the description it was drawn from.
+func (s *Server) posterChains(ctx context.Context, game *Game, players map[int]poster.Player) []poster.Section {
+	round := currentRound(game)
+	if round == nil {
+		return nil
+	}
+	var sections []poster.Section
+	for chainIndex, owner := range game.Players {
+		section := poster.Section{Title: owner.Name + "'s chain"}
+		source := chainOrigin(game, round, chainIndex)
+		for step := 0; step < domain.ChainSteps(len(game.Players)); step++ {
+			link, ok := findChainLink(round, chainIndex, step)
+			if !ok {
+				continue
+			}
+			if link.Kind != string(domain.LinkDrawing) {
+				source = link.Text
+				continue
+			}
+			image, err := s.loadImage(ctx, link.ImageData, link.ImageHash)
+			if err != nil {
+				log.Printf("poster drawing missing game_id=%s chain=%d step=%d error=%v", game.ID, chainIndex, step, err)
+			}
+			section.Drawings = append(section.Drawings, poster.Drawing{Image: image, Prompt: source, Artist: players[link.PlayerID]})
+		}
+		if len(section.Drawings) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// bestLieCaption names the lie that fooled the most players on a drawing.
+// Ties go to the lie written first.
+func bestLieCaption(game *Game, round *RoundState, drawingIndex int) string {
+	fooled := map[string]int{}
+	for _, vote := range round.Votes {
+		if vote.DrawingIndex == drawingIndex && vote.ChoiceType == voteChoiceGuess {
+			fooled[strings.ToLower(strings.TrimSpace(vote.ChoiceText))]++
+		}
+	}
+	var best *GuessEntry
+	bestCount := 0
+	for i := range round.Guesses {
+		guess := &round.Guesses[i]
+		if guess.DrawingIndex != drawingIndex {
+			continue
+		}
+		if count := fooled[strings.ToLower(strings.TrimSpace(guess.Text))]; count > bestCount {
+			best, bestCount = guess, count
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	players := "players"
+	if bestCount == 1 {
+		players = "player"
+	}
+	return fmt.Sprintf("Best lie: “%s” by %s (fooled %d %s)", best.Text, buildNameMap(game.Players)[best.PlayerID], bestCount, players)
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"picture-this/internal/config"
+)
+
+func TestPosterRoute(t *testing.T) {
+	srv := New(nil, config.Default())
+	drawing, err := decodeImageData(testAvatarData)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	game := srv.store.CreateGame(1)
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Ada", Color: "#ff6b6b", Avatar: drawing}, {ID: 2, Name: "Ben", Color: "#4dabf7"}}
+		g.Rounds = []RoundState{{Number: 1, Drawings: []DrawingEntry{{PlayerID: 1, Prompt: "cat", ImageData: drawing}}}}
+		setPhase(g, phaseResults)
+		return nil
+	}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	handler := srv.Handler()
+	path := "/api/games/" + game.ID + "/poster.png"
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected the poster to wait for the end of the game, got %d", rec.Code)
+	}
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		setPhase(g, phaseComplete)
+		return nil
+	}); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"?download=1", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" || rec.Header().Get("Content-Disposition") == "" {
+		t.Fatalf("unexpected response %d %v", rec.Code, rec.Header())
+	}
+	if _, err := png.Decode(bytes.NewReader(rec.Body.Bytes())); err != nil {
+		t.Fatalf("expected a PNG: %v", err)
+	}
+	completed, _ := srv.store.GetGame(game.ID)
+	if entry, ok := srv.renders.get(fmt.Sprintf("poster-%s-%d", game.ID, completed.Version)); !ok || !bytes.Equal(entry.data, rec.Body.Bytes()) {
+		t.Fatal("expected the rendered poster to be cached for this game version")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rec.Code)
+	}
+}
+
+func TestBestLieCaption(t *testing.T) {
+	game := &Game{Players: []Player{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}, {ID: 3, Name: "Cy"}}}
+	round := &RoundState{
+		Guesses: []GuessEntry{
+			{PlayerID: 2, DrawingIndex: 0, Text: "Hot dog"},
+			{PlayerID: 3, DrawingIndex: 0, Text: "Sausage"},
+			{PlayerID: 3, DrawingIndex: 1, Text: "Moon"},
+		},
+		Votes: []VoteEntry{
+			{PlayerID: 1, DrawingIndex: 0, ChoiceText: "sausage", ChoiceType: voteChoiceGuess},
+			{PlayerID: 2, DrawingIndex: 0, ChoiceText: "Sausage", ChoiceType: voteChoiceGuess},
+			{PlayerID: 3, DrawingIndex: 0, ChoiceText: "hot dog", ChoiceType: voteChoiceGuess},
+			{PlayerID: 2, DrawingIndex: 1, ChoiceText: "cat", ChoiceType: voteChoicePrompt},
+		},
+	}
+	if got := bestLieCaption(game, round, 0); got != "Best lie: “Sausage” by Cy (fooled 2 players)" {
+		t.Fatalf("unexpected caption %q", got)
+	}
+	if got := bestLieCaption(game, round, 1); got != "" {
+		t.Fatalf("expected no caption when nobody was fooled, got %q", got)
+	}
+}
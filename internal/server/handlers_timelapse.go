@@ -0,0 +1,121 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"picture-this/internal/blobstore"
+	"picture-this/internal/strokes"
+
+	"github.com/gin-gonic/gin"
+)
+
+type timeLapseURI struct {
+	GameID string `uri:"gameID" binding:"required"`
+	Round  int    `uri:"round" binding:"required,gt=0"`
+	Index  int    `uri:"index" binding:"min=0"`
+}
+
+// handleTimeLapse renders an animated GIF of a drawing being made from its
+// recorded strokes. Renders are cached by the strokes' hash. Adding
+// ?download=1 asks the browser to save it.
+func (s *Server) handleTimeLapse(c *gin.Context) {
+	var uri timeLapseURI
+	if !bindURI(c, &uri) {
+		return
+	}
+	game, ok := s.loadGame(uri.GameID)
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	round := roundByNumber(game, uri.Round)
+	if round == nil || uri.Index >= len(round.Drawings) || len(round.Drawings[uri.Index].Strokes) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no time-lapse for this drawing"})
+		return
+	}
+	if !drawingRevealed(game, round) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "drawings are hidden until guessing starts"})
+		return
+	}
+	strokeData := round.Drawings[uri.Index].Strokes
+	hash := blobstore.Hash(strokeData)
+	etag := `"` + hash + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	data, err := s.cachedRender(c.Request.Context(), "timelapse-"+hash, func() ([]byte, error) {
+		doc, err := strokes.Parse(strokeData)
+		if err != nil {
+			return nil, err
+		}
+		return strokes.TimeLapse(doc)
+	})
+	if err != nil {
+		log.Printf("time-lapse failed game_id=%s round=%d drawing=%d error=%v", game.ID, uri.Round, uri.Index, err)
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render time-lapse"})
+		return
+	}
+	if c.Query("download") != "" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-round%d-drawing%d.gif"`, game.ID, uri.Round, uri.Index+1))
+	}
+	c.Data(http.StatusOK, "image/gif", data)
+}
+
+// drawingRevealed reports whether everyone has already seen the round's
+// drawings, which happens once the round leaves the drawing phase.
+func drawingRevealed(game *Game, round *RoundState) bool {
+	if round != currentRound(game) {
+		return true
+	}
+	phase := game.Phase
+	if phase == phasePaused {
+		phase = game.PausedPhase
+	}
+	return phase != phaseLobby && phase != phaseDrawings
+}
+
+func timeLapsePath(gameID string, roundNumber, drawingIndex int) string {
+	return fmt.Sprintf("/api/games/%s/rounds/%d/drawings/%d/timelapse.gif", gameID, roundNumber, drawingIndex)
+}
+
+// timeLapseSource is the time-lapse URL for drawings that were submitted as
+// strokes, or "" for those that only have a PNG.
+func timeLapseSource(game *Game, round *RoundState, drawingIndex int) string {
+	if len(round.Drawings[drawingIndex].Strokes) == 0 {
+		return ""
+	}
+	return timeLapsePath(game.ID, round.Number, drawingIndex)
+}
+
+// buildReplayDrawings lists the drawings the replay view can show. Prompts
+// stay hidden until the game is over.
+func buildReplayDrawings(game *Game) []map[string]any {
+	names := buildNameMap(game.Players)
+	drawings := make([]map[string]any, 0)
+	for i := range game.Rounds {
+		round := &game.Rounds[i]
+		if !drawingRevealed(game, round) {
+			continue
+		}
+		for drawingIndex, drawing := range round.Drawings {
+			entry := map[string]any{
+				"round_number":       round.Number,
+				"drawing_index":      drawingIndex,
+				"drawing_owner_name": names[drawing.PlayerID],
+				"drawing_image":      imageSource(drawing.ImageData, drawing.ImageHash),
+				"timelapse_url":      timeLapseSource(game, round, drawingIndex),
+			}
+			if game.Phase == phaseComplete {
+				entry["prompt"] = drawing.Prompt
+			}
+			drawings = append(drawings, entry)
+		}
+	}
+	return drawings
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"image/gif"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"picture-this/internal/blobstore"
+)
+
+func TestTimeLapseRoute(t *testing.T) {
+	srv := newBlobServer(t)
+	strokeData := []byte(`{"version":1,"width":40,"height":30,"strokes":[{"color":"#1a1a1a","width":4,"points":[[5,5,0],[35,25,400]]}]}`)
+	game := srv.store.CreateGame(1)
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}}
+		g.Rounds = []RoundState{{Number: 1, Drawings: []DrawingEntry{
+			{PlayerID: 1, Prompt: "cat", ImageData: []byte{1}, Strokes: strokeData},
+			{PlayerID: 2, Prompt: "dog", ImageData: []byte{2}},
+		}}}
+		setPhase(g, phaseDrawings)
+		return nil
+	}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	handler := srv.Handler()
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	path := timeLapsePath(game.ID, 1, 0)
+
+	if rec := get(path, nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected drawings to stay hidden while drawing, got %d", rec.Code)
+	}
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		setPhase(g, phaseGuesses)
+		return nil
+	}); err != nil {
+		t.Fatalf("advance: %v", err)
+	}
+
+	rec := get(path+"?download=1", nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/gif" {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Disposition"), "attachment;") {
+		t.Fatalf("expected a download, got %q", rec.Header().Get("Content-Disposition"))
+	}
+	anim, err := gif.DecodeAll(bytes.NewReader(rec.Body.Bytes()))
+	if err != nil || len(anim.Image) < 2 {
+		t.Fatalf("expected an animated GIF, got %v", err)
+	}
+	entry, ok := srv.renders.get("timelapse-" + blobstore.Hash(strokeData))
+	if !ok || entry.hash == "" {
+		t.Fatal("expected the GIF to be cached in the blob store")
+	}
+	if cached, err := srv.blobs.Get(context.Background(), entry.hash); err != nil || !bytes.Equal(cached, rec.Body.Bytes()) {
+		t.Fatalf("expected the blob store to hold the served GIF, got %v", err)
+	}
+	if again := get(path, nil); again.Code != http.StatusOK || !bytes.Equal(again.Body.Bytes(), rec.Body.Bytes()) {
+		t.Fatalf("expected the cached GIF to be served again, got %d", again.Code)
+	}
+	if rec := get(path, http.Header{"If-None-Match": {rec.Header().Get("ETag")}}); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rec.Code)
+	}
+
+	for _, missing := range []string{timeLapsePath(game.ID, 1, 1), timeLapsePath(game.ID, 1, 5), timeLapsePath(game.ID, 2, 0)} {
+		if rec := get(missing, nil); rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404, got %d", missing, rec.Code)
+		}
+	}
+
+	current, _ := srv.store.GetGame(game.ID)
+	drawings := buildReplayDrawings(current)
+	if len(drawings) != 2 || drawings[0]["timelapse_url"] != path || drawings[1]["timelapse_url"] != "" {
+		t.Fatalf("unexpected replay drawings %v", drawings)
+	}
+	if _, ok := drawings[0]["prompt"]; ok {
+		t.Fatal("expected prompts to stay hidden until the game is over")
+	}
+}
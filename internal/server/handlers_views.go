@@ -93,7 +93,7 @@ func (s *Server) handleReplayView(c *gin.Context) {
 	if !bindURI(c, &uri) {
 		return
 	}
-	game, exists := s.store.GetGame(uri.GameID)
+	game, exists := s.loadGame(uri.GameID)
 	if !exists || !game.PublicReplay {
 		c.Status(http.StatusNotFound)
 		return
@@ -128,5 +128,11 @@ func (s *Server) handleDisplayPartial(c *gin.Context) {
 		c.Status(http.StatusNotFound)
 		return
 	}
+	// The partial carries the same fragments as the display stream, so it
+	// takes the same token as the big screen link.
+	if err := authenticateDisplayRequest(game, c.Query("token")); err != nil {
+		c.Status(http.StatusForbidden)
+		return
+	}
 	templ.Handler(web.DisplayContent(s.buildDisplayState(game))).ServeHTTP(c.Writer, c.Request)
 }
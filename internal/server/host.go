@@ -0,0 +1,114 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	hostReasonTransfer = "host_transfer"
+	hostReasonAway     = "host_away"
+)
+
+// setHost makes playerID the game's host, keeping every player's IsHost flag
+// in step with HostID.
+func setHost(game *Game, playerID int) {
+	game.HostID = playerID
+	for i := range game.Players {
+		game.Players[i].IsHost = game.Players[i].ID == playerID
+	}
+}
+
+// hostReconnectMessage tells the new host's sockets to reconnect with
+// role=host so they get the host controls' fragments.
+type hostReconnectMessage struct {
+	Type   string `json:"type"`
+	HostID int    `json:"host_id"`
+}
+
+// syncHostSockets closes host sockets that no longer belong to the game's
+// host and asks the new host's sockets to reconnect as host after a change.
+func (s *Server) syncHostSockets(game *Game) {
+	for _, client := range s.ws.SyncHosts(game.ID, game.HostID) {
+		s.ws.Send(client, hostReconnectMessage{Type: "host_reconnect", HostID: game.HostID})
+	}
+}
+
+func (s *Server) transferHost(gameID string, req transferHostRequest) (*Game, error) {
+	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
+		if game.Phase == phaseComplete {
+			return errors.New("game already ended")
+		}
+		if _, err := s.authenticateHostRequest(game, req.PlayerID, req.AuthToken); err != nil {
+			return err
+		}
+		if req.TargetID == game.HostID {
+			return errors.New("player is already host")
+		}
+		if _, ok := s.store.FindPlayer(game, req.TargetID); !ok {
+			return errors.New("player not found")
+		}
+		setHost(game, req.TargetID)
+		return nil
+	}, func(game *Game) error { return s.persistHost(game, hostReasonTransfer) })
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("host transferred game_id=%s from=%d to=%d reason=%s", game.ID, req.PlayerID, game.HostID, hostReasonTransfer)
+	s.broadcastGameUpdate(game)
+	return game, nil
+}
+
+// migrateAwayHost hands the game to the next player once the host has been
+// gone past HostAwaySeconds, and reports whether it did.
+func (s *Server) migrateAwayHost(game *Game, gone map[int]time.Duration, now time.Time) bool {
+	after := time.Duration(s.cfg.HostAwaySeconds) * time.Second
+	if after <= 0 || game.Phase == phaseComplete {
+		return false
+	}
+	if away, ok := gone[game.HostID]; !ok || away < after {
+		return false
+	}
+	successor := nextHost(game, s.presence.States(game.ID, now))
+	if successor == 0 {
+		return false
+	}
+	previous := game.HostID
+	updated, err := s.store.UpdateGameDurably(game.ID, func(game *Game) error {
+		if game.HostID != previous {
+			return errors.New("host changed")
+		}
+		setHost(game, successor)
+		return nil
+	}, func(game *Game) error { return s.persistHost(game, hostReasonAway) })
+	if err != nil {
+		log.Printf("host migration failed game_id=%s host_id=%d error=%v", game.ID, previous, err)
+		return false
+	}
+	log.Printf("host transferred game_id=%s from=%d to=%d reason=%s", updated.ID, previous, updated.HostID, hostReasonAway)
+	s.broadcastGameUpdate(updated)
+	return true
+}
+
+// nextHost picks the earliest-joined player with a live socket, preferring
+// active players over idle ones. It returns 0 when nobody is connected.
+func nextHost(game *Game, presence map[int]string) int {
+	for _, state := range []string{presenceConnected, presenceIdle} {
+		for _, player := range game.Players {
+			if player.ID != game.HostID && presence[player.ID] == state {
+				return player.ID
+			}
+		}
+	}
+	return 0
+}
+
+func hostName(game *Game) string {
+	for _, player := range game.Players {
+		if player.ID == game.HostID {
+			return player.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"bytes"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+
+	"github.com/gorilla/websocket"
+)
+
+func hostedLobby(t *testing.T, srv *Server) *Game {
+	t.Helper()
+	game := srv.store.CreateGame(1)
+	updated, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Ada", IsHost: true}, {ID: 2, Name: "Ben"}, {ID: 3, Name: "Cam"}}
+		g.HostID = 1
+		g.PlayerAuthTokens = map[int]string{1: "ada-secret", 2: "ben-secret", 3: "cam-secret"}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	return updated
+}
+
+func TestTransferHost(t *testing.T) {
+	srv := New(nil, config.Default())
+	store, journal := newJournaledStore()
+	srv.store = store
+	game := hostedLobby(t, srv)
+
+	for _, tc := range []struct {
+		req  transferHostRequest
+		want string
+	}{
+		{transferHostRequest{PlayerID: 2, AuthToken: "ben-secret", TargetID: 3}, "only host can perform this action"},
+		{transferHostRequest{PlayerID: 1, AuthToken: "ada-secret", TargetID: 1}, "player is already host"},
+		{transferHostRequest{PlayerID: 1, AuthToken: "ada-secret", TargetID: 9}, "player not found"},
+	} {
+		if _, err := srv.transferHost(game.ID, tc.req); err == nil || err.Error() != tc.want {
+			t.Fatalf("expected %q, got %v", tc.want, err)
+		}
+	}
+
+	transferred, err := srv.transferHost(game.ID, transferHostRequest{PlayerID: 1, AuthToken: "ada-secret", TargetID: 2})
+	if err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if transferred.HostID != 2 || transferred.Players[0].IsHost || !transferred.Players[1].IsHost {
+		t.Fatalf("expected Ben to be the only host, got host %d and players %+v", transferred.HostID, transferred.Players)
+	}
+	if snapshot := srv.snapshotForPlayer(transferred, 3); snapshot["host_id"] != 2 || snapshot["host_name"] != "Ben" {
+		t.Fatalf("expected every view to show the new host, got %v (%v)", snapshot["host_id"], snapshot["host_name"])
+	}
+	if _, ok := srv.snapshotForPlayer(transferred, 2)["display_token"]; !ok {
+		t.Fatal("expected the new host to get the display token")
+	}
+	if _, err := srv.advanceGame(game.ID, advanceRequest{PlayerID: 1, AuthToken: "ada-secret"}); err == nil || err.Error() != "only host can perform this action" {
+		t.Fatalf("expected the old host to lose host controls, got %v", err)
+	}
+
+	replayed, err := replayGameEvents(journal.events, 0)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayable(replayed) != replayable(transferred) {
+		t.Fatalf("expected the event stream to carry the new host, got host %d", replayed.HostID)
+	}
+}
+
+func TestTransferHostMovesHostSockets(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := hostedLobby(t, srv)
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	base := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/games/" + game.ID
+	dial := func(query string) *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial(base+"?"+query, nil)
+		if err != nil {
+			t.Fatalf("dial %s: %v", query, err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	// readUntil reads until a message contains want, and reports whether the
+	// socket was closed first.
+	readUntil := func(conn *websocket.Conn, want string) bool {
+		t.Helper()
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		for {
+			_, payload, err := conn.ReadMessage()
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				t.Fatalf("timed out waiting for %q", want)
+			}
+			if err != nil {
+				return false
+			}
+			if want != "" && bytes.Contains(payload, []byte(want)) {
+				return true
+			}
+		}
+	}
+	waitForClients := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			srv.ws.mu.Lock()
+			got := len(srv.ws.groups[game.ID])
+			srv.ws.mu.Unlock()
+			if got == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d game sockets, got %d", want, got)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	ada := dial("role=host&player_id=1&auth_token=ada-secret")
+	ben := dial("player_id=2&auth_token=ben-secret")
+	waitForClients(2)
+
+	if _, err := srv.transferHost(game.ID, transferHostRequest{PlayerID: 1, AuthToken: "ada-secret", TargetID: 2}); err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if readUntil(ada, "") {
+		t.Fatal("expected the old host's host socket to be closed")
+	}
+	if !readUntil(ben, `"host_reconnect"`) {
+		t.Fatal("expected the new host to be told to reconnect as host")
+	}
+
+	benHost := dial("role=host&player_id=2&auth_token=ben-secret")
+	waitForClients(2)
+	srv.ws.BroadcastFragments(game.ID, wsRoleHost, []wsHTMLMessage{htmlMessage("#hostProbe", "inner", "next")})
+	if !readUntil(benHost, "#hostProbe") {
+		t.Fatal("expected the new host's host socket to get the next host broadcast")
+	}
+	srv.ws.mu.Lock()
+	defer srv.ws.mu.Unlock()
+	for client := range srv.ws.hosts[game.ID] {
+		if client.playerID != 2 {
+			t.Fatalf("expected only the new host in the host group, got player %d", client.playerID)
+		}
+	}
+}
+
+func TestAwayHostMigratesByJoinOrder(t *testing.T) {
+	cfg := config.Default()
+	cfg.HostAwaySeconds = 10
+	srv := New(nil, cfg)
+	game := hostedLobby(t, srv)
+
+	start := time.Now().UTC()
+	srv.presence.Connect(game.ID, 1, start)
+	srv.presence.Connect(game.ID, 3, start)
+	srv.presence.Disconnect(game.ID, 1, start)
+
+	srv.applyAwayPolicy(game, start.Add(5*time.Second))
+	if current, _ := srv.store.GetGame(game.ID); current.HostID != 1 {
+		t.Fatalf("expected the host to keep the game before the threshold, got %d", current.HostID)
+	}
+	srv.applyAwayPolicy(game, start.Add(11*time.Second))
+	current, _ := srv.store.GetGame(game.ID)
+	if current.HostID != 3 || !current.Players[2].IsHost || current.Players[0].IsHost {
+		t.Fatalf("expected Cam, the only connected player, to take over, got %d", current.HostID)
+	}
+
+	if got := nextHost(current, map[int]string{1: presenceIdle, 2: presenceConnected}); got != 2 {
+		t.Fatalf("expected a connected player to beat an idle one, got %d", got)
+	}
+	if got := nextHost(current, map[int]string{1: presenceIdle, 2: presenceIdle}); got != 1 {
+		t.Fatalf("expected join order to break ties, got %d", got)
+	}
+	if got := nextHost(current, map[int]string{1: presenceGone}); got != 0 {
+		t.Fatalf("expected no successor when nobody is connected, got %d", got)
+	}
+}
@@ -1,11 +1,35 @@
 package server
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"image/png"
+	"net/http"
 	"strings"
+
+	"picture-this/internal/blobstore"
+	"picture-this/internal/strokes"
+)
+
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+const maxImageDimension = strokes.MaxDimension
+
+var (
+	errInvalidImage    = errors.New("invalid image data")
+	errImageNotPNG     = errors.New("image must be a PNG")
+	errImageTooLarge   = errors.New("image exceeds size limit")
+	errImageDimensions = fmt.Errorf("image must be at most %dx%d pixels", maxImageDimension, maxImageDimension)
 )
 
+// decodeImageData decodes a base64 PNG (optionally a data URL) uploaded as a
+// drawing or avatar and re-encodes it, so only pixels we decoded ourselves
+// are stored and served back: ancillary chunks such as text and EXIF
+// metadata are dropped.
 func decodeImageData(data string) ([]byte, error) {
 	data = strings.TrimSpace(data)
 	if data == "" {
@@ -13,13 +37,134 @@ func decodeImageData(data string) ([]byte, error) {
 	}
 	parts := strings.SplitN(data, ",", 2)
 	if len(parts) == 2 {
+		if !strings.EqualFold(strings.TrimSpace(parts[0]), "data:image/png;base64") {
+			return nil, errImageNotPNG
+		}
 		data = parts[1]
 	}
+	if base64.StdEncoding.DecodedLen(len(data)) > maxDrawingBytes+2 {
+		return nil, errImageTooLarge
+	}
 	decoded, err := base64.StdEncoding.DecodeString(data)
 	if err != nil {
+		return nil, errInvalidImage
+	}
+	if len(decoded) > maxDrawingBytes {
+		return nil, errImageTooLarge
+	}
+	image, err := canonicalPNG(decoded)
+	if err != nil {
+		return nil, err
+	}
+	// Re-encoding can grow an image that was just under the limit.
+	if len(image) > maxDrawingBytes {
+		return nil, errImageTooLarge
+	}
+	return image, nil
+}
+
+// imageError reports an image that could not be accepted as a command error,
+// with 413 for one over the size limit. what names the image in the message.
+func imageError(what string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, errImageTooLarge) {
+		return &commandError{status: http.StatusRequestEntityTooLarge, message: what + " exceeds size limit"}
+	}
+	return badCommand(err.Error())
+}
+
+func canonicalPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(pngSignature)) {
+		return nil, errImageNotPNG
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errInvalidImage
+	}
+	if config.Width < 1 || config.Height < 1 || config.Width > maxImageDimension || config.Height > maxImageDimension {
+		return nil, errImageDimensions
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errInvalidImage
+	}
+	var out bytes.Buffer
+	if err := png.Encode(&out, img); err != nil {
 		return nil, err
 	}
-	return decoded, nil
+	return out.Bytes(), nil
+}
+
+// decodeDrawing accepts a stroke document, rasterizing it for the views that
+// show PNGs, or a base64 PNG from clients that predate stroke data. It
+// returns the PNG and the stroke document re-encoded in canonical form.
+func decodeDrawing(imageData string, strokeData json.RawMessage) ([]byte, []byte, error) {
+	if len(bytes.TrimSpace(strokeData)) == 0 || bytes.Equal(bytes.TrimSpace(strokeData), []byte("null")) {
+		if strings.TrimSpace(imageData) == "" {
+			return nil, nil, errors.New("drawings are required")
+		}
+		image, err := decodeImageData(imageData)
+		if err != nil {
+			return nil, nil, err
+		}
+		return image, nil, nil
+	}
+	doc, err := strokes.Parse(strokeData)
+	if err != nil {
+		return nil, nil, err
+	}
+	canonical, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	image, err := strokes.Rasterize(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(image) > maxDrawingBytes {
+		return nil, nil, errImageTooLarge
+	}
+	return image, canonical, nil
+}
+
+// storeImage moves an image into the blob store when one is configured,
+// returning the bytes to keep inline and the hash to reference instead.
+func (s *Server) storeImage(ctx context.Context, image []byte) ([]byte, string, error) {
+	if s.blobs == nil || len(image) == 0 {
+		return image, "", nil
+	}
+	hash, err := s.blobs.Put(ctx, image)
+	if err != nil {
+		return nil, "", err
+	}
+	return nil, hash, nil
+}
+
+// loadImage returns an image's bytes whether it is inline or in the blob
+// store.
+func (s *Server) loadImage(ctx context.Context, image []byte, hash string) ([]byte, error) {
+	if hash == "" || len(image) > 0 {
+		return image, nil
+	}
+	if s.blobs == nil {
+		return nil, blobstore.ErrNotFound
+	}
+	return s.blobs.Get(ctx, hash)
+}
+
+// imageSource is what snapshots put in an <img src>: the blob URL when the
+// image is in the blob store, otherwise an inline data URL.
+func imageSource(image []byte, hash string) string {
+	if hash != "" {
+		return blobPath(hash)
+	}
+	return encodeImageData(image)
+}
+
+func blobPath(hash string) string {
+	return "/blobs/" + hash
 }
 
 func encodeImageData(image []byte) string {
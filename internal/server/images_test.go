@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"net/http"
+	"testing"
+)
+
+func TestDecodeDrawingAcceptsStrokesAndLegacyPNG(t *testing.T) {
+	strokeData := json.RawMessage(`{"version":1,"width":80,"height":60,"strokes":[{"color":"#1a1a1a","width":4,"points":[[10,10,0],[70.333,50,250]]}]}`)
+	image, canonical, err := decodeDrawing("", strokeData)
+	if err != nil {
+		t.Fatalf("decode strokes: %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(image)); err != nil {
+		t.Fatalf("expected a rasterized PNG: %v", err)
+	}
+	if !bytes.Contains(canonical, []byte("[70.33,50,250]")) {
+		t.Fatalf("expected canonical stroke data, got %s", canonical)
+	}
+
+	legacy, legacyStrokes, err := decodeDrawing(encodeImageData(image), nil)
+	if err != nil || !bytes.Equal(legacy, image) || legacyStrokes != nil {
+		t.Fatalf("expected the PNG to pass through, got %v", err)
+	}
+
+	if _, _, err := decodeDrawing("", json.RawMessage(`{"version":1,"width":80,"height":60,"strokes":[{"color":"blue","width":4,"points":[[1,1,0]]}]}`)); err == nil {
+		t.Fatal("expected invalid strokes to be rejected")
+	}
+	if _, _, err := decodeDrawing("", json.RawMessage("null")); err == nil || err.Error() != "drawings are required" {
+		t.Fatalf("expected a missing drawing error, got %v", err)
+	}
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	if err := png.Encode(&out, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return out.Bytes()
+}
+
+// withTextChunk inserts a tEXt chunk after the IHDR chunk.
+func withTextChunk(data []byte, text string) []byte {
+	const ihdrEnd = 8 + 8 + 13 + 4
+	chunk := make([]byte, 4, 12+len(text))
+	binary.BigEndian.PutUint32(chunk, uint32(len(text)))
+	chunk = append(chunk, "tEXt"+text...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+	out := append([]byte(nil), data[:ihdrEnd]...)
+	out = append(out, chunk...)
+	return append(out, data[ihdrEnd:]...)
+}
+
+func TestDecodeImageDataReencodesPNG(t *testing.T) {
+	tagged := withTextChunk(testPNG(t, 4, 3), "Comment\x00secret")
+	decoded, err := decodeImageData(encodeImageData(tagged))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if bytes.Contains(decoded, []byte("secret")) {
+		t.Fatal("expected metadata to be stripped")
+	}
+	if img, err := png.Decode(bytes.NewReader(decoded)); err != nil || img.Bounds().Dx() != 4 || img.Bounds().Dy() != 3 {
+		t.Fatalf("expected a 4x3 PNG, got %v", err)
+	}
+
+	cases := map[string]struct {
+		data string
+		want error
+	}{
+		"gif":        {"data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("GIF89a\x01\x00\x01\x00")), errImageNotPNG},
+		"jpeg url":   {"data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(tagged), errImageNotPNG},
+		"truncated":  {base64.StdEncoding.EncodeToString(tagged[:40]), errInvalidImage},
+		"not base64": {"data:image/png;base64,@@@", errInvalidImage},
+		"dimensions": {base64.StdEncoding.EncodeToString(testPNG(t, maxImageDimension+1, 1)), errImageDimensions},
+		"too large":  {base64.StdEncoding.EncodeToString(make([]byte, maxDrawingBytes+1)), errImageTooLarge},
+	}
+	for name, tc := range cases {
+		if _, err := decodeImageData(tc.data); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", name, err, tc.want)
+		}
+	}
+
+	if status := mutationErrorStatus(imageError("avatar", errImageTooLarge)); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected an oversized image to map to 413, got %d", status)
+	}
+	if status := mutationErrorStatus(imageError("avatar", errImageNotPNG)); status != http.StatusBadRequest {
+		t.Errorf("expected a malformed image to map to 400, got %d", status)
+	}
+}
+
+func FuzzDecodeImageData(f *testing.F) {
+	f.Add(testAvatarData)
+	f.Add("data:image/png;base64,")
+	f.Add(base64.StdEncoding.EncodeToString([]byte(pngSignature + "\x00\x00\x00\x0dIHDR")))
+	f.Fuzz(func(t *testing.T, data string) {
+		decoded, err := decodeImageData(data)
+		if err != nil {
+			return
+		}
+		again, err := canonicalPNG(decoded)
+		if err != nil {
+			t.Fatalf("accepted image does not decode again: %v", err)
+		}
+		if !bytes.Equal(again, decoded) {
+			t.Fatal("re-encoding is not stable")
+		}
+	})
+}
+
+func FuzzCanonicalPNG(f *testing.F) {
+	seed, _ := decodeImageData(testAvatarData)
+	f.Add(seed)
+	f.Add([]byte(pngSignature))
+	f.Add(withTextChunk(seed, "Comment\x00hello"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		out, err := canonicalPNG(data)
+		if err != nil {
+			return
+		}
+		config, err := png.DecodeConfig(bytes.NewReader(out))
+		if err != nil {
+			t.Fatalf("output is not a PNG: %v", err)
+		}
+		if config.Width > maxImageDimension || config.Height > maxImageDimension {
+			t.Fatalf("output exceeds the dimension limit: %dx%d", config.Width, config.Height)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"picture-this/internal/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StartJanitor periodically unloads finished and abandoned games so their
+// drawings and actor goroutines do not stay in memory forever.
+func (s *Server) StartJanitor(ctx context.Context) {
+	interval := time.Duration(s.cfg.JanitorIntervalSeconds) * time.Second
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.sweepGames(now.UTC())
+			}
+		}
+	}()
+}
+
+// sweepGames unloads complete games past the grace period and lobbies (or
+// paused games) with no commands for longer than the idle TTL. A zero setting
+// disables that rule.
+func (s *Server) sweepGames(now time.Time) int {
+	grace := time.Duration(s.cfg.CompleteGameGraceSeconds) * time.Second
+	idleTTL := time.Duration(s.cfg.IdleLobbyTTLSeconds) * time.Second
+	unloaded := 0
+	for _, game := range s.store.ResidentGames() {
+		reason := ""
+		switch game.Phase {
+		case phaseComplete:
+			if grace > 0 && now.Sub(game.PhaseStartedAt) >= grace {
+				reason = "complete"
+			}
+		case phaseLobby, phasePaused:
+			if idleTTL > 0 && now.Sub(game.IdleSince) >= idleTTL {
+				reason = "idle"
+			}
+		}
+		if reason == "" {
+			continue
+		}
+		if s.unloadGame(game, reason) {
+			unloaded++
+		}
+	}
+	if unloaded > 0 {
+		s.broadcastHomeUpdate()
+	}
+	return unloaded
+}
+
+func (s *Server) unloadGame(game ResidentGame, reason string) bool {
+	s.cancelPhaseTimer(game.ID)
+	if !s.store.UnloadGame(game.ID) {
+		return false
+	}
+	s.releaseGameLease(game.DBID)
+	s.presence.Forget(game.ID)
+	s.streams.Forget(game.ID)
+	log.Printf("game unloaded game_id=%s phase=%s reason=%s", game.ID, game.Phase, reason)
+	return true
+}
+
+// loadGame returns a game from memory, reloading it from the database when the
+// janitor has unloaded it.
+func (s *Server) loadGame(gameID string) (*Game, bool) {
+	if game, ok := s.findGameInStore(gameID); ok {
+		return game, true
+	}
+	if s.db == nil {
+		return nil, false
+	}
+	game, _, err := s.loadGameFromDB(strings.TrimPrefix(gameID, "game-"), false)
+	if err != nil {
+		// A concurrent request may have reloaded the game first.
+		return s.findGameInStore(gameID)
+	}
+	log.Printf("game reloaded game_id=%s phase=%s", game.ID, game.Phase)
+	s.resumeRestoredGame(game)
+	return game, true
+}
+
+func (s *Server) releaseGameLease(dbID uint) {
+	if s.cluster == nil || dbID == 0 {
+		return
+	}
+	s.cluster.forget(dbID)
+	if err := s.db.Where("owner = ? AND game_id = ?", s.cluster.nodeID, dbID).Delete(&db.GameLease{}).Error; err != nil {
+		log.Printf("lease release failed node=%s game_db_id=%d error=%v", s.cluster.nodeID, dbID, err)
+	}
+}
+
+// handleMetrics reports resident game counts in the Prometheus text format.
+func (s *Server) handleMetrics(c *gin.Context) {
+	byPhase := map[string]int{}
+	for _, game := range s.store.ResidentGames() {
+		byPhase[game.Phase]++
+	}
+	phases := make([]string, 0, len(byPhase))
+	for phase := range byPhase {
+		phases = append(phases, phase)
+	}
+	sort.Strings(phases)
+
+	var b strings.Builder
+	b.WriteString("# HELP picture_this_resident_games Games held in memory by phase.\n")
+	b.WriteString("# TYPE picture_this_resident_games gauge\n")
+	for _, phase := range phases {
+		fmt.Fprintf(&b, "picture_this_resident_games{phase=%q} %d\n", phase, byPhase[phase])
+	}
+	b.WriteString("# HELP picture_this_game_actors Running game actor goroutines.\n")
+	b.WriteString("# TYPE picture_this_game_actors gauge\n")
+	fmt.Fprintf(&b, "picture_this_game_actors %d\n", runningGameActors.Load())
+	b.WriteString("# HELP picture_this_games_unloaded_total Games unloaded from memory.\n")
+	b.WriteString("# TYPE picture_this_games_unloaded_total counter\n")
+	fmt.Fprintf(&b, "picture_this_games_unloaded_total %d\n", s.store.UnloadedCount())
+	b.WriteString("# HELP picture_this_ws_clients Connected websocket clients.\n")
+	b.WriteString("# TYPE picture_this_ws_clients gauge\n")
+	fmt.Fprintf(&b, "picture_this_ws_clients %d\n", wsOpenClients.Load())
+	b.WriteString("# HELP picture_this_ws_dropped_total Websocket clients dropped for falling behind.\n")
+	b.WriteString("# TYPE picture_this_ws_dropped_total counter\n")
+	fmt.Fprintf(&b, "picture_this_ws_dropped_total %d\n", wsDroppedClients.Load())
+	b.WriteString("# HELP picture_this_sse_clients Connected server-sent event streams.\n")
+	b.WriteString("# TYPE picture_this_sse_clients gauge\n")
+	fmt.Fprintf(&b, "picture_this_sse_clients %d\n", sseOpenClients.Load())
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+)
+
+func TestSweepGamesUnloadsCompleteAndIdleGames(t *testing.T) {
+	cfg := config.Default()
+	cfg.CompleteGameGraceSeconds = 60
+	cfg.IdleLobbyTTLSeconds = 600
+	srv := New(nil, cfg)
+	now := time.Now().UTC()
+
+	finished := srv.store.CreateGame(1)
+	recent := srv.store.CreateGame(1)
+	idle := srv.store.CreateGame(1)
+	active := srv.store.CreateGame(1)
+	_, _ = srv.store.UpdateGame(finished.ID, func(game *Game) error {
+		game.Phase = phaseComplete
+		game.PhaseStartedAt = now.Add(-2 * time.Minute)
+		return nil
+	})
+	_, _ = srv.store.UpdateGame(recent.ID, func(game *Game) error {
+		game.Phase = phaseComplete
+		game.PhaseStartedAt = now.Add(-10 * time.Second)
+		return nil
+	})
+	_, _ = srv.store.UpdateGame(active.ID, func(game *Game) error {
+		game.Phase = phaseDrawings
+		return nil
+	})
+
+	if got := srv.sweepGames(now.Add(30 * time.Second)); got != 1 {
+		t.Fatalf("expected only the finished game to unload, got %d", got)
+	}
+	if _, ok := srv.store.GetGame(finished.ID); ok {
+		t.Fatal("finished game is still resident")
+	}
+	if got := srv.sweepGames(now.Add(11 * time.Minute)); got != 2 {
+		t.Fatalf("expected the recent and idle games to unload, got %d", got)
+	}
+	if _, ok := srv.store.GetGame(idle.ID); ok {
+		t.Fatal("idle lobby is still resident")
+	}
+	if _, ok := srv.store.GetGame(active.ID); !ok {
+		t.Fatal("in-progress game should never be unloaded")
+	}
+
+	recorder := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+	if recorder.Code != http.StatusOK ||
+		!strings.Contains(body, `picture_this_resident_games{phase="drawings"} 1`) ||
+		!strings.Contains(body, "picture_this_games_unloaded_total 3") {
+		t.Fatalf("unexpected metrics: %d %s", recorder.Code, body)
+	}
+}
+
+func TestUnloadedGameReloadsForCommandsAndKeepsTokens(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := hostedLobby(t, srv)
+
+	// A reload only has the token hashes stored on the players rows.
+	reloaded := cloneGame(game)
+	reloaded.PlayerAuthHashes = make(map[int]string)
+	for id, token := range reloaded.PlayerAuthTokens {
+		reloaded.PlayerAuthHashes[id] = hashAuthToken(token)
+	}
+	reloaded.PlayerAuthTokens = make(map[int]string)
+	loads := 0
+	srv.store.SetLoader(func(id string) (*Game, bool) {
+		loads++
+		if err := srv.store.RestoreGame(cloneGame(reloaded)); err != nil {
+			t.Fatalf("reload: %v", err)
+		}
+		return srv.store.GetGame(id)
+	})
+	if !srv.unloadGame(srv.store.ResidentGames()[0], "idle") {
+		t.Fatal("expected the lobby to unload")
+	}
+
+	if _, err := srv.transferHost(game.ID, transferHostRequest{PlayerID: 2, AuthToken: "not-ben", TargetID: 3}); err == nil || err.Error() != "invalid player authentication" {
+		t.Fatalf("expected a wrong token to be refused, got %v", err)
+	}
+	transferred, err := srv.transferHost(game.ID, transferHostRequest{PlayerID: 1, AuthToken: "ada-secret", TargetID: 2})
+	if err != nil {
+		t.Fatalf("expected the host's token to survive the reload: %v", err)
+	}
+	if transferred.HostID != 2 || loads != 1 {
+		t.Fatalf("expected one reload and Ben as host, got %d reloads and host %d", loads, transferred.HostID)
+	}
+	if resident := srv.store.ResidentGames(); len(resident) != 1 || resident[0].ID != game.ID || resident[0].Phase != phaseLobby {
+		t.Fatalf("expected the reloaded lobby to be resident, got %+v", resident)
+	}
+}
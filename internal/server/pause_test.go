@@ -0,0 +1,71 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+)
+
+func TestHostPauseFreezesPhaseClock(t *testing.T) {
+	cfg := config.Default()
+	cfg.GuessDurationSeconds = 60
+	srv := New(nil, cfg)
+	game := restoredGuessGame(time.Now().UTC().Add(-45 * time.Second))
+	game.PlayerAuthTokens = map[int]string{1: "host-secret", 2: "ben-secret"}
+	for i := range game.Players {
+		game.Players[i].Claimed = true
+	}
+	if err := srv.store.RestoreGame(game); err != nil {
+		t.Fatalf("restore game: %v", err)
+	}
+	srv.schedulePhaseTimer(game)
+	defer srv.cancelPhaseTimer(game.ID)
+	scheduled := func() bool {
+		srv.timersMu.Lock()
+		defer srv.timersMu.Unlock()
+		_, ok := srv.timers[game.ID]
+		return ok
+	}
+	near := func(got, want time.Duration) bool {
+		return got > want-time.Second && got <= want
+	}
+
+	if _, err := srv.pauseGame(game.ID, advanceRequest{PlayerID: 2, AuthToken: "ben-secret"}); err == nil || err.Error() != "only host can perform this action" {
+		t.Fatalf("expected only the host to pause, got %v", err)
+	}
+	paused, err := srv.pauseGame(game.ID, advanceRequest{PlayerID: 1, AuthToken: "host-secret"})
+	if err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if paused.Phase != phasePaused || paused.PausedPhase != phaseGuesses || !near(paused.PausedRemaining, 15*time.Second) {
+		t.Fatalf("expected guesses paused with 15s left, got %s/%s with %s", paused.Phase, paused.PausedPhase, paused.PausedRemaining)
+	}
+	if scheduled() {
+		t.Fatal("expected pausing to cancel the phase timer")
+	}
+	snapshot := srv.snapshotForPlayer(paused, 1)
+	if snapshot["paused_remaining"] != 15 || snapshot["phase_ends_at"] != "" {
+		t.Fatalf("expected a frozen 15s countdown, got %v and ends at %q", snapshot["paused_remaining"], snapshot["phase_ends_at"])
+	}
+	if display := srv.buildDisplayState(paused); display.PausedSeconds != 15 || display.StageStatus != "The host paused the game. Hang tight." {
+		t.Fatalf("expected the display to show the frozen countdown, got %d %q", display.PausedSeconds, display.StageStatus)
+	}
+	if _, err := srv.pauseGame(game.ID, advanceRequest{PlayerID: 1, AuthToken: "host-secret"}); err == nil || err.Error() != "game already paused" {
+		t.Fatalf("expected a second pause to conflict, got %v", err)
+	}
+
+	// Time spent paused does not count against the phase.
+	time.Sleep(20 * time.Millisecond)
+	resumed, err := srv.resumeGame(game.ID, advanceRequest{PlayerID: 1, AuthToken: "host-secret"})
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	remaining := phaseRemaining(resumed, srv.phaseDuration(resumed), time.Now().UTC())
+	if resumed.Phase != phaseGuesses || resumed.PausedRemaining != 0 || !near(remaining, paused.PausedRemaining) {
+		t.Fatalf("expected guesses to resume with %s left, got %s with %s", paused.PausedRemaining, resumed.Phase, remaining)
+	}
+	if !scheduled() {
+		t.Fatal("expected resuming to reschedule the phase timer")
+	}
+}
@@ -15,6 +15,32 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// persistCommand runs one game command's row writes and journal insert in a
+// single transaction. On a cluster node it first confirms this node still
+// holds the game's lease, so a node that lost it cannot keep writing.
+func (s *Server) persistCommand(game *Game, write func() error) error {
+	if s.db == nil {
+		return write()
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.checkGameLease(tx, game); err != nil {
+			return err
+		}
+		s.commandTxs.Store(game, tx)
+		defer s.commandTxs.Delete(game)
+		return write()
+	})
+}
+
+// dbFor returns the transaction a command on game is persisting in, or the
+// shared connection outside of one.
+func (s *Server) dbFor(game *Game) *gorm.DB {
+	if tx, ok := s.commandTxs.Load(game); ok {
+		return tx.(*gorm.DB)
+	}
+	return s.db
+}
+
 func (s *Server) persistGame(game *Game) error {
 	if s.db == nil {
 		return nil
@@ -22,6 +48,7 @@ func (s *Server) persistGame(game *Game) error {
 	record := db.Game{
 		JoinCode:         game.JoinCode,
 		Phase:            game.Phase,
+		PhaseStartedAt:   game.PhaseStartedAt,
 		PromptsPerPlayer: game.PromptsPerPlayer,
 		MinPlayers:       game.MinPlayers,
 		MaxPlayers:       game.MaxPlayers,
@@ -31,9 +58,13 @@ func (s *Server) persistGame(game *Game) error {
 		AudienceEnabled:  game.AudienceEnabled,
 		JokesEnabled:     game.JokesEnabled,
 		PublicReplay:     game.PublicReplay,
+		TeamCount:        game.TeamCount,
+		ScoringRules:     dbScoringRules(game.ScoringRules),
+		Timers:           dbTimerSettings(game.Timers),
 		Version:          game.Version,
+		DisplayTokenHash: game.DisplayTokenHash,
 	}
-	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record).Error; err != nil {
+	if err := s.dbFor(game).Clauses(clause.OnConflict{DoNothing: true}).Create(&record).Error; err != nil {
 		return err
 	}
 	game.DBID = record.ID
@@ -66,12 +97,15 @@ func (s *Server) persistPlayer(game *Game, player *Player) (int, error) {
 		GameID:           game.DBID,
 		Name:             player.Name,
 		AvatarImage:      player.Avatar,
+		AvatarHash:       player.AvatarHash,
 		Color:            player.Color,
 		IsHost:           player.IsHost,
 		JoinedAt:         time.Now().UTC(),
 		RecoveryCodeHash: player.RecoveryHash,
+		Team:             player.Team,
+		AuthTokenHash:    hashAuthToken(game.PlayerAuthTokens[player.ID]),
 	}
-	err := s.db.Transaction(func(tx *gorm.DB) error {
+	err := s.dbFor(game).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(&record).Error; err != nil {
 			return err
 		}
@@ -100,7 +134,8 @@ func (s *Server) persistPlayerRecovery(game *Game, playerID int, hash string) er
 	if !ok || player.DBID == 0 {
 		return errors.New("player not found")
 	}
-	return s.db.Model(&db.Player{}).Where("id = ?", player.DBID).Update("recovery_code_hash", hash).Error
+	return s.dbFor(game).Model(&db.Player{}).Where("id = ?", player.DBID).
+		Updates(map[string]any{"recovery_code_hash": hash, "auth_token_hash": hashAuthToken(game.PlayerAuthTokens[playerID])}).Error
 }
 
 func (s *Server) persistPlayerAvatar(game *Game, player *Player) error {
@@ -123,9 +158,39 @@ func (s *Server) persistPlayerAvatar(game *Game, player *Player) error {
 	if player.DBID == 0 {
 		return errors.New("player not found")
 	}
-	return s.db.Model(&db.Player{}).
+	return s.dbFor(game).Model(&db.Player{}).
 		Where("id = ?", player.DBID).
-		Update("avatar_image", player.Avatar).Error
+		Updates(map[string]any{"avatar_image": player.Avatar, "avatar_hash": player.AvatarHash}).Error
+}
+
+// persistPlayerTeams stores the current team of every player; lobby changes
+// rebalance teams, so any join, kick or settings update can move players.
+func (s *Server) persistPlayerTeams(game *Game) error {
+	for _, player := range game.Players {
+		if player.DBID == 0 {
+			continue
+		}
+		if err := s.dbFor(game).Model(&db.Player{}).Where("id = ?", player.DBID).Update("team", player.Team).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistHost moves the is_host flag to the new host in one statement and
+// records who took over and why, inside the command's transaction.
+func (s *Server) persistHost(game *Game, reason string) error {
+	if s.db == nil {
+		return nil
+	}
+	host, ok := s.store.FindPlayer(game, game.HostID)
+	if !ok {
+		return errors.New("player not found")
+	}
+	if err := s.dbFor(game).Exec("UPDATE players SET is_host = (id = ?) WHERE game_id = ?", host.DBID, game.DBID).Error; err != nil {
+		return err
+	}
+	return s.persistEvent(game, "host_changed", EventPayload{PlayerName: host.Name, PlayerID: host.ID, Reason: reason})
 }
 
 func (s *Server) persistPhase(game *Game, eventType string, payload EventPayload) error {
@@ -140,15 +205,21 @@ func (s *Server) persistPhase(game *Game, eventType string, payload EventPayload
 	if game.DBID == 0 {
 		return errors.New("game not found")
 	}
-	if err := s.db.Model(&db.Game{}).Where("id = ?", game.DBID).Update("phase", game.Phase).Error; err != nil {
-		return err
+	var deadline *time.Time
+	if !game.PhaseDeadline.IsZero() {
+		deadline = &game.PhaseDeadline
 	}
-	if err := s.db.Model(&db.Game{}).Where("id = ?", game.DBID).Update("version", game.Version).Error; err != nil {
+	if err := s.dbFor(game).Model(&db.Game{}).Where("id = ?", game.DBID).Updates(map[string]any{
+		"phase": game.Phase, "phase_started_at": game.PhaseStartedAt, "version": game.Version,
+		"paused_phase": game.PausedPhase, "paused_remaining_ms": game.PausedRemaining.Milliseconds(),
+		"phase_extension_ms": game.PhaseExtension.Milliseconds(), "phase_deadline": deadline,
+	}).Error; err != nil {
 		return err
 	}
 	if round := currentRound(game); round != nil && round.DBID != 0 {
-		if err := s.db.Model(&db.Round{}).Where("id = ?", round.DBID).Updates(map[string]any{
+		if err := s.dbFor(game).Model(&db.Round{}).Where("id = ?", round.DBID).Updates(map[string]any{
 			"status": game.Phase, "active_drawing_index": round.RevealIndex, "reveal_stage": round.RevealStage,
+			"chain_step": round.ChainStep,
 		}).Error; err != nil {
 			return err
 		}
@@ -177,8 +248,19 @@ func (s *Server) persistSettings(game *Game) error {
 		"audience_enabled":   game.AudienceEnabled,
 		"jokes_enabled":      game.JokesEnabled,
 		"public_replay":      game.PublicReplay,
+		"ruleset":            game.Ruleset,
+		"team_count":         game.TeamCount,
+	}
+	for column, value := range scoringRuleColumns(game.ScoringRules) {
+		updates[column] = value
+	}
+	for column, value := range timerSettingColumns(game.Timers) {
+		updates[column] = value
+	}
+	if err := s.dbFor(game).Model(&db.Game{}).Where("id = ?", game.DBID).Updates(updates).Error; err != nil {
+		return err
 	}
-	if err := s.db.Model(&db.Game{}).Where("id = ?", game.DBID).Updates(updates).Error; err != nil {
+	if err := s.persistPlayerTeams(game); err != nil {
 		return err
 	}
 	return s.persistEvent(game, "settings_updated", EventPayload{
@@ -201,7 +283,7 @@ func (s *Server) persistEvent(game *Game, eventType string, payload EventPayload
 	if game.DBID == 0 {
 		return errors.New("game not found")
 	}
-	return s.persistEventWithDB(s.db, game, eventType, payload)
+	return s.persistEventWithDB(s.dbFor(game), game, eventType, payload)
 }
 
 func (s *Server) persistEventWithDB(conn *gorm.DB, game *Game, eventType string, payload EventPayload) error {
@@ -219,6 +301,63 @@ func (s *Server) persistEventWithDB(conn *gorm.DB, game *Game, eventType string,
 	return conn.Create(&event).Error
 }
 
+func (s *Server) journalGameEvents(game *Game, events []GameEvent) error {
+	if s.db == nil || len(events) == 0 {
+		return nil
+	}
+	if game.DBID == 0 {
+		if err := s.ensureGameDBID(game); err != nil {
+			return err
+		}
+	}
+	if game.DBID == 0 {
+		return errors.New("game not found")
+	}
+	records := make([]db.Event, 0, len(events))
+	for _, event := range events {
+		record := db.Event{
+			GameID:  game.DBID,
+			Type:    event.Type,
+			Version: event.Version,
+			Payload: datatypes.JSON(event.Payload),
+		}
+		if round := roundByNumber(game, event.Round); round != nil && round.DBID != 0 {
+			id := round.DBID
+			record.RoundID = &id
+		}
+		records = append(records, record)
+	}
+	return s.dbFor(game).Create(&records).Error
+}
+
+func (s *Server) loadGameEvents(gameDBID uint) ([]GameEvent, error) {
+	return s.loadGameEventsAfter(gameDBID, 0)
+}
+
+// loadGameEventsAfter loads the typed events recorded after version.
+func (s *Server) loadGameEventsAfter(gameDBID uint, version int64) ([]GameEvent, error) {
+	var records []db.Event
+	if err := s.db.Where("game_id = ? AND type IN ? AND version > ?", gameDBID, gameEventTypes, version).Order("version asc, id asc").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	events := make([]GameEvent, 0, len(records))
+	for _, record := range records {
+		var scope struct {
+			Round  int `json:"round"`
+			Number int `json:"number"`
+		}
+		if err := json.Unmarshal(record.Payload, &scope); err != nil {
+			return nil, err
+		}
+		round := scope.Round
+		if round == 0 {
+			round = scope.Number
+		}
+		events = append(events, GameEvent{Version: record.Version, Type: record.Type, Round: round, Payload: json.RawMessage(record.Payload)})
+	}
+	return events, nil
+}
+
 func (s *Server) resolveEventRoundID(game *Game) *uint {
 	round := currentRound(game)
 	if round == nil {
@@ -253,7 +392,7 @@ func (s *Server) ensureGameDBID(game *Game) error {
 		return nil
 	}
 	var record db.Game
-	if err := s.db.Where("join_code = ?", game.JoinCode).First(&record).Error; err != nil {
+	if err := s.dbFor(game).Where("join_code = ?", game.JoinCode).First(&record).Error; err != nil {
 		return nil
 	}
 	game.DBID = record.ID
@@ -280,7 +419,7 @@ func (s *Server) persistRound(game *Game) error {
 		GameID: game.DBID, Number: round.Number, Status: game.Phase,
 		ActiveDrawingIndex: round.RevealIndex, RevealStage: round.RevealStage,
 	}
-	if err := s.db.Create(&record).Error; err != nil {
+	if err := s.dbFor(game).Create(&record).Error; err != nil {
 		return err
 	}
 	round.DBID = record.ID
@@ -312,7 +451,7 @@ func (s *Server) persistAssignedPrompts(game *Game, round *RoundState) error {
 			Joke:          entry.Joke,
 			JokeAudioPath: entry.JokeAudioPath,
 		}
-		if err := s.db.Create(&record).Error; err != nil {
+		if err := s.dbFor(game).Create(&record).Error; err != nil {
 			return err
 		}
 		entry.DBID = record.ID
@@ -320,7 +459,7 @@ func (s *Server) persistAssignedPrompts(game *Game, round *RoundState) error {
 	return nil
 }
 
-func (s *Server) persistDrawing(game *Game, playerID int, image []byte, promptText string) error {
+func (s *Server) persistDrawing(game *Game, playerID int, image []byte, imageHash string, strokeData []byte, promptText string) error {
 	if s.db == nil {
 		return s.persistEvent(game, "drawings_submitted", EventPayload{
 			PlayerID: playerID,
@@ -345,12 +484,14 @@ func (s *Server) persistDrawing(game *Game, playerID int, image []byte, promptTe
 		return errors.New("prompt not assigned")
 	}
 	record := db.Drawing{
-		RoundID:   round.DBID,
-		PlayerID:  player.DBID,
-		PromptID:  promptEntry.DBID,
-		ImageData: image,
+		RoundID:    round.DBID,
+		PlayerID:   player.DBID,
+		PromptID:   promptEntry.DBID,
+		ImageData:  image,
+		StrokeData: strokeData,
+		ImageHash:  imageHash,
 	}
-	if err := s.db.Create(&record).Error; err != nil {
+	if err := s.dbFor(game).Create(&record).Error; err != nil {
 		return err
 	}
 	for i := range round.Drawings {
@@ -394,7 +535,7 @@ func (s *Server) persistGuess(game *Game, playerID int, drawingIndex int, guess
 		DrawingID: drawingID,
 		Text:      guess,
 	}
-	if err := s.db.Create(&record).Error; err != nil {
+	if err := s.dbFor(game).Create(&record).Error; err != nil {
 		return err
 	}
 	return s.persistEvent(game, "guesses_submitted", EventPayload{
@@ -403,6 +544,73 @@ func (s *Server) persistGuess(game *Game, playerID int, drawingIndex int, guess
 	})
 }
 
+// persistTitleGuess stores a guess that matched the real title. It is not a
+// lie, so it goes to title_guesses rather than guesses.
+func (s *Server) persistTitleGuess(game *Game, playerID int, drawingIndex int, guess string) error {
+	if s.db == nil {
+		return s.persistEvent(game, "title_guessed", EventPayload{PlayerID: playerID})
+	}
+	round := currentRound(game)
+	if round == nil {
+		return errors.New("round not started")
+	}
+	if round.DBID == 0 {
+		if err := s.persistRound(game); err != nil {
+			return err
+		}
+	}
+	player, ok := s.store.FindPlayer(game, playerID)
+	if !ok || player.DBID == 0 {
+		return errors.New("player not found")
+	}
+	record := db.TitleGuess{
+		GameID:       game.DBID,
+		RoundID:      round.DBID,
+		PlayerID:     player.DBID,
+		DrawingIndex: drawingIndex,
+		Guess:        guess,
+		Correct:      true,
+	}
+	if err := s.dbFor(game).Create(&record).Error; err != nil {
+		return err
+	}
+	return s.persistEvent(game, "title_guessed", EventPayload{PlayerID: playerID})
+}
+
+func (s *Server) persistChainLink(game *Game, link ChainLinkEntry) error {
+	payload := EventPayload{PlayerID: link.PlayerID, Prompt: link.Text}
+	if s.db == nil {
+		return s.persistEvent(game, "chain_link_submitted", payload)
+	}
+	round := currentRound(game)
+	if round == nil {
+		return errors.New("round not started")
+	}
+	if round.DBID == 0 {
+		if err := s.persistRound(game); err != nil {
+			return err
+		}
+	}
+	player, ok := s.store.FindPlayer(game, link.PlayerID)
+	if !ok || player.DBID == 0 {
+		return errors.New("player not found")
+	}
+	record := db.ChainLink{
+		RoundID:    round.DBID,
+		ChainIndex: link.ChainIndex,
+		Step:       link.Step,
+		PlayerID:   player.DBID,
+		Kind:       link.Kind,
+		Text:       link.Text,
+		ImageData:  link.ImageData,
+		ImageHash:  link.ImageHash,
+	}
+	if err := s.dbFor(game).Create(&record).Error; err != nil {
+		return err
+	}
+	return s.persistEvent(game, "chain_link_submitted", payload)
+}
+
 func (s *Server) persistVote(game *Game, playerID int, roundNumber int, drawingIndex int, choiceText string, choiceType string) error {
 	if s.db == nil {
 		return s.persistEvent(game, "votes_submitted", EventPayload{
@@ -433,7 +641,7 @@ func (s *Server) persistVote(game *Game, playerID int, roundNumber int, drawingI
 		ChoiceText: choiceText,
 		ChoiceType: choiceType,
 	}
-	if err := s.db.Create(&record).Error; err != nil {
+	if err := s.dbFor(game).Create(&record).Error; err != nil {
 		return err
 	}
 	return s.persistEvent(game, "votes_submitted", EventPayload{
@@ -459,7 +667,7 @@ func (s *Server) persistLike(game *Game, entry *LikeEntry) error {
 		return errors.New("lie owner not found")
 	}
 	record := db.Like{RoundID: round.DBID, PlayerID: liker.DBID, DrawingID: round.Drawings[entry.DrawingIndex].DBID, GuessOwnerID: owner.DBID}
-	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record).Error; err != nil {
+	if err := s.dbFor(game).Clauses(clause.OnConflict{DoNothing: true}).Create(&record).Error; err != nil {
 		return err
 	}
 	entry.DBID = record.ID
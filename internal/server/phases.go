@@ -3,6 +3,8 @@ package server
 import (
 	"errors"
 	"time"
+
+	domain "picture-this/internal/game"
 )
 
 type transitionMode int
@@ -111,7 +113,15 @@ var phaseTransitions = map[string]phaseTransition{
 				nextPhase = phaseComplete
 				if round.Number < game.PromptsPerPlayer {
 					nextPhase = phaseDrawings
+				} else if len(domain.Awards(domainStateForScores(game))) > 0 {
+					nextRevealStage = revealStageAwards
+					nextRevealIndex = revealIndex
+					nextPhase = phaseResults
 				}
+			case revealStageAwards:
+				nextRevealStage = ""
+				nextRevealIndex = 0
+				nextPhase = phaseComplete
 			default:
 				nextRevealStage = revealStageGuesses
 			}
@@ -129,6 +139,10 @@ var phaseTransitions = map[string]phaseTransition{
 	},
 }
 
+var rulesetPhaseTransitions = map[string]map[string]phaseTransition{
+	rulesetTelephone: telephonePhaseTransitions,
+}
+
 func (s *Server) nextPhase(game *Game) (string, bool, error) {
 	next, err := s.advancePhase(game, transitionPreview, time.Time{})
 	if err != nil || next == "" {
@@ -141,13 +155,22 @@ func (s *Server) advancePhase(game *Game, mode transitionMode, at time.Time) (st
 	if game == nil {
 		return "", errors.New("game not found")
 	}
-	transition, ok := phaseTransitions[game.Phase]
+	transition, ok := transitionsForRuleset(game.Ruleset)[game.Phase]
 	if !ok {
 		return "", errors.New("no next phase")
 	}
 	return transition.advance(s, game, mode, at)
 }
 
+// transitionsForRuleset picks the phase machine for a ruleset; rulesets without
+// their own phases share the Drawful loop in phaseTransitions.
+func transitionsForRuleset(ruleset string) map[string]phaseTransition {
+	if transitions, ok := rulesetPhaseTransitions[ruleset]; ok {
+		return transitions
+	}
+	return phaseTransitions
+}
+
 func currentRound(game *Game) *RoundState {
 	if len(game.Rounds) == 0 {
 		return nil
@@ -177,6 +200,8 @@ func setPhaseAt(game *Game, phase string, at time.Time) {
 		at = time.Now().UTC()
 	}
 	game.PhaseStartedAt = at
+	game.PhaseExtension = 0
+	game.PhaseDeadline = time.Time{}
 }
 
 func applyPhase(game *Game, phase string, mode transitionMode, at time.Time) {
@@ -204,40 +229,3 @@ func initReveal(round *RoundState, drawingIndex int) {
 	round.RevealIndex = drawingIndex
 	round.RevealStage = revealStageGuesses
 }
-
-func drawingsComplete(game *Game) bool {
-	if game == nil {
-		return false
-	}
-	round := currentRound(game)
-	if round == nil {
-		return false
-	}
-	return len(round.Drawings) >= len(game.Players) && len(game.Players) > 0
-}
-
-func (s *Server) tryAdvanceToGuesses(gameID string) (bool, *Game, error) {
-	game, err := s.store.UpdateGame(gameID, func(game *Game) error {
-		if game.Phase != phaseDrawings {
-			return nil
-		}
-		if !drawingsComplete(game) {
-			return nil
-		}
-		round := currentRound(game)
-		if round != nil {
-			round.RevealIndex = 0
-			round.RevealStage = ""
-		}
-		setPhase(game, phaseGuesses)
-		return nil
-	})
-	if err != nil {
-		return false, nil, err
-	}
-	if game.Phase != phaseGuesses {
-		return false, game, nil
-	}
-	s.schedulePhaseTimer(game)
-	return true, game, nil
-}
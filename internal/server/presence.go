@@ -0,0 +1,333 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	presenceConnected = "connected"
+	presenceIdle      = "idle"
+	presenceGone      = "gone"
+
+	presenceSweepInterval = 2 * time.Second
+)
+
+// presenceEntry is one player's websocket presence on this node.
+type presenceEntry struct {
+	connections int
+	lastSeen    time.Time
+	goneSince   time.Time
+	// reported is the state clients last saw, so sweeps only broadcast
+	// changes.
+	reported string
+	alerted  bool
+}
+
+// presenceTracker records which players have a live websocket. Players who
+// have never connected are not tracked and have no presence state.
+type presenceTracker struct {
+	mu        sync.Mutex
+	games     map[string]map[int]*presenceEntry
+	idleAfter time.Duration
+}
+
+func newPresenceTracker(idleAfter time.Duration) *presenceTracker {
+	return &presenceTracker{games: make(map[string]map[int]*presenceEntry), idleAfter: idleAfter}
+}
+
+func (p *presenceTracker) stateLocked(entry *presenceEntry, now time.Time) string {
+	if entry.connections == 0 {
+		return presenceGone
+	}
+	if p.idleAfter > 0 && now.Sub(entry.lastSeen) >= p.idleAfter {
+		return presenceIdle
+	}
+	return presenceConnected
+}
+
+// update applies change to the player's entry and reports whether their
+// state changed.
+func (p *presenceTracker) update(gameID string, playerID int, now time.Time, create bool, change func(*presenceEntry)) bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	players := p.games[gameID]
+	entry := players[playerID]
+	if entry == nil {
+		if !create {
+			return false
+		}
+		if players == nil {
+			players = make(map[int]*presenceEntry)
+			p.games[gameID] = players
+		}
+		entry = &presenceEntry{}
+		players[playerID] = entry
+	}
+	change(entry)
+	state := p.stateLocked(entry, now)
+	if state == entry.reported {
+		return false
+	}
+	entry.reported = state
+	return true
+}
+
+func (p *presenceTracker) Connect(gameID string, playerID int, now time.Time) bool {
+	return p.update(gameID, playerID, now, true, func(entry *presenceEntry) {
+		entry.connections++
+		entry.lastSeen = now
+		entry.goneSince = time.Time{}
+		entry.alerted = false
+	})
+}
+
+func (p *presenceTracker) Disconnect(gameID string, playerID int, now time.Time) bool {
+	return p.update(gameID, playerID, now, false, func(entry *presenceEntry) {
+		if entry.connections > 0 {
+			entry.connections--
+		}
+		if entry.connections == 0 {
+			entry.goneSince = now
+		}
+	})
+}
+
+// Touch records activity from a connected player.
+func (p *presenceTracker) Touch(gameID string, playerID int, now time.Time) bool {
+	return p.update(gameID, playerID, now, false, func(entry *presenceEntry) {
+		entry.lastSeen = now
+	})
+}
+
+// States returns the presence of every tracked player in the game.
+func (p *presenceTracker) States(gameID string, now time.Time) map[int]string {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	states := make(map[int]string, len(p.games[gameID]))
+	for playerID, entry := range p.games[gameID] {
+		states[playerID] = p.stateLocked(entry, now)
+	}
+	return states
+}
+
+// Gone returns how long each disconnected player has been away.
+func (p *presenceTracker) Gone(gameID string, now time.Time) map[int]time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	gone := make(map[int]time.Duration)
+	for playerID, entry := range p.games[gameID] {
+		if entry.connections == 0 {
+			gone[playerID] = now.Sub(entry.goneSince)
+		}
+	}
+	return gone
+}
+
+// Refresh re-evaluates idle timeouts and returns the games where a player's
+// state changed without a connect or disconnect.
+func (p *presenceTracker) Refresh(now time.Time) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := make([]string, 0)
+	for gameID, players := range p.games {
+		dirty := false
+		for _, entry := range players {
+			if state := p.stateLocked(entry, now); state != entry.reported {
+				entry.reported = state
+				dirty = true
+			}
+		}
+		if dirty {
+			changed = append(changed, gameID)
+		}
+	}
+	return changed
+}
+
+// MarkAlerted reports whether the host still needs to hear that the player
+// is away; it returns true once per disconnect.
+func (p *presenceTracker) MarkAlerted(gameID string, playerID int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry := p.games[gameID][playerID]
+	if entry == nil || entry.alerted || entry.connections > 0 {
+		return false
+	}
+	entry.alerted = true
+	return true
+}
+
+func (p *presenceTracker) GameIDs() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ids := make([]string, 0, len(p.games))
+	for gameID := range p.games {
+		ids = append(ids, gameID)
+	}
+	return ids
+}
+
+func (p *presenceTracker) Forget(gameID string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.games, gameID)
+}
+
+type presenceAlertMessage struct {
+	Type        string `json:"type"`
+	PlayerID    int    `json:"player_id"`
+	PlayerName  string `json:"player_name"`
+	AwaySeconds int    `json:"away_seconds"`
+}
+
+// playerPresence lists the presence of the game's current players, leaving
+// out players who have never opened a websocket on this node.
+func (s *Server) playerPresence(game *Game) map[int]string {
+	states := s.presence.States(game.ID, time.Now().UTC())
+	presence := make(map[int]string, len(states))
+	for _, player := range game.Players {
+		if state, ok := states[player.ID]; ok {
+			presence[player.ID] = state
+		}
+	}
+	return presence
+}
+
+// StartPresence runs the away-player policy and pushes idle changes to
+// clients.
+func (s *Server) StartPresence(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(presenceSweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.sweepPresence(now.UTC())
+			}
+		}
+	}()
+}
+
+func (s *Server) sweepPresence(now time.Time) {
+	for _, gameID := range s.presence.Refresh(now) {
+		s.broadcastPresence(gameID)
+	}
+	for _, gameID := range s.presence.GameIDs() {
+		game, ok := s.store.GetGame(gameID)
+		if !ok {
+			s.presence.Forget(gameID)
+			continue
+		}
+		s.applyAwayPolicy(game, now)
+	}
+}
+
+// applyAwayPolicy hands a host who has been gone past HostAwaySeconds over
+// to the next player, tells the host about players who have been gone past
+// AwayAlertSeconds, and closes the current phase early once the only
+// players it is still waiting on have been gone past AwaySkipSeconds. The
+// whole policy is off in multi-instance mode, where presence only covers the
+// sockets on this node.
+func (s *Server) applyAwayPolicy(game *Game, now time.Time) {
+	if s.cluster != nil {
+		// A player whose socket moved to another node looks gone here.
+		return
+	}
+	gone := s.presence.Gone(game.ID, now)
+	if len(gone) == 0 {
+		return
+	}
+	if s.migrateAwayHost(game, gone, now) {
+		// The rest of the policy waits for the next sweep and the new host.
+		return
+	}
+	names := buildNameMap(game.Players)
+	alertAfter := time.Duration(s.cfg.AwayAlertSeconds) * time.Second
+	for playerID, away := range gone {
+		if _, ok := names[playerID]; !ok || alertAfter <= 0 || away < alertAfter || playerID == game.HostID {
+			continue
+		}
+		if s.presence.MarkAlerted(game.ID, playerID) {
+			log.Printf("player away game_id=%s player_id=%d away=%s", game.ID, playerID, away.Round(time.Second))
+			s.ws.SendToPlayer(game.ID, game.HostID, presenceAlertMessage{
+				Type:        "presence_alert",
+				PlayerID:    playerID,
+				PlayerName:  names[playerID],
+				AwaySeconds: int(away / time.Second),
+			})
+		}
+	}
+	skipAfter := time.Duration(s.cfg.AwaySkipSeconds) * time.Second
+	if skipAfter > 0 && onlyWaitingOnAway(game, gone, skipAfter) {
+		s.forceAdvancePhase(game.ID, game.Phase, "players_away")
+	}
+}
+
+// onlyWaitingOnAway is true when the phase is waiting on at least one player,
+// every one of them has been gone for at least after, and somebody else is
+// still here to keep playing.
+func onlyWaitingOnAway(game *Game, gone map[int]time.Duration, after time.Duration) bool {
+	waiting := waitingOnPlayers(game)
+	if len(waiting) == 0 {
+		return false
+	}
+	for _, playerID := range waiting {
+		if away, ok := gone[playerID]; !ok || away < after {
+			return false
+		}
+	}
+	for _, player := range game.Players {
+		if _, ok := gone[player.ID]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// waitingOnPlayers lists the players who still owe a submission before the
+// current phase can finish on its own.
+func waitingOnPlayers(game *Game) []int {
+	round := currentRound(game)
+	if round == nil {
+		return nil
+	}
+	switch {
+	case isChainStepPhase(game.Phase):
+		return pendingChainPlayers(game, round)
+	case game.Phase == phaseDrawings:
+		drawn := make(map[int]bool, len(round.Drawings))
+		for _, drawing := range round.Drawings {
+			drawn[drawing.PlayerID] = true
+		}
+		waiting := make([]int, 0)
+		for _, player := range game.Players {
+			if !drawn[player.ID] {
+				waiting = append(waiting, player.ID)
+			}
+		}
+		return waiting
+	case game.Phase == phaseGuesses:
+		if index := activeGuessDrawingIndex(game, round); index >= 0 {
+			return pendingGuessersForIndex(game, round, index)
+		}
+	case game.Phase == phaseGuessVotes:
+		if index := activeVoteDrawingIndex(game, round); index >= 0 {
+			return pendingVotersForIndex(game, round, index)
+		}
+	}
+	return nil
+}
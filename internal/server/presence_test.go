@@ -0,0 +1,182 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestPresenceTrackerStates(t *testing.T) {
+	tracker := newPresenceTracker(time.Minute)
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	if tracker.Touch("g", 1, start) {
+		t.Fatal("activity from an untracked player should not create an entry")
+	}
+	if !tracker.Connect("g", 1, start) {
+		t.Fatal("expected the first connection to change presence")
+	}
+	if tracker.Connect("g", 1, start) {
+		t.Fatal("a second tab should not change presence")
+	}
+	if got := tracker.States("g", start.Add(59*time.Second))[1]; got != presenceConnected {
+		t.Fatalf("expected connected, got %q", got)
+	}
+	if got := tracker.Refresh(start.Add(time.Minute)); len(got) != 1 || got[0] != "g" {
+		t.Fatalf("expected the idle player's game to be refreshed, got %v", got)
+	}
+	if !tracker.Touch("g", 1, start.Add(2*time.Minute)) {
+		t.Fatal("expected activity to wake an idle player")
+	}
+	if tracker.Disconnect("g", 1, start.Add(2*time.Minute)) {
+		t.Fatal("the player still has a connection open")
+	}
+	if !tracker.Disconnect("g", 1, start.Add(3*time.Minute)) {
+		t.Fatal("expected the last disconnect to mark the player gone")
+	}
+	if got := tracker.Gone("g", start.Add(4*time.Minute))[1]; got != time.Minute {
+		t.Fatalf("expected one minute away, got %s", got)
+	}
+	if !tracker.MarkAlerted("g", 1) || tracker.MarkAlerted("g", 1) {
+		t.Fatal("expected a single alert per disconnect")
+	}
+	tracker.Connect("g", 1, start.Add(5*time.Minute))
+	if _, gone := tracker.Gone("g", start.Add(5*time.Minute))[1]; gone {
+		t.Fatal("expected reconnecting to clear the away time")
+	}
+}
+
+func TestAwayPolicySkipsDisconnectedDrawers(t *testing.T) {
+	cfg := config.Default()
+	cfg.AwaySkipSeconds = 10
+	srv := New(nil, cfg)
+	game := srv.store.CreateGame(1)
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}, {ID: 3, Name: "Cy"}}
+		g.HostID = 1
+		g.Rounds = []RoundState{{Number: 1, Drawings: []DrawingEntry{
+			{PlayerID: 1, Prompt: "cat", ImageData: []byte{0x01}},
+			{PlayerID: 2, Prompt: "dog", ImageData: []byte{0x01}},
+		}}}
+		setPhase(g, phaseDrawings)
+		return nil
+	}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	now := time.Now().UTC()
+	for _, playerID := range []int{1, 2, 3} {
+		srv.presence.Connect(game.ID, playerID, now)
+	}
+	srv.presence.Disconnect(game.ID, 3, now)
+
+	srv.sweepPresence(now.Add(5 * time.Second))
+	if current, _ := srv.store.GetGame(game.ID); current.Phase != phaseDrawings {
+		t.Fatalf("expected to wait out the grace period, got %s", current.Phase)
+	}
+	srv.sweepPresence(now.Add(10 * time.Second))
+	current, _ := srv.store.GetGame(game.ID)
+	if current.Phase != phaseGuesses {
+		t.Fatalf("expected the away player's drawing to be skipped, got %s", current.Phase)
+	}
+	if snapshot := srv.snapshot(current); snapshot["player_presence"].(map[int]string)[3] != presenceGone {
+		t.Fatalf("expected the snapshot to show player 3 gone, got %v", snapshot["player_presence"])
+	}
+}
+
+func TestAwayPolicyIgnoresPlayersOnOtherNodes(t *testing.T) {
+	cfg := config.Default()
+	cfg.AwaySkipSeconds = 10
+	cfg.AwayAlertSeconds = 10
+	cfg.HostAwaySeconds = 10
+	srv := New(nil, cfg)
+	srv.cluster = newCluster("node-a", "http://node-a", 15)
+	game := srv.store.CreateGame(1)
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}, {ID: 3, Name: "Cy"}}
+		g.HostID = 1
+		g.Rounds = []RoundState{{Number: 1, Drawings: []DrawingEntry{
+			{PlayerID: 1, Prompt: "cat", ImageData: []byte{0x01}},
+			{PlayerID: 2, Prompt: "dog", ImageData: []byte{0x01}},
+		}}}
+		setPhase(g, phaseDrawings)
+		return nil
+	}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	now := time.Now().UTC()
+	for _, playerID := range []int{1, 2, 3} {
+		srv.presence.Connect(game.ID, playerID, now)
+	}
+	// Ada's and Cy's sockets reconnect through node B, which this node never sees.
+	srv.presence.Disconnect(game.ID, 1, now)
+	srv.presence.Disconnect(game.ID, 3, now)
+
+	srv.sweepPresence(now.Add(time.Minute))
+	current, _ := srv.store.GetGame(game.ID)
+	if current.Phase != phaseDrawings {
+		t.Fatalf("expected a player on another node not to be skipped, got %s", current.Phase)
+	}
+	if current.HostID != 1 {
+		t.Fatalf("expected a host on another node to keep hosting, got host %d", current.HostID)
+	}
+	if !srv.presence.MarkAlerted(game.ID, 3) {
+		t.Fatal("expected no away alert for a player on another node")
+	}
+}
+
+func TestOnlyWaitingOnAway(t *testing.T) {
+	game := &Game{
+		Players: []Player{{ID: 1}, {ID: 2}},
+		Rounds:  []RoundState{{Number: 1, Drawings: []DrawingEntry{{PlayerID: 1}}}},
+		Phase:   phaseDrawings,
+	}
+	if !onlyWaitingOnAway(game, map[int]time.Duration{2: time.Minute}, time.Second) {
+		t.Fatal("expected to skip the only missing drawing")
+	}
+	if onlyWaitingOnAway(game, map[int]time.Duration{2: time.Millisecond}, time.Second) {
+		t.Fatal("expected to wait for a player who just dropped")
+	}
+	if onlyWaitingOnAway(game, map[int]time.Duration{1: time.Minute, 2: time.Minute}, time.Second) {
+		t.Fatal("expected the timer to handle a game everyone has left")
+	}
+	game.Phase = phaseResults
+	if onlyWaitingOnAway(game, map[int]time.Duration{2: time.Minute}, time.Second) {
+		t.Fatal("timed phases do not wait on players")
+	}
+}
+
+func TestWebsocketBindsAuthenticatedPlayer(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := srv.store.CreateGame(1)
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Ada"}}
+		g.PlayerAuthTokens = map[int]string{1: "secret"}
+		return nil
+	}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/games/" + game.ID + "?player_id=1&auth_token="
+
+	if _, resp, err := websocket.DefaultDialer.Dial(url+"wrong", nil); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a bad token to be rejected, got %v", err)
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(url+"secret", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	waitFor(t, time.Second, "the player to be connected", func() bool {
+		return srv.presence.States(game.ID, time.Now())[1] == presenceConnected
+	})
+	conn.Close()
+	waitFor(t, time.Second, "the player to be gone", func() bool {
+		return srv.presence.States(game.ID, time.Now())[1] == presenceGone
+	})
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+
+	"picture-this/internal/blobstore"
+)
+
+// renderCacheEntries bounds how many renders the cache remembers.
+const renderCacheEntries = 256
+
+// renderCache remembers expensive renders such as time-lapse GIFs and posters
+// by a key derived from their input, so public endpoints encode each one once.
+// With a blob store the rendered bytes live there and an entry only holds the
+// blob hash; without one the entry holds the bytes.
+type renderCache struct {
+	mu       sync.Mutex
+	entries  map[string]renderEntry
+	order    []string
+	inflight map[string]*renderCall
+}
+
+type renderEntry struct {
+	hash string
+	data []byte
+}
+
+// renderCall lets concurrent requests for the same key share one render.
+type renderCall struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+func newRenderCache() *renderCache {
+	return &renderCache{entries: make(map[string]renderEntry), inflight: make(map[string]*renderCall)}
+}
+
+func (r *renderCache) get(key string) (renderEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[key]
+	return entry, ok
+}
+
+func (r *renderCache) put(key string, entry renderEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[key]; !ok {
+		r.order = append(r.order, key)
+	}
+	r.entries[key] = entry
+	for len(r.order) > renderCacheEntries {
+		delete(r.entries, r.order[0])
+		r.order = r.order[1:]
+	}
+}
+
+// cachedRender returns the render stored under key, calling render only when
+// there is none and no other request is already rendering it.
+func (s *Server) cachedRender(ctx context.Context, key string, render func() ([]byte, error)) ([]byte, error) {
+	if entry, ok := s.renders.get(key); ok {
+		if entry.hash == "" {
+			return entry.data, nil
+		}
+		data, err := s.blobs.Get(ctx, entry.hash)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, blobstore.ErrNotFound) {
+			log.Printf("cached render unavailable key=%s hash=%s error=%v", key, entry.hash, err)
+		}
+	}
+
+	s.renders.mu.Lock()
+	if call, ok := s.renders.inflight[key]; ok {
+		s.renders.mu.Unlock()
+		<-call.done
+		return call.data, call.err
+	}
+	call := &renderCall{done: make(chan struct{})}
+	s.renders.inflight[key] = call
+	s.renders.mu.Unlock()
+	defer func() {
+		s.renders.mu.Lock()
+		delete(s.renders.inflight, key)
+		s.renders.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.data, call.err = render()
+	if call.err != nil {
+		return nil, call.err
+	}
+	if s.blobs == nil {
+		s.renders.put(key, renderEntry{data: call.data})
+		return call.data, nil
+	}
+	hash, err := s.blobs.Put(ctx, call.data)
+	if err != nil {
+		// The render is still good; the next request just renders again.
+		log.Printf("cached render not stored key=%s error=%v", key, err)
+		return call.data, nil
+	}
+	s.renders.put(key, renderEntry{hash: hash})
+	return call.data, nil
+}
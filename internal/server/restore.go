@@ -3,6 +3,7 @@ package server
 import (
 	"errors"
 	"fmt"
+	"log"
 	"sort"
 	"strings"
 	"time"
@@ -19,14 +20,39 @@ func (s *Server) RestoreActiveGames() error {
 		return err
 	}
 	for _, record := range records {
-		if _, _, err := s.restoreGameFromDB(fmt.Sprintf("%d", record.ID)); err != nil {
+		game, _, err := s.loadGameFromDB(fmt.Sprintf("%d", record.ID), false)
+		if errors.Is(err, errGameOwnedElsewhere) {
+			continue
+		}
+		if err != nil {
 			return err
 		}
+		s.resumeRestoredGame(game)
 	}
 	return nil
 }
 
+// restoreGameFromDB loads a game for the admin restore flow. The game comes
+// back paused so the host can wait for players to reclaim their seats.
 func (s *Server) restoreGameFromDB(param string) (*Game, string, error) {
+	return s.loadGameFromDB(param, true)
+}
+
+// resumeRestoredGame re-arms the phase timer for a game rebuilt after a
+// restart. The deadline comes from the persisted phase start, so a phase that
+// ran out while the server was down advances straight away.
+func (s *Server) resumeRestoredGame(game *Game) {
+	if game == nil || game.Phase == phasePaused || game.Phase == phaseComplete {
+		return
+	}
+	if s.phaseDuration(game) <= 0 {
+		return
+	}
+	log.Printf("game timer restored game_id=%s phase=%s", game.ID, game.Phase)
+	s.schedulePhaseTimer(game)
+}
+
+func (s *Server) loadGameFromDB(param string, paused bool) (*Game, string, error) {
 	if s.db == nil {
 		return nil, "", errors.New("database not configured")
 	}
@@ -39,7 +65,7 @@ func (s *Server) restoreGameFromDB(param string) (*Game, string, error) {
 	if err := s.db.First(&record, dbID).Error; err != nil {
 		return nil, displayID, err
 	}
-	if record.Phase == phaseComplete {
+	if paused && record.Phase == phaseComplete {
 		return nil, displayID, errors.New("game already complete")
 	}
 
@@ -50,14 +76,103 @@ func (s *Server) restoreGameFromDB(param string) (*Game, string, error) {
 		return existing, displayID, nil
 	}
 
+	owned, err := s.acquireGameLease(record.ID, fmt.Sprintf("game-%d", record.ID))
+	if err != nil {
+		return nil, displayID, err
+	}
+	if !owned {
+		return nil, displayID, errGameOwnedElsewhere
+	}
+
 	players, err := s.loadPlayers(record.ID)
 	if err != nil {
 		return nil, displayID, err
 	}
-	rounds, err := s.loadRounds(record.ID)
+	game, err := s.rebuildGameFromEvents(record, players)
 	if err != nil {
 		return nil, displayID, err
 	}
+	journaled := game != nil
+	if game == nil {
+		game, err = s.rebuildGameFromRows(record, players)
+		if err != nil {
+			return nil, displayID, err
+		}
+	}
+	if game.Ruleset == "" {
+		game.Ruleset = rulesetLegacy
+	}
+	if paused && game.Phase != phasePaused {
+		game.PausedPhase = game.Phase
+		game.Phase = phasePaused
+		game.PhaseStartedAt = time.Now().UTC()
+	}
+	authHashes := make(map[uint]string, len(players))
+	for _, player := range players {
+		authHashes[player.ID] = player.AuthTokenHash
+	}
+	game.PlayerAuthHashes = make(map[int]string)
+	for i := range game.Players {
+		game.Players[i].Claimed = false
+		if hash := authHashes[game.Players[i].DBID]; hash != "" {
+			game.PlayerAuthHashes[game.Players[i].ID] = hash
+			continue
+		}
+		ensurePlayerAuthToken(game, game.Players[i].ID)
+	}
+	game.DisplayToken = ""
+	game.DisplayTokenHash = record.DisplayTokenHash
+	if game.DisplayTokenHash == "" {
+		// Games created before display tokens were stored get one now.
+		issueDisplayToken(game)
+		if err := s.db.Model(&db.Game{}).Where("id = ?", record.ID).Update("display_token_hash", game.DisplayTokenHash).Error; err != nil {
+			return nil, displayID, err
+		}
+	}
+
+	if err := s.store.restoreGame(game, journaled); err != nil {
+		return nil, displayID, err
+	}
+	return game, displayID, nil
+}
+
+// rebuildGameFromEvents replays the typed event stream. It returns nil when
+// the game predates the stream or the stream is behind the games row, so the
+// caller can fall back to the row tables.
+func (s *Server) rebuildGameFromEvents(record db.Game, players []db.Player) (*Game, error) {
+	events, err := s.loadGameEvents(record.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+	game, err := replayGameEvents(events, 0)
+	if err != nil {
+		log.Printf("game event replay failed game_id=%d error=%v", record.ID, err)
+		return nil, nil
+	}
+	if game.Version < record.Version {
+		log.Printf("game event stream behind game_id=%d events=%d row=%d", record.ID, game.Version, record.Version)
+		return nil, nil
+	}
+	game.ID = fmt.Sprintf("game-%d", record.ID)
+	game.DBID = record.ID
+	hashes := make(map[uint]string, len(players))
+	for _, player := range players {
+		hashes[player.ID] = player.RecoveryCodeHash
+	}
+	for i := range game.Players {
+		game.Players[i].RecoveryHash = hashes[game.Players[i].DBID]
+	}
+	return game, nil
+}
+
+func (s *Server) rebuildGameFromRows(record db.Game, players []db.Player) (*Game, error) {
+	rounds, err := s.loadRounds(record.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	roundIDs := make([]uint, 0, len(rounds))
 	for _, round := range rounds {
@@ -66,16 +181,22 @@ func (s *Server) restoreGameFromDB(param string) (*Game, string, error) {
 
 	prompts, drawings, guesses, votes, likes, err := s.loadRoundAssets(roundIDs)
 	if err != nil {
-		return nil, displayID, err
+		return nil, err
 	}
 
+	phaseStartedAt := record.PhaseStartedAt.UTC()
+	if record.PhaseStartedAt.IsZero() {
+		phaseStartedAt = time.Now().UTC()
+	}
 	game := &Game{
 		ID:               fmt.Sprintf("game-%d", record.ID),
 		DBID:             record.ID,
 		JoinCode:         record.JoinCode,
-		Phase:            phasePaused,
-		PausedPhase:      record.Phase,
-		PhaseStartedAt:   time.Now().UTC(),
+		Phase:            record.Phase,
+		PhaseStartedAt:   phaseStartedAt,
+		PausedPhase:      record.PausedPhase,
+		PausedRemaining:  time.Duration(record.PausedRemainingMs) * time.Millisecond,
+		PhaseExtension:   time.Duration(record.PhaseExtensionMs) * time.Millisecond,
 		MinPlayers:       record.MinPlayers,
 		MaxPlayers:       record.MaxPlayers,
 		LobbyLocked:      record.LobbyLocked,
@@ -88,21 +209,28 @@ func (s *Server) restoreGameFromDB(param string) (*Game, string, error) {
 		AudienceEnabled:  record.AudienceEnabled,
 		JokesEnabled:     record.JokesEnabled,
 		PublicReplay:     record.PublicReplay,
+		TeamCount:        record.TeamCount,
+		ScoringRules:     scoringRulesFromRecord(record.ScoringRules),
+		Timers:           timerSettingsFromRecord(record.Timers),
 		Version:          record.Version,
 	}
-	if game.Ruleset == "" {
-		game.Ruleset = rulesetLegacy
+
+	if record.PhaseDeadline != nil {
+		game.PhaseDeadline = record.PhaseDeadline.UTC()
 	}
 
 	game.Players = buildPlayers(players, game)
-	for _, player := range game.Players {
-		ensurePlayerAuthToken(game, player.ID)
-	}
 	game.Rounds = buildRounds(rounds, prompts, drawings, guesses, votes, likes)
+	if err := s.loadChainLinks(game); err != nil {
+		return nil, err
+	}
+	if err := s.loadTitleGuesses(game); err != nil {
+		return nil, err
+	}
 	game.UsedPrompts = usedPrompts(game.Rounds)
 
 	if round := currentRound(game); round != nil {
-		switch game.PausedPhase {
+		switch game.Phase {
 		case phaseGuesses:
 			round.RevealStage = ""
 			round.RevealIndex = firstPendingGuessDrawingIndex(game, round)
@@ -118,11 +246,50 @@ func (s *Server) restoreGameFromDB(param string) (*Game, string, error) {
 			}
 		}
 	}
+	return game, nil
+}
+
+func (s *Server) loadChainLinks(game *Game) error {
+	if !telephoneEnabled(game) {
+		return nil
+	}
+	for i := range game.Rounds {
+		round := &game.Rounds[i]
+		var links []db.ChainLink
+		if err := s.db.Where("round_id = ?", round.DBID).Order("step asc, chain_index asc").Find(&links).Error; err != nil {
+			return err
+		}
+		for _, link := range links {
+			round.ChainLinks = append(round.ChainLinks, ChainLinkEntry{
+				ChainIndex: link.ChainIndex,
+				Step:       link.Step,
+				PlayerID:   int(link.PlayerID),
+				Kind:       link.Kind,
+				Text:       link.Text,
+				ImageData:  link.ImageData,
+				ImageHash:  link.ImageHash,
+				DBID:       link.ID,
+			})
+		}
+	}
+	return nil
+}
 
-	if err := s.store.RestoreGame(game); err != nil {
-		return nil, displayID, err
+func (s *Server) loadTitleGuesses(game *Game) error {
+	for i := range game.Rounds {
+		round := &game.Rounds[i]
+		var guesses []db.TitleGuess
+		if err := s.db.Where("round_id = ? AND correct", round.DBID).Order("id asc").Find(&guesses).Error; err != nil {
+			return err
+		}
+		for _, guess := range guesses {
+			round.TitleGuesses = append(round.TitleGuesses, TitleGuessEntry{
+				PlayerID:     int(guess.PlayerID),
+				DrawingIndex: guess.DrawingIndex,
+			})
+		}
 	}
-	return game, displayID, nil
+	return nil
 }
 
 func (s *Server) loadPlayers(gameID uint) ([]db.Player, error) {
@@ -176,10 +343,12 @@ func buildPlayers(records []db.Player, game *Game) []Player {
 			DBID:         record.ID,
 			Name:         record.Name,
 			Avatar:       record.AvatarImage,
+			AvatarHash:   record.AvatarHash,
 			IsHost:       record.IsHost,
 			Color:        record.Color,
 			Claimed:      false,
 			RecoveryHash: record.RecoveryCodeHash,
+			Team:         record.Team,
 		}
 		players = append(players, player)
 		if record.IsHost {
@@ -213,7 +382,7 @@ func buildRounds(rounds []db.Round, prompts []db.Prompt, drawings []db.Drawing,
 
 	states := make([]RoundState, 0, len(rounds))
 	for _, round := range rounds {
-		state := RoundState{Number: round.Number, DBID: round.ID, RevealIndex: round.ActiveDrawingIndex, RevealStage: round.RevealStage}
+		state := RoundState{Number: round.Number, DBID: round.ID, RevealIndex: round.ActiveDrawingIndex, RevealStage: round.RevealStage, ChainStep: round.ChainStep}
 		promptRecords := promptsByRound[round.ID]
 		promptTextByID := map[uint]string{}
 		for _, prompt := range promptRecords {
@@ -239,6 +408,8 @@ func buildRounds(rounds []db.Round, prompts []db.Prompt, drawings []db.Drawing,
 				ImageData: drawing.ImageData,
 				Prompt:    promptText,
 				DBID:      drawing.ID,
+				Strokes:   drawing.StrokeData,
+				ImageHash: drawing.ImageHash,
 			})
 			drawingIndexByID[drawing.ID] = len(state.Drawings) - 1
 		}
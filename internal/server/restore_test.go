@@ -0,0 +1,83 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+)
+
+func restoredGuessGame(startedAt time.Time) *Game {
+	return &Game{
+		ID:               "game-7",
+		JoinCode:         "RSTORE",
+		Phase:            phaseGuesses,
+		PhaseStartedAt:   startedAt,
+		MinPlayers:       3,
+		UsedPrompts:      map[string]struct{}{},
+		KickedPlayers:    map[string]struct{}{},
+		HostID:           1,
+		PlayerAuthTokens: map[int]string{},
+		Players:          []Player{{ID: 1, Name: "Ada", IsHost: true}, {ID: 2, Name: "Ben"}, {ID: 3, Name: "Cam"}},
+		PromptsPerPlayer: 1,
+		Ruleset:          rulesetDrawful,
+		Rounds: []RoundState{{
+			Number:   1,
+			Prompts:  []PromptEntry{{PlayerID: 1, Text: "cat"}, {PlayerID: 2, Text: "dog"}},
+			Drawings: []DrawingEntry{{PlayerID: 1, Prompt: "cat", ImageData: []byte{1}}, {PlayerID: 2, Prompt: "dog", ImageData: []byte{2}}},
+		}},
+	}
+}
+
+func TestCrashRestartAutoAdvancesOverduePhase(t *testing.T) {
+	cfg := config.Default()
+	cfg.GuessDurationSeconds = 60
+	srv := New(nil, cfg)
+	game := restoredGuessGame(time.Now().UTC().Add(-5 * time.Minute))
+	if err := srv.store.RestoreGame(game); err != nil {
+		t.Fatalf("restore game: %v", err)
+	}
+	srv.resumeRestoredGame(game)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		current, _ := srv.store.GetGame(game.ID)
+		if current.Phase == phaseGuessVotes {
+			round := currentRound(current)
+			if len(round.Guesses) != requiredGuessCountForDrawing(current, round, 0) {
+				t.Fatalf("expected auto-filled guesses, got %d", len(round.Guesses))
+			}
+			srv.cancelPhaseTimer(game.ID)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("overdue restored game did not auto-advance")
+}
+
+func TestCrashRestartKeepsRemainingPhaseTime(t *testing.T) {
+	cfg := config.Default()
+	cfg.GuessDurationSeconds = 60
+	srv := New(nil, cfg)
+	now := time.Now().UTC()
+	game := restoredGuessGame(now.Add(-45 * time.Second))
+	if got := phaseRemaining(game, srv.phaseDuration(game), now); got != 15*time.Second {
+		t.Fatalf("expected 15s remaining, got %s", got)
+	}
+	if err := srv.store.RestoreGame(game); err != nil {
+		t.Fatalf("restore game: %v", err)
+	}
+	srv.resumeRestoredGame(game)
+	defer srv.cancelPhaseTimer(game.ID)
+	time.Sleep(50 * time.Millisecond)
+	current, _ := srv.store.GetGame(game.ID)
+	if current.Phase != phaseGuesses {
+		t.Fatalf("expected game to keep running guesses, got %s", current.Phase)
+	}
+	srv.timersMu.Lock()
+	_, scheduled := srv.timers[game.ID]
+	srv.timersMu.Unlock()
+	if !scheduled {
+		t.Fatal("expected restored game to have a phase timer")
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"picture-this/internal/db"
+	domain "picture-this/internal/game"
+)
+
+func scoringRules(game *Game) domain.ScoringRules {
+	return game.ScoringRules.Effective()
+}
+
+// scoringMultiplier is the final round multiplier for round, or 1.
+func scoringMultiplier(game *Game, round *RoundState) int {
+	if round == nil {
+		return 1
+	}
+	return scoringRules(game).RoundMultiplier(round.Number-1, game.PromptsPerPlayer)
+}
+
+func scoringRulesPayload(game *Game) map[string]any {
+	rules := scoringRules(game)
+	return map[string]any{
+		"correct_guess":          rules.CorrectGuess,
+		"fooled_player":          rules.FooledPlayer,
+		"artist_bonus":           rules.ArtistBonus,
+		"final_round_multiplier": rules.FinalRoundMultiplier,
+		"like_points":            rules.LikePoints,
+		"title_guess_bonus":      rules.TitleGuessBonus,
+	}
+}
+
+func dbScoringRules(rules domain.ScoringRules) db.ScoringRules {
+	rules = rules.Effective()
+	return db.ScoringRules{
+		CorrectGuess:         rules.CorrectGuess,
+		FooledPlayer:         rules.FooledPlayer,
+		ArtistBonus:          rules.ArtistBonus,
+		FinalRoundMultiplier: rules.FinalRoundMultiplier,
+		LikePoints:           rules.LikePoints,
+		TitleGuessBonus:      rules.TitleGuessBonus,
+	}
+}
+
+func scoringRuleColumns(rules domain.ScoringRules) map[string]any {
+	record := dbScoringRules(rules)
+	return map[string]any{
+		"scoring_correct_guess":          record.CorrectGuess,
+		"scoring_fooled_player":          record.FooledPlayer,
+		"scoring_artist_bonus":           record.ArtistBonus,
+		"scoring_final_round_multiplier": record.FinalRoundMultiplier,
+		"scoring_like_points":            record.LikePoints,
+		"scoring_title_guess_bonus":      record.TitleGuessBonus,
+	}
+}
+
+func scoringRulesFromRecord(record db.ScoringRules) domain.ScoringRules {
+	return domain.ScoringRules{
+		CorrectGuess:         record.CorrectGuess,
+		FooledPlayer:         record.FooledPlayer,
+		ArtistBonus:          record.ArtistBonus,
+		FinalRoundMultiplier: record.FinalRoundMultiplier,
+		LikePoints:           record.LikePoints,
+		TitleGuessBonus:      record.TitleGuessBonus,
+	}
+}
+
+func titleGuessAllowed(game *Game) bool {
+	return domain.TitleGuessAllowed(domain.Ruleset(game.Ruleset))
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"testing"
+
+	domain "picture-this/internal/game"
+)
+
+func TestScoreDeltasUseGameScoringRules(t *testing.T) {
+	game := &Game{
+		Ruleset:          rulesetDrawful,
+		PromptsPerPlayer: 2,
+		ScoringRules:     domain.ScoringRules{CorrectGuess: 200, FooledPlayer: 100, ArtistBonus: 50, FinalRoundMultiplier: 3, LikePoints: 10},
+		Players:          []Player{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}, {ID: 3, Name: "Cam"}},
+	}
+	round := RoundState{
+		Drawings: []DrawingEntry{{PlayerID: 1, Prompt: "real"}},
+		Guesses:  []GuessEntry{{PlayerID: 2, DrawingIndex: 0, Text: "lie"}},
+		Votes: []VoteEntry{
+			{PlayerID: 2, DrawingIndex: 0, ChoiceText: "real", ChoiceType: voteChoicePrompt},
+			{PlayerID: 3, DrawingIndex: 0, ChoiceText: "lie", ChoiceType: voteChoiceGuess},
+		},
+		Likes: []LikeEntry{{PlayerID: 3, DrawingIndex: 0, GuessOwnerID: 2}},
+	}
+	first, final := round, round
+	first.Number, final.Number = 1, 2
+	game.Rounds = []RoundState{first, final}
+
+	names := buildNameMap(game.Players)
+	deltas := drawingScoreDeltas(game, &game.Rounds[1], 0, names)
+	if len(deltas) != 2 || deltas[0]["player_id"] != 2 || deltas[0]["delta"] != 930 || deltas[0]["multiplier"] != 3 {
+		t.Fatalf("unexpected final round deltas: %v", deltas)
+	}
+	if deltas := drawingScoreDeltas(game, &game.Rounds[0], 0, names); deltas[0]["delta"] != 310 {
+		t.Fatalf("expected first round without multiplier, got %v", deltas)
+	}
+
+	scores := buildScores(game)
+	if scores[0]["player_id"] != 2 || scores[0]["score"] != 1240 {
+		t.Fatalf("expected scores to match the deltas, got %v", scores)
+	}
+	if rules := scoringRulesPayload(game); rules["final_round_multiplier"] != 3 {
+		t.Fatalf("expected effective rules in the snapshot, got %v", rules)
+	}
+}
+
+func TestUnsetScoringRulesUseDefaults(t *testing.T) {
+	game := &Game{Ruleset: rulesetDrawful}
+	if scoringRules(game) != domain.DefaultScoringRules() {
+		t.Fatalf("expected defaults, got %+v", scoringRules(game))
+	}
+}
@@ -1,10 +1,12 @@
 package server
 
 import (
+	"log"
 	"net/http"
 	"sync"
 	"time"
 
+	"picture-this/internal/blobstore"
 	"picture-this/internal/config"
 
 	"github.com/gin-gonic/gin"
@@ -27,11 +29,18 @@ type Server struct {
 	rateMu          sync.Mutex
 	rateEntries     map[string]*rateEntry
 	rateNow         func() time.Time
+	cluster         *cluster
+	blobs           blobstore.BlobStore
+	presence        *presenceTracker
+	streams         *stateStreams
+	acks            *commandAcks
+	renders         *renderCache
+	commandTxs      sync.Map
 }
 
 func New(conn *gorm.DB, cfg config.Config) *Server {
 	registerValidators()
-	return &Server{
+	srv := &Server{
 		store:           NewStore(),
 		db:              conn,
 		ws:              newWSHub(),
@@ -43,12 +52,28 @@ func New(conn *gorm.DB, cfg config.Config) *Server {
 		promptJobs:      make(map[string]*promptGenerateJob),
 		rateEntries:     make(map[string]*rateEntry),
 		rateNow:         time.Now,
+		presence:        newPresenceTracker(time.Duration(cfg.PresenceIdleSeconds) * time.Second),
+		streams:         newStateStreams(),
+		acks:            newCommandAcks(),
+		renders:         newRenderCache(),
 	}
+	srv.store.SetJournal(srv.journalGameEvents)
+	srv.store.SetTransaction(srv.persistCommand)
+	srv.store.SetLoader(srv.loadGame)
+	if conn != nil && cfg.NodeURL != "" {
+		srv.cluster = newCluster(cfg.NodeID, cfg.NodeURL, cfg.LeaseTTLSeconds)
+	}
+	if blobs, err := blobstore.FromConfig(cfg); err != nil {
+		log.Printf("blob store disabled: %v", err)
+	} else if blobs != nil {
+		srv.blobs = blobs
+	}
+	return srv
 }
 
 func (s *Server) Handler() http.Handler {
 	router := gin.New()
-	router.Use(gin.Logger(), gin.Recovery())
+	router.Use(gin.Logger(), gin.Recovery(), s.clusterMiddleware())
 	_ = router.SetTrustedProxies(nil)
 
 	router.GET("/", s.handleHome)
@@ -73,6 +98,7 @@ func (s *Server) Handler() http.Handler {
 		admin.POST("/prompts/:id/delete", s.handleAdminPromptDelete)
 		admin.POST("/:gameID/restore", s.handleAdminRestoreGame)
 		admin.POST("/:gameID/resume", s.handleAdminResumeGame)
+		admin.GET("/:gameID/versions/:version", s.handleAdminGameVersion)
 		admin.GET("/:gameID", s.handleAdminView)
 	}
 
@@ -87,6 +113,8 @@ func (s *Server) Handler() http.Handler {
 		api.GET("/games/:gameID/audience/state", s.handleAudienceState)
 		api.GET("/games/:gameID/events", s.handleEvents)
 		api.GET("/games/:gameID/results", s.handleResults)
+		api.GET("/games/:gameID/poster.png", s.handlePoster)
+		api.GET("/games/:gameID/rounds/:round/drawings/:index/timelapse.gif", s.handleTimeLapse)
 		api.GET("/games/:gameID/players/:playerID/prompt", s.handlePlayerPrompt)
 		api.POST("/games/:gameID/join", s.handleJoinGame)
 		api.POST("/games/:gameID/players/recover", s.handleRecoverPlayer)
@@ -97,20 +125,29 @@ func (s *Server) Handler() http.Handler {
 		api.POST("/games/:gameID/drawings", s.handleDrawings)
 		api.POST("/games/:gameID/guesses", s.handleGuesses)
 		api.POST("/games/:gameID/votes", s.handleVotes)
+		api.POST("/games/:gameID/chain", s.handleChainLink)
 		api.POST("/games/:gameID/likes", s.handleLikes)
 		api.POST("/games/:gameID/settings", s.handleSettings)
 		api.POST("/games/:gameID/kick", s.handleKick)
+		api.POST("/games/:gameID/transfer-host", s.handleTransferHost)
 		api.POST("/games/:gameID/advance", s.handleAdvance)
+		api.POST("/games/:gameID/pause", s.handlePauseGame)
+		api.POST("/games/:gameID/add-time", s.handleAddTime)
 		api.POST("/games/:gameID/resume", s.handleResumeGame)
 		api.POST("/games/:gameID/end", s.handleEndGame)
 	}
 
 	router.GET("/ws/games/:gameID", s.handleWebsocket)
+	router.GET("/sse/games/:gameID", s.handleSSE)
 	router.GET("/ws/home", s.handleHomeWebsocket)
+	router.GET("/metrics", s.handleMetrics)
+	router.GET("/blobs/:hash", s.handleBlob)
 	router.Static("/static", "./static")
 	return router
 }
 
 func (s *Server) snapshot(game *Game) map[string]any {
-	return snapshotWithConfig(game, s.cfg)
+	snapshot := snapshotWithConfig(game, s.cfg)
+	snapshot["player_presence"] = s.playerPresence(game)
+	return snapshot
 }
@@ -3,10 +3,15 @@ package server
 import (
 	"encoding/json"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"picture-this/internal/config"
+
 	"github.com/gorilla/websocket"
 )
 
@@ -27,9 +32,10 @@ func TestWebsocketRolePayloadIsolation(t *testing.T) {
 	_, ts := newServerHarness(t)
 
 	gameID := createGame(t, ts)
+	hostID := joinPlayer(t, ts, gameID, "Host")
 	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws/games/" + gameID
 
-	hostConn, _, err := websocket.DefaultDialer.Dial(wsURL+"?role=host", nil)
+	hostConn, _, err := websocket.DefaultDialer.Dial(wsURL+"?role=host&player_id="+strconv.Itoa(hostID)+"&auth_token="+getTestAuthToken(gameID, hostID), nil)
 	if err != nil {
 		t.Skipf("skipping test; websocket dial unavailable: %v", err)
 	}
@@ -61,6 +67,107 @@ func TestWebsocketRolePayloadIsolation(t *testing.T) {
 	expectNoWSMessage(t, playerConn, 350*time.Millisecond)
 }
 
+func TestWebsocketRolesAndOriginsAreAuthorized(t *testing.T) {
+	cfg := config.Default()
+	cfg.AllowedOrigins = []string{"https://screen.example"}
+	srv := New(nil, cfg)
+	game := srv.store.CreateGame(1)
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}}
+		g.HostID = 1
+		g.PlayerAuthTokens = map[int]string{1: "host-secret", 2: "ben-secret"}
+		return nil
+	}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	base := "ws" + strings.TrimPrefix(server.URL, "http")
+	dial := func(path, origin string) int {
+		header := http.Header{}
+		if origin != "" {
+			header.Set("Origin", origin)
+		}
+		conn, res, err := websocket.DefaultDialer.Dial(base+path, header)
+		if err == nil {
+			conn.Close()
+			return http.StatusSwitchingProtocols
+		}
+		if res == nil {
+			t.Fatalf("dial %s: %v", path, err)
+		}
+		return res.StatusCode
+	}
+
+	gamePath := "/ws/games/" + game.ID
+	for _, tc := range []struct {
+		name   string
+		path   string
+		origin string
+		want   int
+	}{
+		{"host", gamePath + "?role=host&player_id=1&auth_token=host-secret", "", http.StatusSwitchingProtocols},
+		{"host without token", gamePath + "?role=host", "", http.StatusForbidden},
+		{"host role for a player", gamePath + "?role=host&player_id=2&auth_token=ben-secret", "", http.StatusForbidden},
+		{"display", gamePath + "?role=display&display_token=" + game.DisplayToken, "", http.StatusSwitchingProtocols},
+		{"display with a wrong token", gamePath + "?role=display&display_token=guess", "", http.StatusForbidden},
+		{"unknown role", gamePath + "?role=admin", "", http.StatusForbidden},
+		{"bad player token", gamePath + "?player_id=2&auth_token=wrong", "", http.StatusUnauthorized},
+		{"same origin", gamePath, server.URL, http.StatusSwitchingProtocols},
+		{"allowed origin", gamePath, "https://screen.example", http.StatusSwitchingProtocols},
+		{"foreign origin", gamePath, "https://evil.example", http.StatusForbidden},
+		{"foreign origin on home", "/ws/home", "https://evil.example", http.StatusForbidden},
+	} {
+		if got := dial(tc.path, tc.origin); got != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.want, got)
+		}
+	}
+
+	current, _ := srv.store.GetGame(game.ID)
+	if token, _ := srv.snapshotForPlayer(current, 1)["display_token"].(string); token != game.DisplayToken {
+		t.Fatalf("expected the host snapshot to carry the display token, got %q", token)
+	}
+	if _, ok := srv.snapshotForPlayer(current, 2)["display_token"]; ok {
+		t.Fatal("expected other players not to see the display token")
+	}
+}
+
+func TestDisplayPartialRequiresDisplayTokenAcrossReloads(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := srv.store.CreateGame(1)
+	token := game.DisplayToken
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	partial := "/partials/games/" + game.ID + "/display"
+
+	if res := doRequest(t, server, http.MethodGet, partial, nil); res.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 without a token, got %d", res.StatusCode)
+	}
+	if res := doRequest(t, server, http.MethodGet, partial+"?token=guess", nil); res.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 for a wrong token, got %d", res.StatusCode)
+	}
+	if res := doRequest(t, server, http.MethodGet, partial+"?token="+token, nil); res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 with the display token, got %d", res.StatusCode)
+	}
+
+	// A reload only has the stored hash, which still admits the old screen.
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Ada"}}
+		g.HostID = 1
+		g.DisplayToken = ""
+		return nil
+	}); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if res := doRequest(t, server, http.MethodGet, partial+"?token="+token, nil); res.StatusCode != http.StatusOK {
+		t.Fatalf("expected the stored hash to admit the display, got %d", res.StatusCode)
+	}
+	current, _ := srv.store.GetGame(game.ID)
+	if _, ok := srv.snapshotForPlayer(current, 1)["display_token"]; ok {
+		t.Fatal("expected no display token in the host snapshot once only its hash is known")
+	}
+}
+
 func readWSMessageType(t *testing.T, conn *websocket.Conn, timeout time.Duration) string {
 	t.Helper()
 	_ = conn.SetReadDeadline(time.Now().Add(timeout))
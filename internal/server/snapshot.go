@@ -71,7 +71,7 @@ func snapshotWithConfig(game *Game, cfg config.Config) map[string]any {
 			if drawingIndex >= 0 && drawingIndex < len(round.Drawings) {
 				drawing := round.Drawings[drawingIndex]
 				guessFocus["drawing_owner"] = drawing.PlayerID
-				guessFocus["drawing_image"] = encodeImageData(drawing.ImageData)
+				guessFocus["drawing_image"] = imageSource(drawing.ImageData, drawing.ImageHash)
 			}
 		}
 		if voterID, drawingIndex, ok := firstAssignmentByOrder(game, voteMap); ok {
@@ -88,11 +88,12 @@ func snapshotWithConfig(game *Game, cfg config.Config) map[string]any {
 			if drawingIndex >= 0 && drawingIndex < len(round.Drawings) {
 				drawing := round.Drawings[drawingIndex]
 				voteFocus["drawing_owner"] = drawing.PlayerID
-				voteFocus["drawing_image"] = encodeImageData(drawing.ImageData)
+				voteFocus["drawing_image"] = imageSource(drawing.ImageData, drawing.ImageHash)
 				voteFocus["options"] = voteOptionsForPlayerPayload(game, voteOptionEntries(round, drawingIndex), 0)
 			}
 		}
 	}
+	chainStep, chainSteps, chainSubmitted, chainRequired := chainProgress(game)
 	phaseDuration := phaseDurationSeconds(cfg, game)
 	phaseEndsAt := ""
 	if !game.PhaseStartedAt.IsZero() && phaseDuration > 0 {
@@ -105,6 +106,7 @@ func snapshotWithConfig(game *Game, cfg config.Config) map[string]any {
 		"phase":                 game.Phase,
 		"paused":                game.Phase == phasePaused,
 		"paused_phase":          game.PausedPhase,
+		"paused_remaining":      pausedRemainingSeconds(game),
 		"phase_started_at":      game.PhaseStartedAt,
 		"phase_duration":        phaseDuration,
 		"phase_ends_at":         phaseEndsAt,
@@ -117,14 +119,28 @@ func snapshotWithConfig(game *Game, cfg config.Config) map[string]any {
 		"max_players":           game.MaxPlayers,
 		"lobby_locked":          game.LobbyLocked,
 		"ruleset":               game.Ruleset,
+		"team_count":            game.TeamCount,
+		"scoring_rules":         scoringRulesPayload(game),
+		"timers":                timerSettingsPayload(cfg, game),
+		"player_teams":          extractPlayerTeams(game),
 		"avatars_enabled":       game.AvatarsEnabled,
 		"audience_enabled":      game.AudienceEnabled,
 		"jokes_enabled":         game.JokesEnabled,
 		"public_replay":         game.PublicReplay,
 		"host_id":               game.HostID,
+		"host_name":             hostName(game),
 		"scores":                scores,
+		"team_scores":           buildTeamScores(game),
 		"results":               buildResults(game),
 		"reveal":                reveal,
+		"chain_step":            chainStep,
+		"chain_steps":           chainSteps,
+		"chain_submitted_count": chainSubmitted,
+		"chain_required_count":  chainRequired,
+		"chain_assignments":     chainAssignmentsPayload(game, currentRound(game)),
+		"chain_reveal":          buildChainReveal(game),
+		"chains":                buildChains(game),
+		"awards":                buildAwards(game),
 		"total_rounds":          game.PromptsPerPlayer,
 		"current_round":         len(game.Rounds),
 		"guess_focus":           guessFocus,
@@ -146,15 +162,30 @@ func snapshotWithConfig(game *Game, cfg config.Config) map[string]any {
 			"guesses":  guessesCount,
 			"votes":    votesCount,
 		},
+		"server_time":    serverTimeMillis(),
 		"can_join":       game.Phase == phaseLobby && !game.LobbyLocked && len(game.Players) < effectiveMaxPlayers(game.MaxPlayers),
 		"audience_count": len(game.Audience),
 	}
 }
 
+// phaseDurationSeconds is the length of the game's current phase under its
+// timer settings, including any time the host has added, or up to its early
+// deadline once one is set.
 func phaseDurationSeconds(cfg config.Config, game *Game) int {
 	if game == nil {
 		return 0
 	}
+	base := basePhaseDurationSeconds(gameTimerConfig(cfg, game), game)
+	if base <= 0 {
+		return 0
+	}
+	if !game.PhaseDeadline.IsZero() {
+		return int(game.PhaseDeadline.Sub(game.PhaseStartedAt).Round(time.Second) / time.Second)
+	}
+	return base + int(game.PhaseExtension/time.Second)
+}
+
+func basePhaseDurationSeconds(cfg config.Config, game *Game) int {
 	switch game.Phase {
 	case phaseDrawings:
 		return cfg.DrawDurationSeconds
@@ -162,6 +193,12 @@ func phaseDurationSeconds(cfg config.Config, game *Game) int {
 		return cfg.GuessDurationSeconds
 	case phaseGuessVotes:
 		return cfg.VoteDurationSeconds
+	case phaseChainDraw:
+		return cfg.DrawDurationSeconds
+	case phaseChainDescribe:
+		return cfg.GuessDurationSeconds
+	case phaseChainReveal:
+		return cfg.RevealDurationSeconds
 	case phaseResults:
 		round := currentRound(game)
 		if round == nil {
@@ -178,6 +215,8 @@ func phaseDurationSeconds(cfg config.Config, game *Game) int {
 			if cfg.RevealJokeSeconds > 0 {
 				return cfg.RevealJokeSeconds
 			}
+		case revealStageAwards:
+			return cfg.RevealDurationSeconds
 		default:
 			if cfg.RevealGuessesSeconds > 0 {
 				return cfg.RevealGuessesSeconds
@@ -208,10 +247,10 @@ func extractPlayerColors(players []Player) map[int]string {
 func extractPlayerAvatars(players []Player) map[int]string {
 	avatars := make(map[int]string, len(players))
 	for _, player := range players {
-		if len(player.Avatar) == 0 {
+		if len(player.Avatar) == 0 && player.AvatarHash == "" {
 			continue
 		}
-		avatars[player.ID] = encodeImageData(player.Avatar)
+		avatars[player.ID] = imageSource(player.Avatar, player.AvatarHash)
 	}
 	return avatars
 }
@@ -226,6 +265,14 @@ func extractPlayerAvatarLocks(players []Player) map[int]bool {
 	return locks
 }
 
+func extractPlayerTeams(game *Game) map[int]int {
+	teams := playerTeams(game)
+	if teams == nil {
+		return map[int]int{}
+	}
+	return teams
+}
+
 func extractPlayerIDs(players []Player) []int {
 	list := make([]int, 0, len(players))
 	for _, player := range players {
@@ -266,6 +313,7 @@ func buildResults(game *Game) []map[string]any {
 				"player_id":   guess.PlayerID,
 				"player_name": playerNames[guess.PlayerID],
 				"text":        guess.Text,
+				"likes":       guessLikeCount(round, drawingIndex, guess.PlayerID),
 			})
 		}
 		votes := make([]map[string]any, 0)
@@ -287,7 +335,8 @@ func buildResults(game *Game) []map[string]any {
 			"drawing_index":      drawingIndex,
 			"drawing_owner":      drawing.PlayerID,
 			"drawing_owner_name": playerNames[drawing.PlayerID],
-			"drawing_image":      encodeImageData(drawing.ImageData),
+			"drawing_image":      imageSource(drawing.ImageData, drawing.ImageHash),
+			"timelapse_url":      timeLapseSource(game, round, drawingIndex),
 			"prompt":             drawing.Prompt,
 			"joke":               joke,
 			"joke_audio":         jokeAudio,
@@ -301,23 +350,43 @@ func buildResults(game *Game) []map[string]any {
 	return results
 }
 
+func guessLikeCount(round *RoundState, drawingIndex, ownerID int) int {
+	count := 0
+	for _, like := range round.Likes {
+		if like.DrawingIndex == drawingIndex && like.GuessOwnerID == ownerID {
+			count++
+		}
+	}
+	return count
+}
+
 func buildScores(game *Game) []map[string]any {
 	if game == nil {
 		return nil
 	}
 	names := buildNameMap(game.Players)
-	scores := domain.Scores(domainStateForScores(game))
+	teams := playerTeams(game)
+	scores := domain.Scores(domainStateForScores(game)).Players
 	results := make([]map[string]any, 0, len(scores))
 	for _, score := range scores {
-		results = append(results, map[string]any{
-			"player_id": score.PlayerID, "player_name": names[score.PlayerID], "score": score.Points,
-		})
+		entry := map[string]any{
+			"player_id": score.PlayerID, "player_name": names[score.PlayerID], "score": score.Points, "likes": score.Likes,
+		}
+		if teams != nil {
+			entry["team"] = teams[score.PlayerID]
+		}
+		results = append(results, entry)
 	}
 	return results
 }
 
 func domainStateForScores(source *Game) domain.State {
-	state := domain.State{Ruleset: domain.Ruleset(source.Ruleset)}
+	state := domain.State{
+		Ruleset:     domain.Ruleset(source.Ruleset),
+		Teams:       playerTeams(source),
+		Rules:       scoringRules(source),
+		TotalRounds: source.PromptsPerPlayer,
+	}
 	for _, player := range source.Players {
 		state.Players = append(state.Players, player.ID)
 	}
@@ -332,6 +401,12 @@ func domainStateForScores(source *Game) domain.State {
 		for _, vote := range sourceRound.Votes {
 			round.Votes = append(round.Votes, domain.Vote{PlayerID: vote.PlayerID, DrawingIndex: vote.DrawingIndex, ChoiceText: vote.ChoiceText, Correct: vote.ChoiceType == voteChoicePrompt})
 		}
+		for _, like := range sourceRound.Likes {
+			round.Likes = append(round.Likes, domain.Like{PlayerID: like.PlayerID, DrawingIndex: like.DrawingIndex, OwnerID: like.GuessOwnerID})
+		}
+		for _, guess := range sourceRound.TitleGuesses {
+			round.TitleGuesses = append(round.TitleGuesses, domain.TitleGuess{PlayerID: guess.PlayerID, DrawingIndex: guess.DrawingIndex})
+		}
 		state.Rounds = append(state.Rounds, round)
 	}
 	return state
@@ -364,7 +439,8 @@ func buildReveal(game *Game) map[string]any {
 		"drawing_index":      round.RevealIndex,
 		"drawing_owner":      drawing.PlayerID,
 		"drawing_owner_name": playerNames[drawing.PlayerID],
-		"drawing_image":      encodeImageData(drawing.ImageData),
+		"drawing_image":      imageSource(drawing.ImageData, drawing.ImageHash),
+		"timelapse_url":      timeLapseSource(game, round, round.RevealIndex),
 		"stage":              round.RevealStage,
 	}
 	if round.RevealStage == revealStageGuesses {
@@ -532,6 +608,8 @@ func drawingScoreDeltas(game *Game, round *RoundState, drawingIndex int, playerN
 		Delta    int
 		Reasons  []string
 	}
+	rules := scoringRules(game)
+	multiplier := scoringMultiplier(game, round)
 	entries := make(map[int]*scoreDelta)
 	addDelta := func(playerID int, delta int, reason string) {
 		if playerID <= 0 || delta == 0 {
@@ -542,9 +620,9 @@ func drawingScoreDeltas(game *Game, round *RoundState, drawingIndex int, playerN
 			entry = &scoreDelta{PlayerID: playerID}
 			entries[playerID] = entry
 		}
-		entry.Delta += delta
+		entry.Delta += delta * multiplier
 		if reason != "" {
-			entry.Reasons = append(entry.Reasons, reason)
+			entry.Reasons = append(entry.Reasons, fmt.Sprintf("%s (+%d)", reason, delta))
 		}
 	}
 
@@ -555,7 +633,7 @@ func drawingScoreDeltas(game *Game, round *RoundState, drawingIndex int, playerN
 			continue
 		}
 		if vote.ChoiceType == voteChoicePrompt {
-			addDelta(vote.PlayerID, 1000, "Correct vote")
+			addDelta(vote.PlayerID, rules.CorrectGuess, "Correct vote")
 			correctVotes++
 			continue
 		}
@@ -567,18 +645,40 @@ func drawingScoreDeltas(game *Game, round *RoundState, drawingIndex int, playerN
 				if fooledName == "" {
 					fooledName = fmt.Sprintf("Player %d", vote.PlayerID)
 				}
-				addDelta(ownerID, 500, "Fooled "+fooledName)
+				addDelta(ownerID, rules.FooledPlayer, "Fooled "+fooledName)
 			}
 		}
 	}
 
 	drawingOwner := round.Drawings[drawingIndex].PlayerID
-	if game.Ruleset == rulesetDrawful {
-		addDelta(drawingOwner, 500*correctVotes, fmt.Sprintf("%d players found the real title", correctVotes))
+	if game.Ruleset == rulesetDrawful || teamsEnabled(game) {
+		addDelta(drawingOwner, rules.ArtistBonus*correctVotes, fmt.Sprintf("%d players found the real title", correctVotes))
 	} else if fooledVotes == 0 {
-		addDelta(drawingOwner, 1000, "No one picked a lie")
+		addDelta(drawingOwner, rules.CorrectGuess, "No one picked a lie")
 	} else {
-		addDelta(drawingOwner, 500*fooledVotes, fmt.Sprintf("%d players picked lies", fooledVotes))
+		addDelta(drawingOwner, rules.ArtistBonus*fooledVotes, fmt.Sprintf("%d players picked lies", fooledVotes))
+	}
+	if titleGuessAllowed(game) {
+		for _, guess := range round.TitleGuesses {
+			if guess.DrawingIndex == drawingIndex {
+				addDelta(guess.PlayerID, rules.TitleGuessBonus, "Guessed the title")
+			}
+		}
+	}
+	for _, like := range round.Likes {
+		if like.DrawingIndex != drawingIndex {
+			continue
+		}
+		likerName := playerNames[like.PlayerID]
+		if likerName == "" {
+			likerName = fmt.Sprintf("Player %d", like.PlayerID)
+		}
+		addDelta(like.GuessOwnerID, rules.LikePoints, "Liked by "+likerName)
+	}
+	if multiplier > 1 {
+		for _, entry := range entries {
+			entry.Reasons = append(entry.Reasons, fmt.Sprintf("Final round x%d", multiplier))
+		}
 	}
 
 	ordered := make([]*scoreDelta, 0, len(entries))
@@ -599,6 +699,7 @@ func drawingScoreDeltas(game *Game, round *RoundState, drawingIndex int, playerN
 			"player_name": playerNames[entry.PlayerID],
 			"delta":       entry.Delta,
 			"reasons":     entry.Reasons,
+			"multiplier":  multiplier,
 		})
 	}
 	return result
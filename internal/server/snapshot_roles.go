@@ -9,6 +9,7 @@ func (s *Server) snapshotForPublic(game *Game) map[string]any {
 	delete(snapshot, "vote_focus")
 	delete(snapshot, "guess_assignments")
 	delete(snapshot, "vote_assignments")
+	delete(snapshot, "chain_assignments")
 	delete(snapshot, "guess_remaining")
 	delete(snapshot, "vote_remaining")
 	if game.Phase != phaseComplete {
@@ -21,6 +22,7 @@ func (s *Server) snapshotForPlayer(game *Game, playerID int) map[string]any {
 	snapshot := s.snapshot(game)
 	snapshot["guess_assignments"] = filterAssignments(snapshot["guess_assignments"], playerID)
 	snapshot["vote_assignments"] = filterAssignments(snapshot["vote_assignments"], playerID)
+	snapshot["chain_assignments"] = filterAssignments(snapshot["chain_assignments"], playerID)
 	delete(snapshot, "guess_focus")
 	delete(snapshot, "vote_focus")
 	delete(snapshot, "guess_remaining")
@@ -28,6 +30,9 @@ func (s *Server) snapshotForPlayer(game *Game, playerID int) map[string]any {
 	if game.Phase != phaseComplete {
 		delete(snapshot, "results")
 	}
+	if playerID > 0 && playerID == game.HostID && game.DisplayToken != "" {
+		snapshot["display_token"] = game.DisplayToken
+	}
 	return snapshot
 }
 
@@ -52,6 +57,7 @@ func filterAssignments(raw any, playerID int) []map[string]any {
 }
 
 type stateChangedMessage struct {
-	Type    string `json:"type"`
-	Version int64  `json:"version,omitempty"`
+	Type       string `json:"type"`
+	Version    int64  `json:"version,omitempty"`
+	ServerTime int64  `json:"server_time,omitempty"`
 }
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// sseKeepAlive is how often an idle event stream gets a comment, so
+	// proxies that close quiet connections leave it open.
+	sseKeepAlive = 15 * time.Second
+	// sseRetryMillis is the reconnect delay suggested to the browser.
+	sseRetryMillis = 2000
+)
+
+var errSSEClosed = errors.New("stream closed by server")
+
+// newSSEClient returns a hub client for a server-sent event stream. It has
+// no connection of its own; handleSSE drains its queue into the response.
+func newSSEClient(timeouts wsTimeouts) *wsClient {
+	sseOpenClients.Add(1)
+	return &wsClient{
+		send:     make(chan []byte, timeouts.queue),
+		closed:   make(chan struct{}),
+		timeouts: timeouts,
+	}
+}
+
+// handleSSE streams the same messages a game websocket receives, for
+// networks that block websocket upgrades. Each event carrying a game version
+// uses it as its id, so a browser that reconnects with Last-Event-ID at the
+// current version is not told to refetch.
+func (s *Server) handleSSE(c *gin.Context) {
+	game, query, role, ok := s.gameStreamRequest(c)
+	if !ok {
+		return
+	}
+	lastVersion, _ := strconv.ParseInt(strings.TrimSpace(c.GetHeader("Last-Event-ID")), 10, 64)
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	log.Printf("sse connected game_id=%s remote=%s", game.ID, c.Request.RemoteAddr)
+	client := newSSEClient(s.ws.timeouts)
+	client.playerID = query.PlayerID
+	// Event streams cannot ask for a resync, so they always get notices.
+	query.Deltas = false
+	s.subscribeGameStream(game, client, query, role, lastVersion)
+	err := writeSSE(c, client)
+	log.Printf("sse disconnected game_id=%s player_id=%d error=%v", game.ID, client.playerID, err)
+	s.unsubscribeGameStream(game.ID, client, role)
+}
+
+func writeSSE(c *gin.Context, client *wsClient) error {
+	controller := http.NewResponseController(c.Writer)
+	write := func(frame string) error {
+		_ = controller.SetWriteDeadline(time.Now().Add(client.timeouts.writeWait))
+		if _, err := io.WriteString(c.Writer, frame); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+	if err := write("retry: " + strconv.Itoa(sseRetryMillis) + "\n\n"); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case data := <-client.send:
+			if err := write(sseEvent(data)); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := write(": keepalive\n\n"); err != nil {
+				return err
+			}
+		case <-client.Done():
+			return errSSEClosed
+		case <-c.Request.Context().Done():
+			return c.Request.Context().Err()
+		}
+	}
+}
+
+// sseEvent frames one queued message. Messages are single-line JSON.
+func sseEvent(data []byte) string {
+	var b strings.Builder
+	var versioned struct {
+		Version int64 `json:"version"`
+	}
+	if json.Unmarshal(data, &versioned) == nil && versioned.Version > 0 {
+		b.WriteString("id: " + strconv.FormatInt(versioned.Version, 10) + "\n")
+	}
+	b.WriteString("data: ")
+	b.Write(data)
+	b.WriteString("\n\n")
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+)
+
+type sseEventFrame struct {
+	id   string
+	data string
+}
+
+func openSSE(t *testing.T, url, lastEventID string) *bufio.Reader {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open %s: %v", url, err)
+	}
+	t.Cleanup(func() { res.Body.Close() })
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %d %q", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+	return bufio.NewReader(res.Body)
+}
+
+func readSSEEvent(t *testing.T, reader *bufio.Reader) sseEventFrame {
+	t.Helper()
+	var event sseEventFrame
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && event.data != "":
+			return event
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestSSEStreamsGameUpdatesAndResumes(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := srv.store.CreateGame(1)
+	server := httptest.NewServer(srv.Handler())
+	// Registered first so it runs after the streams are cancelled.
+	t.Cleanup(server.Close)
+	url := server.URL + "/sse/games/" + game.ID
+	addPlayer := func() *Game {
+		updated, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+			g.Players = append(g.Players, Player{ID: len(g.Players) + 1, Name: "P" + strconv.Itoa(len(g.Players)+1)})
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		srv.broadcastLocalGameUpdate(updated)
+		return updated
+	}
+
+	game = addPlayer()
+	stream := openSSE(t, url, "")
+	first := readSSEEvent(t, stream)
+	if first.id != strconv.FormatInt(game.Version, 10) || !strings.Contains(first.data, `"state_changed"`) {
+		t.Fatalf("expected a state_changed event at version %d, got %+v", game.Version, first)
+	}
+	updated := addPlayer()
+	next := readSSEEvent(t, stream)
+	if next.id != strconv.FormatInt(updated.Version, 10) {
+		t.Fatalf("expected the update as event %d, got %+v", updated.Version, next)
+	}
+
+	// A client that has seen the current version only gets later events.
+	resumed := openSSE(t, url, next.id)
+	updated = addPlayer()
+	if event := readSSEEvent(t, resumed); event.id != strconv.FormatInt(updated.Version, 10) {
+		t.Fatalf("expected the resumed stream to start at version %d, got %+v", updated.Version, event)
+	}
+
+	display := openSSE(t, url+"?role=display&display_token="+game.DisplayToken, "")
+	var message wsHTMLMessage
+	if err := json.Unmarshal([]byte(readSSEEvent(t, display).data), &message); err != nil || message.Target != "#displayContent" {
+		t.Fatalf("expected the display fragment, got %+v (%v)", message, err)
+	}
+
+	res, err := http.Get(server.URL + "/sse/games/" + game.ID + "?player_id=1&auth_token=wrong")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a bad token to be refused, got %d", res.StatusCode)
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	domain "picture-this/internal/game"
 )
 
 type Store struct {
@@ -17,6 +20,19 @@ type Store struct {
 	nextPlayerID int
 	games        map[string]*Game
 	actors       map[string]*gameActor
+	journal      func(*Game, []GameEvent) error
+	transact     func(*Game, func() error) error
+	load         func(string) (*Game, bool)
+	unloaded     atomic.Int64
+}
+
+// ResidentGame describes a game held in memory, for the janitor and metrics.
+type ResidentGame struct {
+	ID             string
+	DBID           uint
+	Phase          string
+	PhaseStartedAt time.Time
+	IdleSince      time.Time
 }
 
 func NewStore() *Store {
@@ -28,6 +44,31 @@ func NewStore() *Store {
 	}
 }
 
+// SetJournal installs the sink for typed game events. It applies to games
+// created or restored afterwards.
+func (s *Store) SetJournal(journal func(*Game, []GameEvent) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.journal = journal
+}
+
+// SetTransaction installs the wrapper that each command's persistence and
+// journal writes run inside. Like SetJournal, it applies to games created or
+// restored afterwards.
+func (s *Store) SetTransaction(transact func(*Game, func() error) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transact = transact
+}
+
+// SetLoader installs the fallback that brings an unloaded game back into
+// memory when a command targets it.
+func (s *Store) SetLoader(load func(id string) (*Game, bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load = load
+}
+
 func (s *Store) CreateGame(promptsPerPlayer int) *Game {
 	return s.CreateGameWithLimits(promptsPerPlayer, 3, 8)
 }
@@ -67,9 +108,11 @@ func (s *Store) CreateGameWithLimits(promptsPerPlayer int, minPlayers int, maxPl
 		PlayerAuthTokens: make(map[int]string),
 		PromptsPerPlayer: promptsPerPlayer,
 		Ruleset:          rulesetDrawful,
+		ScoringRules:     domain.DefaultScoringRules(),
 	}
+	issueDisplayToken(game)
 	s.games[id] = game
-	s.actors[id] = newGameActor(game)
+	s.actors[id] = newGameActor(game, s.journal, s.transact, false)
 	return game
 }
 
@@ -84,10 +127,50 @@ func (s *Store) GetGame(id string) (*Game, bool) {
 }
 
 func (s *Store) DeleteGame(id string) {
+	s.removeGame(id)
+}
+
+// UnloadGame drops a game from memory and stops its actor. The game stays in
+// the database and can be reloaded later.
+func (s *Store) UnloadGame(id string) bool {
+	if !s.removeGame(id) {
+		return false
+	}
+	s.unloaded.Add(1)
+	return true
+}
+
+func (s *Store) removeGame(id string) bool {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	actor, ok := s.actors[id]
 	delete(s.games, id)
 	delete(s.actors, id)
+	s.mu.Unlock()
+	if ok && actor != nil {
+		actor.shutdown()
+	}
+	return ok
+}
+
+// UnloadedCount reports how many games UnloadGame has removed.
+func (s *Store) UnloadedCount() int64 {
+	return s.unloaded.Load()
+}
+
+// ResidentGames summarizes every game in memory without cloning them.
+func (s *Store) ResidentGames() []ResidentGame {
+	s.mu.Lock()
+	list := make([]ResidentGame, 0, len(s.actors))
+	for id, actor := range s.actors {
+		game := actor.resident()
+		game.ID = id
+		list = append(list, game)
+	}
+	s.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		return gameSortKey(list[i].ID) < gameSortKey(list[j].ID)
+	})
+	return list
 }
 
 func (s *Store) UpdateGame(id string, update func(game *Game) error) (*Game, error) {
@@ -102,7 +185,16 @@ func (s *Store) updateGame(id string, update, persist func(game *Game) error) (*
 	s.mu.Lock()
 	_, ok := s.games[id]
 	actor := s.actors[id]
+	load := s.load
 	s.mu.Unlock()
+	if !ok && load != nil {
+		if game, loaded := load(id); loaded {
+			s.mu.Lock()
+			_, ok = s.games[game.ID]
+			actor = s.actors[game.ID]
+			s.mu.Unlock()
+		}
+	}
 	if !ok {
 		return nil, errors.New("game not found")
 	}
@@ -162,10 +254,12 @@ func (s *Store) UpdateGameID(game *Game, newID string) {
 }
 
 func (s *Store) AddPlayer(gameIDOrCode, name string, avatar []byte, recoveryHash string) (*Game, *Player, error) {
-	return s.AddPlayerDurably(gameIDOrCode, name, avatar, recoveryHash, nil)
+	return s.AddPlayerDurably(gameIDOrCode, name, avatar, "", recoveryHash, nil)
 }
 
-func (s *Store) AddPlayerDurably(gameIDOrCode, name string, avatar []byte, recoveryHash string, persist func(*Game, *Player) error) (*Game, *Player, error) {
+// AddPlayerDurably adds a player with an avatar given either inline or, when
+// it lives in the blob store, by hash.
+func (s *Store) AddPlayerDurably(gameIDOrCode, name string, avatar []byte, avatarHash string, recoveryHash string, persist func(*Game, *Player) error) (*Game, *Player, error) {
 	s.mu.Lock()
 	_, ok := s.games[gameIDOrCode]
 	actor := s.actors[gameIDOrCode]
@@ -212,11 +306,12 @@ func (s *Store) AddPlayerDurably(gameIDOrCode, name string, avatar []byte, recov
 		reservedPlayerID := s.nextPlayerID
 		s.nextPlayerID++
 		s.mu.Unlock()
-		player := Player{ID: reservedPlayerID, Name: name, Avatar: avatar, IsHost: len(game.Players) == 0, Color: pickPlayerColor(len(game.Players)), Claimed: true, RecoveryHash: recoveryHash}
+		player := Player{ID: reservedPlayerID, Name: name, Avatar: avatar, AvatarHash: avatarHash, IsHost: len(game.Players) == 0, Color: pickPlayerColor(len(game.Players)), Claimed: true, RecoveryHash: recoveryHash}
 		game.Players = append(game.Players, player)
 		if player.IsHost {
 			game.HostID = player.ID
 		}
+		applyTeamBalance(game)
 		ensurePlayerAuthToken(game, player.ID)
 		joined = &game.Players[len(game.Players)-1]
 		return nil
@@ -251,6 +346,13 @@ func (s *Store) AddPlayerDurably(gameIDOrCode, name string, avatar []byte, recov
 }
 
 func (s *Store) RestoreGame(game *Game) error {
+	return s.restoreGame(game, true)
+}
+
+// restoreGame registers a rebuilt game. journaled is false when the game was
+// rebuilt from row tables, so its first command records a full baseline in
+// the event stream.
+func (s *Store) restoreGame(game *Game, journaled bool) error {
 	if game == nil {
 		return errors.New("game is nil")
 	}
@@ -265,7 +367,7 @@ func (s *Store) RestoreGame(game *Game) error {
 		}
 	}
 	s.games[game.ID] = game
-	s.actors[game.ID] = newGameActor(game)
+	s.actors[game.ID] = newGameActor(game, s.journal, s.transact, journaled)
 	if id := gameSortKey(game.ID); id >= s.nextID {
 		s.nextID = id + 1
 	}
@@ -8,7 +8,7 @@ import (
 func TestAddPlayerDurablyRollsBackPersistenceFailure(t *testing.T) {
 	store := NewStore()
 	game := store.CreateGame(0)
-	_, _, err := store.AddPlayerDurably(game.ID, "Ada", nil, "hash", func(*Game, *Player) error {
+	_, _, err := store.AddPlayerDurably(game.ID, "Ada", nil, "", "hash", func(*Game, *Player) error {
 		return errors.New("database unavailable")
 	})
 	if err == nil {
@@ -60,3 +60,29 @@ func TestAddPlayerPausedRejectsNameTakeover(t *testing.T) {
 		t.Fatalf("expected paused error, got %v", err)
 	}
 }
+
+func TestUnloadGameStopsActor(t *testing.T) {
+	store := NewStore()
+	game := store.CreateGame(2)
+	store.mu.Lock()
+	actor := store.actors[game.ID]
+	store.mu.Unlock()
+
+	if !store.UnloadGame(game.ID) {
+		t.Fatal("expected game to unload")
+	}
+	select {
+	case <-actor.done:
+	default:
+		t.Fatal("expected actor goroutine to exit")
+	}
+	if err := actor.execute(func(*Game) error { return nil }); !errors.Is(err, errGameUnloaded) {
+		t.Fatalf("expected unloaded error, got %v", err)
+	}
+	if _, ok := store.GetGame(game.ID); ok {
+		t.Fatal("unloaded game is still resident")
+	}
+	if store.UnloadGame(game.ID) || store.UnloadedCount() != 1 {
+		t.Fatalf("expected a single unload, got %d", store.UnloadedCount())
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import domain "picture-this/internal/game"
+
+func teamsEnabled(game *Game) bool {
+	return game != nil && game.Ruleset == rulesetTeams
+}
+
+func playerTeams(game *Game) map[int]int {
+	if !teamsEnabled(game) {
+		return nil
+	}
+	teams := make(map[int]int, len(game.Players))
+	for _, player := range game.Players {
+		teams[player.ID] = player.Team
+	}
+	return teams
+}
+
+func sameTeam(game *Game, a, b int) bool {
+	return domain.SameTeam(playerTeams(game), a, b)
+}
+
+// applyTeamBalance reassigns lobby teams after players join or leave and
+// clears them when the game is not using the team ruleset.
+func applyTeamBalance(game *Game) {
+	if game == nil {
+		return
+	}
+	if !teamsEnabled(game) {
+		game.TeamCount = 0
+		for i := range game.Players {
+			game.Players[i].Team = 0
+		}
+		return
+	}
+	if game.TeamCount < 2 || game.TeamCount > domain.MaxTeams {
+		game.TeamCount = domain.DefaultTeamCount
+	}
+	ids := extractPlayerIDs(game.Players)
+	teams := domain.BalanceTeams(ids, playerTeams(game), game.TeamCount)
+	for i := range game.Players {
+		game.Players[i].Team = teams[game.Players[i].ID]
+	}
+}
+
+// guessSatisfiedForPlayer reports whether a player still owes a decoy for a
+// drawing. In team games the artist's teammates pool a single decoy, so the
+// first teammate to submit covers the rest of the team.
+func guessSatisfiedForPlayer(game *Game, round *RoundState, drawingIndex int, playerID int) bool {
+	if hasGuessForPlayer(round, drawingIndex, playerID) {
+		return true
+	}
+	if round == nil || drawingIndex < 0 || drawingIndex >= len(round.Drawings) {
+		return false
+	}
+	artistID := round.Drawings[drawingIndex].PlayerID
+	if !sameTeam(game, playerID, artistID) {
+		return false
+	}
+	for _, guess := range round.Guesses {
+		if guess.DrawingIndex == drawingIndex && sameTeam(game, guess.PlayerID, artistID) {
+			return true
+		}
+	}
+	return false
+}
+
+func buildTeamScores(game *Game) []map[string]any {
+	if !teamsEnabled(game) {
+		return nil
+	}
+	board := domain.Scores(domainStateForScores(game))
+	names := buildNameMap(game.Players)
+	members := map[int][]string{}
+	// Players are listed in join order so the scoreboard is stable.
+	for _, player := range game.Players {
+		members[player.Team] = append(members[player.Team], names[player.ID])
+	}
+	results := make([]map[string]any, 0, len(board.Teams))
+	for _, team := range board.Teams {
+		results = append(results, map[string]any{
+			"team": team.Team, "score": team.Points, "players": members[team.Team],
+		})
+	}
+	return results
+}
@@ -0,0 +1,132 @@
+package server
+
+import "testing"
+
+func teamGame() *Game {
+	return &Game{Ruleset: rulesetTeams, TeamCount: 2, Players: []Player{
+		{ID: 1, Name: "Ada", Team: 1}, {ID: 2, Name: "Ben", Team: 1}, {ID: 3, Name: "Cam", Team: 1},
+		{ID: 4, Name: "Dee", Team: 2}, {ID: 5, Name: "Eve", Team: 2},
+	}, Rounds: []RoundState{{
+		Number:   1,
+		Drawings: []DrawingEntry{{PlayerID: 1, Prompt: "real"}},
+	}}}
+}
+
+func TestTeamsBalanceOnJoinAndKick(t *testing.T) {
+	store := NewStore()
+	game := store.CreateGame(1)
+	if _, err := store.UpdateGame(game.ID, func(g *Game) error {
+		g.Ruleset = rulesetTeams
+		return nil
+	}); err != nil {
+		t.Fatalf("set ruleset: %v", err)
+	}
+	for _, name := range []string{"Ada", "Ben", "Cam", "Dee"} {
+		if _, _, err := store.AddPlayer(game.ID, name, nil, ""); err != nil {
+			t.Fatalf("add %s: %v", name, err)
+		}
+	}
+	game, _ = store.GetGame(game.ID)
+	if game.TeamCount != 2 {
+		t.Fatalf("expected default team count, got %d", game.TeamCount)
+	}
+	sizes := map[int]int{}
+	for _, player := range game.Players {
+		sizes[player.Team]++
+	}
+	if sizes[1] != 2 || sizes[2] != 2 {
+		t.Fatalf("unbalanced teams: %v", sizes)
+	}
+
+	game, err := store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = g.Players[:2]
+		applyTeamBalance(g)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("kick: %v", err)
+	}
+	if game.Players[0].Team == game.Players[1].Team {
+		t.Fatalf("expected remaining players on different teams: %+v", game.Players)
+	}
+}
+
+func TestTeammatesPoolDecoyOnTeammateDrawing(t *testing.T) {
+	game := teamGame()
+	round := &game.Rounds[0]
+	if got := len(pendingGuessersForIndex(game, round, 0)); got != 4 {
+		t.Fatalf("expected 4 pending guessers, got %d", got)
+	}
+	round.Guesses = append(round.Guesses, GuessEntry{PlayerID: 2, DrawingIndex: 0, Text: "team lie"})
+	pending := pendingGuessersForIndex(game, round, 0)
+	if len(pending) != 2 || pending[0] != 4 || pending[1] != 5 {
+		t.Fatalf("expected only the other team to owe decoys, got %v", pending)
+	}
+	if _, ok := nextGuessAssignment(game, round, 3); ok {
+		t.Fatal("teammate should not be assigned after the pooled decoy")
+	}
+
+	round.Guesses = nil
+	filled := autoFillMissingGuesses(game)
+	if len(filled) != 3 {
+		t.Fatalf("expected one pooled auto decoy plus two for the other team, got %d", len(filled))
+	}
+}
+
+func TestTeamVoteOptionsMarkTeamLies(t *testing.T) {
+	game := teamGame()
+	round := &game.Rounds[0]
+	round.Guesses = []GuessEntry{
+		{PlayerID: 2, DrawingIndex: 0, Text: "other team lie"},
+		{PlayerID: 4, DrawingIndex: 0, Text: "teammate lie"},
+	}
+	for _, option := range voteOptionsForPlayerPayload(game, voteOptionEntries(round, 0), 5) {
+		own, _ := option["is_own"].(bool)
+		if option["text"] == "teammate lie" && !own {
+			t.Fatalf("expected teammate lie to be blocked: %#v", option)
+		}
+		if option["text"] == "other team lie" && own {
+			t.Fatalf("expected other team's lie to be votable: %#v", option)
+		}
+	}
+}
+
+func TestTeamScoresTotalMembers(t *testing.T) {
+	game := teamGame()
+	round := &game.Rounds[0]
+	round.Guesses = []GuessEntry{{PlayerID: 4, DrawingIndex: 0, Text: "rival lie"}}
+	round.Votes = []VoteEntry{
+		{PlayerID: 2, DrawingIndex: 0, ChoiceText: "real", ChoiceType: voteChoicePrompt},
+		{PlayerID: 3, DrawingIndex: 0, ChoiceText: "rival lie", ChoiceType: voteChoiceGuess},
+		{PlayerID: 5, DrawingIndex: 0, ChoiceText: "real", ChoiceType: voteChoicePrompt},
+	}
+	got := map[int]int{}
+	for _, entry := range buildTeamScores(game) {
+		got[entry["team"].(int)] = entry["score"].(int)
+	}
+	// Team 1: Ben's correct vote plus 500 per correct vote for Ada's drawing.
+	// Team 2: Eve's correct vote plus Dee fooling Cam.
+	if got[1] != 2000 || got[2] != 1500 {
+		t.Fatalf("unexpected team scores: %v", got)
+	}
+}
+
+func TestSettingsRejectSingleTeam(t *testing.T) {
+	for _, tc := range []struct {
+		teams int
+		want  string
+	}{
+		{0, ""},
+		{1, "team count must be at least 2"},
+		{4, ""},
+		{5, "team count exceeds maximum"},
+	} {
+		got := ""
+		if err := settingsBinding.validate(&settingsRequest{PlayerID: 1, TeamCount: tc.teams}); err != nil {
+			got = err.Error()
+		}
+		if got != tc.want {
+			t.Fatalf("team_count=%d: expected %q, got %q", tc.teams, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	domain "picture-this/internal/game"
+)
+
+const noDescriptionText = "No description"
+
+// telephonePhaseTransitions drives the telephone ruleset: chains alternate
+// between draw and describe steps until every player has added a link to every
+// chain, then each chain is revealed in turn.
+var telephonePhaseTransitions = map[string]phaseTransition{
+	phaseLobby: {
+		advance: func(s *Server, game *Game, mode transitionMode, at time.Time) (string, error) {
+			if mode != transitionPreview && len(game.Rounds) == 0 {
+				game.Rounds = append(game.Rounds, RoundState{Number: 1})
+			}
+			applyPhase(game, phaseChainDraw, mode, at)
+			return phaseChainDraw, nil
+		},
+	},
+	phaseChainDraw:     {advance: advanceChainStep},
+	phaseChainDescribe: {advance: advanceChainStep},
+	phaseChainReveal: {
+		advance: func(s *Server, game *Game, mode transitionMode, at time.Time) (string, error) {
+			round := currentRound(game)
+			if round == nil {
+				return "", errors.New("round not started")
+			}
+			if round.RevealIndex+1 >= len(game.Players) {
+				applyPhase(game, phaseComplete, mode, at)
+				return phaseComplete, nil
+			}
+			if mode != transitionPreview {
+				round.RevealIndex++
+			}
+			applyPhase(game, phaseChainReveal, mode, at)
+			return phaseChainReveal, nil
+		},
+	},
+}
+
+func advanceChainStep(s *Server, game *Game, mode transitionMode, at time.Time) (string, error) {
+	round := currentRound(game)
+	if round == nil {
+		return "", errors.New("round not started")
+	}
+	next := round.ChainStep + 1
+	if next >= domain.ChainSteps(len(game.Players)) {
+		if mode != transitionPreview {
+			round.RevealIndex = 0
+		}
+		applyPhase(game, phaseChainReveal, mode, at)
+		return phaseChainReveal, nil
+	}
+	phase := chainPhaseForStep(next)
+	if mode != transitionPreview {
+		round.ChainStep = next
+	}
+	applyPhase(game, phase, mode, at)
+	return phase, nil
+}
+
+func telephoneEnabled(game *Game) bool {
+	return game != nil && game.Ruleset == rulesetTelephone
+}
+
+func isChainStepPhase(phase string) bool {
+	return phase == phaseChainDraw || phase == phaseChainDescribe
+}
+
+func chainPhaseForStep(step int) string {
+	if domain.ChainStepKind(step) == domain.LinkDrawing {
+		return phaseChainDraw
+	}
+	return phaseChainDescribe
+}
+
+func playerSeat(game *Game, playerID int) int {
+	for i, player := range game.Players {
+		if player.ID == playerID {
+			return i
+		}
+	}
+	return -1
+}
+
+func findChainLink(round *RoundState, chainIndex, step int) (ChainLinkEntry, bool) {
+	if round == nil {
+		return ChainLinkEntry{}, false
+	}
+	for _, link := range round.ChainLinks {
+		if link.ChainIndex == chainIndex && link.Step == step {
+			return link, true
+		}
+	}
+	return ChainLinkEntry{}, false
+}
+
+// chainOrigin returns the prompt that opens a chain: the prompt assigned to
+// the player in that seat.
+func chainOrigin(game *Game, round *RoundState, chainIndex int) string {
+	if round == nil || chainIndex < 0 || chainIndex >= len(game.Players) {
+		return ""
+	}
+	for _, prompt := range round.Prompts {
+		if prompt.PlayerID == game.Players[chainIndex].ID {
+			return prompt.Text
+		}
+	}
+	return ""
+}
+
+// chainTask is what a player has to work from for the current step: the
+// original prompt or previous description when drawing, or the previous
+// drawing when describing.
+type chainTask struct {
+	ChainIndex int
+	Step       int
+	Kind       domain.LinkKind
+	Text       string
+	ImageData  []byte
+	ImageHash  string
+}
+
+func chainTaskForPlayer(game *Game, round *RoundState, playerID int) (chainTask, bool) {
+	if !telephoneEnabled(game) || round == nil || !isChainStepPhase(game.Phase) {
+		return chainTask{}, false
+	}
+	seat := playerSeat(game, playerID)
+	if seat < 0 {
+		return chainTask{}, false
+	}
+	step := round.ChainStep
+	chainIndex := domain.ChainForSeat(seat, step, len(game.Players))
+	task := chainTask{ChainIndex: chainIndex, Step: step, Kind: domain.ChainStepKind(step)}
+	if step == 0 {
+		task.Text = chainOrigin(game, round, chainIndex)
+		return task, true
+	}
+	previous, ok := findChainLink(round, chainIndex, step-1)
+	if !ok {
+		return chainTask{}, false
+	}
+	task.Text = previous.Text
+	task.ImageData = previous.ImageData
+	task.ImageHash = previous.ImageHash
+	return task, true
+}
+
+func pendingChainPlayers(game *Game, round *RoundState) []int {
+	if !telephoneEnabled(game) || round == nil || !isChainStepPhase(game.Phase) {
+		return nil
+	}
+	pending := make([]int, 0, len(game.Players))
+	for seat, player := range game.Players {
+		chainIndex := domain.ChainForSeat(seat, round.ChainStep, len(game.Players))
+		if _, ok := findChainLink(round, chainIndex, round.ChainStep); ok {
+			continue
+		}
+		pending = append(pending, player.ID)
+	}
+	return pending
+}
+
+func chainStepComplete(game *Game, round *RoundState) bool {
+	return len(game.Players) > 0 && len(pendingChainPlayers(game, round)) == 0
+}
+
+// autoFillMissingChainLinks closes the current step for players who ran out of
+// time: a blank drawing or a placeholder description keeps every chain intact.
+func autoFillMissingChainLinks(game *Game) []ChainLinkEntry {
+	round := currentRound(game)
+	if round == nil {
+		return nil
+	}
+	filled := make([]ChainLinkEntry, 0)
+	for _, playerID := range pendingChainPlayers(game, round) {
+		task, ok := chainTaskForPlayer(game, round, playerID)
+		if !ok {
+			continue
+		}
+		link := ChainLinkEntry{ChainIndex: task.ChainIndex, Step: task.Step, PlayerID: playerID, Kind: string(task.Kind)}
+		if task.Kind == domain.LinkDescription {
+			link.Text = noDescriptionText
+		}
+		round.ChainLinks = append(round.ChainLinks, link)
+		filled = append(filled, link)
+	}
+	return filled
+}
+
+func chainLinkPayload(game *Game, link ChainLinkEntry) map[string]any {
+	names := buildNameMap(game.Players)
+	return map[string]any{
+		"chain_index":   link.ChainIndex,
+		"step":          link.Step,
+		"player_id":     link.PlayerID,
+		"player_name":   names[link.PlayerID],
+		"kind":          link.Kind,
+		"text":          link.Text,
+		"drawing_image": imageSource(link.ImageData, link.ImageHash),
+	}
+}
+
+func buildChainPayload(game *Game, round *RoundState, chainIndex int) map[string]any {
+	if chainIndex < 0 || chainIndex >= len(game.Players) {
+		return nil
+	}
+	links := make([]map[string]any, 0, len(game.Players))
+	for step := 0; step < domain.ChainSteps(len(game.Players)); step++ {
+		if link, ok := findChainLink(round, chainIndex, step); ok {
+			links = append(links, chainLinkPayload(game, link))
+		}
+	}
+	origin := game.Players[chainIndex]
+	return map[string]any{
+		"chain_index": chainIndex,
+		"owner_id":    origin.ID,
+		"owner_name":  origin.Name,
+		"prompt":      chainOrigin(game, round, chainIndex),
+		"links":       links,
+	}
+}
+
+// buildChainReveal is the chain currently on screen during chain-reveal.
+func buildChainReveal(game *Game) map[string]any {
+	round := currentRound(game)
+	if !telephoneEnabled(game) || round == nil || game.Phase != phaseChainReveal {
+		return nil
+	}
+	return buildChainPayload(game, round, round.RevealIndex)
+}
+
+// buildChains lists every finished chain once the game is complete.
+func buildChains(game *Game) []map[string]any {
+	round := currentRound(game)
+	if !telephoneEnabled(game) || round == nil || game.Phase != phaseComplete {
+		return nil
+	}
+	chains := make([]map[string]any, 0, len(game.Players))
+	for chainIndex := range game.Players {
+		chains = append(chains, buildChainPayload(game, round, chainIndex))
+	}
+	return chains
+}
+
+func chainAssignmentsPayload(game *Game, round *RoundState) []map[string]any {
+	pending := pendingChainPlayers(game, round)
+	if len(pending) == 0 {
+		return nil
+	}
+	payload := make([]map[string]any, 0, len(pending))
+	for _, playerID := range pending {
+		task, ok := chainTaskForPlayer(game, round, playerID)
+		if !ok {
+			continue
+		}
+		payload = append(payload, map[string]any{
+			"player_id":     playerID,
+			"chain_index":   task.ChainIndex,
+			"step":          task.Step,
+			"kind":          string(task.Kind),
+			"text":          task.Text,
+			"drawing_image": imageSource(task.ImageData, task.ImageHash),
+		})
+	}
+	return payload
+}
+
+func chainProgress(game *Game) (step, steps, submitted, required int) {
+	if !telephoneEnabled(game) {
+		return 0, 0, 0, 0
+	}
+	steps = domain.ChainSteps(len(game.Players))
+	round := currentRound(game)
+	if round == nil {
+		return 0, steps, 0, 0
+	}
+	step = round.ChainStep
+	if !isChainStepPhase(game.Phase) {
+		return step, steps, 0, 0
+	}
+	required = len(game.Players)
+	return step, steps, required - len(pendingChainPlayers(game, round)), required
+}
+
+func chainStepLabel(game *Game) string {
+	step, steps, submitted, required := chainProgress(game)
+	return fmt.Sprintf("Step %d of %d (%d/%d submitted)", step+1, steps, submitted, required)
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+)
+
+func telephoneGame() *Game {
+	game := &Game{Ruleset: rulesetTelephone, Phase: phaseChainDraw, Players: []Player{
+		{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}, {ID: 3, Name: "Cam"},
+	}, Rounds: []RoundState{{Number: 1}}}
+	for _, player := range game.Players {
+		game.Rounds[0].Prompts = append(game.Rounds[0].Prompts, PromptEntry{PlayerID: player.ID, Text: "prompt " + player.Name})
+	}
+	return game
+}
+
+func TestTelephoneChainsRunToReveal(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := telephoneGame()
+	for step, phase := range []string{phaseChainDraw, phaseChainDescribe, phaseChainDraw} {
+		round := currentRound(game)
+		if game.Phase != phase || round.ChainStep != step {
+			t.Fatalf("step %d: expected %s, got %s at step %d", step, phase, game.Phase, round.ChainStep)
+		}
+		for _, player := range game.Players {
+			task, ok := chainTaskForPlayer(game, round, player.ID)
+			if !ok {
+				t.Fatalf("step %d: no task for player %d", step, player.ID)
+			}
+			if step == 0 && task.Text != "prompt "+player.Name {
+				t.Fatalf("expected players to start from their own prompt, got %q", task.Text)
+			}
+			if step == 1 && len(task.ImageData) == 0 {
+				t.Fatalf("expected describe step to carry the previous drawing")
+			}
+			link := ChainLinkEntry{ChainIndex: task.ChainIndex, Step: step, PlayerID: player.ID, Kind: string(task.Kind)}
+			if phase == phaseChainDraw {
+				link.ImageData = []byte{byte(player.ID)}
+			} else {
+				link.Text = "described by " + player.Name
+			}
+			round.ChainLinks = append(round.ChainLinks, link)
+		}
+		if !chainStepComplete(game, round) {
+			t.Fatalf("step %d: expected every player to have submitted", step)
+		}
+		if _, err := srv.advancePhase(game, transitionManual, time.Now()); err != nil {
+			t.Fatalf("advance step %d: %v", step, err)
+		}
+	}
+
+	if game.Phase != phaseChainReveal {
+		t.Fatalf("expected chain-reveal phase, got %s", game.Phase)
+	}
+	reveal := buildChainReveal(game)
+	if links, _ := reveal["links"].([]map[string]any); len(links) != len(game.Players) {
+		t.Fatalf("expected a link from every player, got %v", reveal["links"])
+	}
+	for range game.Players {
+		if _, err := srv.advancePhase(game, transitionManual, time.Now()); err != nil {
+			t.Fatalf("advance reveal: %v", err)
+		}
+	}
+	if game.Phase != phaseComplete {
+		t.Fatalf("expected complete phase, got %s", game.Phase)
+	}
+	if chains := buildChains(game); len(chains) != len(game.Players) {
+		t.Fatalf("expected every chain in the final payload, got %d", len(chains))
+	}
+}
+
+func TestTelephoneSnapshotsHideOtherAssignments(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := telephoneGame()
+	if _, ok := srv.snapshotForPublic(game)["chain_assignments"]; ok {
+		t.Fatal("public snapshot should not expose chain assignments")
+	}
+	assignments, _ := srv.snapshotForPlayer(game, 2)["chain_assignments"].([]map[string]any)
+	if len(assignments) != 1 || assignments[0]["player_id"] != 2 {
+		t.Fatalf("expected only the player's own assignment, got %v", assignments)
+	}
+}
+
+func TestTelephoneAutoFillKeepsChainsIntact(t *testing.T) {
+	game := &Game{Ruleset: rulesetTelephone, Phase: phaseChainDescribe, Players: []Player{
+		{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"},
+	}, Rounds: []RoundState{{
+		Number:    1,
+		ChainStep: 1,
+		ChainLinks: []ChainLinkEntry{
+			{ChainIndex: 0, Step: 0, PlayerID: 1, Kind: "drawing", ImageData: []byte{1}},
+			{ChainIndex: 1, Step: 0, PlayerID: 2, Kind: "drawing", ImageData: []byte{2}},
+			{ChainIndex: 0, Step: 1, PlayerID: 2, Kind: "description", Text: "a boat"},
+		},
+	}}}
+	filled := autoFillMissingChainLinks(game)
+	if len(filled) != 1 || filled[0].PlayerID != 1 || filled[0].ChainIndex != 1 || filled[0].Text != noDescriptionText {
+		t.Fatalf("unexpected auto fill: %+v", filled)
+	}
+	if !chainStepComplete(game, &game.Rounds[0]) {
+		t.Fatal("expected the step to be complete after auto fill")
+	}
+}
+
+func TestTelephoneChainLinksReplayFromEvents(t *testing.T) {
+	store, journal := newJournaledStore()
+	game := store.CreateGame(1)
+	steps := []func(*Game) error{
+		func(g *Game) error {
+			g.Ruleset = rulesetTelephone
+			g.Players = []Player{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}}
+			g.Rounds = []RoundState{{Number: 1}}
+			setPhase(g, phaseChainDraw)
+			return nil
+		},
+		func(g *Game) error {
+			round := &g.Rounds[0]
+			round.ChainLinks = append(round.ChainLinks, ChainLinkEntry{ChainIndex: 0, Step: 0, PlayerID: 1, Kind: "drawing", ImageData: []byte{7}})
+			return nil
+		},
+		func(g *Game) error {
+			round := &g.Rounds[0]
+			round.ChainLinks = append(round.ChainLinks, ChainLinkEntry{ChainIndex: 1, Step: 0, PlayerID: 2, Kind: "drawing", ImageData: []byte{8}})
+			round.ChainStep = 1
+			setPhase(g, phaseChainDescribe)
+			return nil
+		},
+	}
+	for i, step := range steps {
+		if _, err := store.UpdateGame(game.ID, step); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	final, _ := store.GetGame(game.ID)
+	got, err := replayGameEvents(journal.events, 0)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayable(got) != replayable(final) {
+		t.Fatalf("replay mismatch:\n got %s\nwant %s", replayable(got), replayable(final))
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"picture-this/internal/config"
+	"picture-this/internal/db"
+	domain "picture-this/internal/game"
+)
+
+const (
+	// addTimeStep is how much one host add-time press extends a phase.
+	addTimeStep = 30
+	// maxPhaseExtensionSeconds caps the time added to a single phase.
+	maxPhaseExtensionSeconds = 300
+)
+
+// gameTimerConfig applies a game's timer settings over the server defaults.
+// Joke narration keeps its configured length, since it follows the audio.
+func gameTimerConfig(cfg config.Config, game *Game) config.Config {
+	timers := game.Timers
+	if timers.DrawSeconds > 0 {
+		cfg.DrawDurationSeconds = timers.DrawSeconds
+	}
+	if timers.GuessSeconds > 0 {
+		cfg.GuessDurationSeconds = timers.GuessSeconds
+	}
+	if timers.VoteSeconds > 0 {
+		cfg.VoteDurationSeconds = timers.VoteSeconds
+	}
+	if timers.RevealSeconds > 0 {
+		cfg.RevealDurationSeconds = timers.RevealSeconds
+		cfg.RevealGuessesSeconds = timers.RevealSeconds
+		cfg.RevealVotesSeconds = timers.RevealSeconds
+	}
+	return cfg
+}
+
+func timerSettingsPayload(cfg config.Config, game *Game) map[string]any {
+	effective := gameTimerConfig(cfg, game)
+	preset := game.Timers.Preset
+	if preset == "" {
+		preset = domain.TimerPresetStandard
+	}
+	return map[string]any{
+		"preset":         preset,
+		"presets":        domain.TimerPresetNames(),
+		"draw_seconds":   effective.DrawDurationSeconds,
+		"guess_seconds":  effective.GuessDurationSeconds,
+		"vote_seconds":   effective.VoteDurationSeconds,
+		"reveal_seconds": effective.RevealDurationSeconds,
+		"added_seconds":  int(game.PhaseExtension.Seconds()),
+	}
+}
+
+func dbTimerSettings(timers domain.TimerSettings) db.TimerSettings {
+	return db.TimerSettings{
+		Preset:        timers.Preset,
+		DrawSeconds:   timers.DrawSeconds,
+		GuessSeconds:  timers.GuessSeconds,
+		VoteSeconds:   timers.VoteSeconds,
+		RevealSeconds: timers.RevealSeconds,
+	}
+}
+
+func timerSettingColumns(timers domain.TimerSettings) map[string]any {
+	record := dbTimerSettings(timers)
+	return map[string]any{
+		"timer_preset":         record.Preset,
+		"timer_draw_seconds":   record.DrawSeconds,
+		"timer_guess_seconds":  record.GuessSeconds,
+		"timer_vote_seconds":   record.VoteSeconds,
+		"timer_reveal_seconds": record.RevealSeconds,
+	}
+}
+
+func timerSettingsFromRecord(record db.TimerSettings) domain.TimerSettings {
+	return domain.TimerSettings{
+		Preset:        record.Preset,
+		DrawSeconds:   record.DrawSeconds,
+		GuessSeconds:  record.GuessSeconds,
+		VoteSeconds:   record.VoteSeconds,
+		RevealSeconds: record.RevealSeconds,
+	}
+}
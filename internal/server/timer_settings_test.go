@@ -0,0 +1,80 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+	domain "picture-this/internal/game"
+)
+
+func TestTimerPresetsAndHostAddTime(t *testing.T) {
+	cfg := config.Default()
+	cfg.GuessDurationSeconds = 60
+	srv := New(nil, cfg)
+	lobby := srv.store.CreateGame(1)
+	if _, err := srv.store.UpdateGame(lobby.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Host"}}
+		g.HostID = 1
+		g.PlayerAuthTokens = map[int]string{1: "host-secret"}
+		return nil
+	}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, err := srv.updateSettings(lobby.ID, settingsRequest{PlayerID: 1, AuthToken: "host-secret", Timers: &domain.TimerSettings{Preset: "glacial"}}); err == nil {
+		t.Fatal("expected an unknown preset to be rejected")
+	}
+	updated, err := srv.updateSettings(lobby.ID, settingsRequest{PlayerID: 1, AuthToken: "host-secret", Timers: &domain.TimerSettings{Preset: domain.TimerPresetSpeed}})
+	if err != nil {
+		t.Fatalf("settings: %v", err)
+	}
+	updated.Phase = phaseGuesses
+	if got := phaseDurationSeconds(srv.cfg, updated); got != 30 {
+		t.Fatalf("expected the speed preset to set a 30s guess phase, got %d", got)
+	}
+	if timers := srv.snapshotForPlayer(updated, 1)["timers"].(map[string]any); timers["preset"] != domain.TimerPresetSpeed || timers["draw_seconds"] != 45 {
+		t.Fatalf("expected the snapshot to carry the speed preset, got %v", timers)
+	}
+
+	game := restoredGuessGame(time.Now().UTC().Add(-45 * time.Second))
+	game.PlayerAuthTokens = map[int]string{1: "host-secret", 2: "ben-secret"}
+	if err := srv.store.RestoreGame(game); err != nil {
+		t.Fatalf("restore game: %v", err)
+	}
+	defer srv.cancelPhaseTimer(game.ID)
+	if _, err := srv.addPhaseTime(game.ID, advanceRequest{PlayerID: 2, AuthToken: "ben-secret"}); err == nil || err.Error() != "only host can perform this action" {
+		t.Fatalf("expected only the host to add time, got %v", err)
+	}
+	before, _ := time.Parse(time.RFC3339, srv.snapshotForPlayer(game, 1)["phase_ends_at"].(string))
+	extended, err := srv.addPhaseTime(game.ID, advanceRequest{PlayerID: 1, AuthToken: "host-secret"})
+	if err != nil {
+		t.Fatalf("add time: %v", err)
+	}
+	after, _ := time.Parse(time.RFC3339, srv.snapshotForPlayer(extended, 1)["phase_ends_at"].(string))
+	if after.Sub(before) != addTimeStep*time.Second {
+		t.Fatalf("expected the deadline to move by %ds, got %s", addTimeStep, after.Sub(before))
+	}
+	srv.timersMu.Lock()
+	_, scheduled := srv.timers[game.ID]
+	srv.timersMu.Unlock()
+	if !scheduled {
+		t.Fatal("expected adding time to reschedule the phase timer")
+	}
+	for extended.PhaseExtension < maxPhaseExtensionSeconds*time.Second {
+		if extended, err = srv.addPhaseTime(game.ID, advanceRequest{PlayerID: 1, AuthToken: "host-secret"}); err != nil {
+			t.Fatalf("add time: %v", err)
+		}
+	}
+	if _, err := srv.addPhaseTime(game.ID, advanceRequest{PlayerID: 1, AuthToken: "host-secret"}); err == nil {
+		t.Fatal("expected added time to be capped")
+	}
+
+	// A phase change drops the extension.
+	advanced, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		setPhase(g, phaseGuessVotes)
+		return nil
+	})
+	if err != nil || advanced.PhaseExtension != 0 {
+		t.Fatalf("expected a new phase to start without added time, got %s (%v)", advanced.PhaseExtension, err)
+	}
+}
@@ -12,6 +12,7 @@ func (s *Server) schedulePhaseTimer(game *Game) {
 		s.cancelPhaseTimer(game.ID)
 		return
 	}
+	remaining := phaseRemaining(game, duration, time.Now().UTC())
 	s.timersMu.Lock()
 	s.timerGeneration[game.ID]++
 	generation := s.timerGeneration[game.ID]
@@ -20,7 +21,7 @@ func (s *Server) schedulePhaseTimer(game *Game) {
 	}
 	gameID := game.ID
 	expectedPhase := game.Phase
-	timer := time.AfterFunc(duration, func() {
+	timer := time.AfterFunc(remaining, func() {
 		s.timersMu.Lock()
 		current := s.timerGeneration[gameID]
 		s.timersMu.Unlock()
@@ -43,58 +44,136 @@ func (s *Server) cancelPhaseTimer(gameID string) {
 	}
 }
 
-func (s *Server) phaseDuration(game *Game) time.Duration {
-	if game == nil {
+// phaseRemaining measures the time left on the current phase from its start
+// time, so timers rebuilt after a restart keep the original deadline. Phases
+// that are already overdue return zero and fire immediately.
+func phaseRemaining(game *Game, duration time.Duration, now time.Time) time.Duration {
+	if game == nil || game.PhaseStartedAt.IsZero() {
+		return duration
+	}
+	remaining := game.PhaseStartedAt.Add(duration).Sub(now)
+	if remaining < 0 {
 		return 0
 	}
-	switch game.Phase {
-	case phaseDrawings:
-		return time.Duration(s.cfg.DrawDurationSeconds) * time.Second
-	case phaseGuesses:
-		return time.Duration(s.cfg.GuessDurationSeconds) * time.Second
-	case phaseGuessVotes:
-		return time.Duration(s.cfg.VoteDurationSeconds) * time.Second
-	case phaseResults:
-		round := currentRound(game)
-		if round == nil {
-			return time.Duration(s.cfg.RevealDurationSeconds) * time.Second
-		}
-		switch round.RevealStage {
-		case revealStageVotes:
-			base := s.cfg.RevealVotesSeconds
-			if base <= 0 {
-				base = s.cfg.RevealDurationSeconds
-			}
-			return time.Duration(revealVotesStageDurationSeconds(base, round)) * time.Second
-		case revealStageJoke:
-			if s.cfg.RevealJokeSeconds > 0 {
-				return time.Duration(s.cfg.RevealJokeSeconds) * time.Second
-			}
-		default:
-			if s.cfg.RevealGuessesSeconds > 0 {
-				return time.Duration(s.cfg.RevealGuessesSeconds) * time.Second
-			}
-		}
-		return time.Duration(s.cfg.RevealDurationSeconds) * time.Second
-	default:
+	if remaining > duration {
+		return duration
+	}
+	return remaining
+}
+
+// resumePausedPhase returns a paused game to the phase it was paused in. A
+// phase paused with time on its clock gets that time back: its start moves so
+// the deadline is the remaining time from now, and time the host had added
+// beyond the phase's length is carried over as an extension.
+func (s *Server) resumePausedPhase(game *Game, now time.Time) {
+	resume := game.PausedPhase
+	if resume == "" {
+		resume = phaseLobby
+	}
+	remaining := game.PausedRemaining
+	setPhaseAt(game, resume, now)
+	game.PausedPhase = ""
+	game.PausedRemaining = 0
+	duration := s.phaseDuration(game)
+	if duration <= 0 || remaining <= 0 {
+		return
+	}
+	if remaining > duration {
+		game.PhaseExtension = (remaining - duration).Round(time.Second)
+		duration = s.phaseDuration(game)
+	}
+	game.PhaseStartedAt = now.Add(remaining - duration)
+}
+
+// settleCompletePhase ends the current phase early once submissionsComplete says
+// every submission is in. With a grace period the phase's deadline is pulled
+// in to that period, so the last player sees their answer land before the
+// phase moves on; the phase timer then advances it. Without one, or for a
+// phase with no clock, it advances straight away. It reports whether the
+// phase or its deadline changed.
+func (s *Server) settleCompletePhase(game *Game, now time.Time) (bool, error) {
+	if !submissionsComplete(game) {
+		return false, nil
+	}
+	grace := time.Duration(s.cfg.PhaseGraceSeconds) * time.Second
+	duration := s.phaseDuration(game)
+	if grace <= 0 || duration <= 0 || game.PhaseStartedAt.IsZero() {
+		_, err := s.advancePhase(game, transitionManual, now)
+		return err == nil, err
+	}
+	if phaseRemaining(game, duration, now) <= grace {
+		return false, nil
+	}
+	game.PhaseDeadline = now.Add(grace).UTC()
+	return true, nil
+}
+
+// persistSettledPhase records what settleCompletePhase did to a game that
+// was in prevPhase.
+func (s *Server) persistSettledPhase(game *Game, prevPhase string, settled bool) error {
+	switch {
+	case game.Phase != prevPhase:
+		return s.persistPhase(game, "game_advanced", EventPayload{Phase: game.Phase, Reason: "all_submitted"})
+	case settled:
+		return s.persistPhase(game, "phase_completed", EventPayload{Phase: game.Phase, Reason: "all_submitted"})
+	}
+	return nil
+}
+
+// afterSettledPhase broadcasts a submission and re-arms the phase timer when
+// settleCompletePhase moved the phase or its deadline.
+func (s *Server) afterSettledPhase(game *Game, prevPhase string, settled bool) {
+	if game.Phase != prevPhase {
+		log.Printf("game advanced game_id=%s phase=%s reason=all_submitted", game.ID, game.Phase)
+	} else if settled {
+		log.Printf("phase complete game_id=%s phase=%s grace=%ds", game.ID, game.Phase, s.cfg.PhaseGraceSeconds)
+	}
+	s.broadcastGameUpdate(game)
+	if settled {
+		s.schedulePhaseTimer(game)
+	}
+}
+
+// pausedRemainingSeconds is the frozen countdown shown while a game is
+// paused, or zero when the paused phase had no clock.
+func pausedRemainingSeconds(game *Game) int {
+	if game == nil || game.Phase != phasePaused {
 		return 0
 	}
+	return int(game.PausedRemaining.Round(time.Second) / time.Second)
+}
+
+func (s *Server) phaseDuration(game *Game) time.Duration {
+	return time.Duration(phaseDurationSeconds(s.cfg, game)) * time.Second
 }
 
 func (s *Server) autoAdvancePhase(gameID string, expectedPhase string) {
+	s.forceAdvancePhase(gameID, expectedPhase, "timeout")
+}
+
+// forceAdvancePhase ends expectedPhase without waiting for the players who
+// have not submitted, filling in their missing work.
+func (s *Server) forceAdvancePhase(gameID string, expectedPhase string, reason string) {
 	now := time.Now().UTC()
 	filledGuesses := make([]autoFilledGuess, 0)
 	filledVotes := make([]autoFilledVote, 0)
+	filledLinks := make([]ChainLinkEntry, 0)
 	game, err := s.store.UpdateGameDurably(gameID, func(game *Game) error {
 		if game.Phase != expectedPhase {
 			return errors.New("phase changed")
 		}
+		if reason == "timeout" && submissionsComplete(game) {
+			reason = "all_submitted"
+		}
 		if expectedPhase == phaseGuesses {
 			filledGuesses = append(filledGuesses, autoFillMissingGuesses(game)...)
 		}
 		if expectedPhase == phaseGuessVotes {
 			filledVotes = append(filledVotes, autoFillMissingVotes(game)...)
 		}
+		if isChainStepPhase(expectedPhase) {
+			filledLinks = append(filledLinks, autoFillMissingChainLinks(game)...)
+		}
 		_, err := s.advancePhase(game, transitionAuto, now)
 		return err
 	}, func(game *Game) error {
@@ -108,6 +187,11 @@ func (s *Server) autoAdvancePhase(gameID string, expectedPhase string) {
 				return err
 			}
 		}
+		for _, filled := range filledLinks {
+			if err := s.persistChainLink(game, filled); err != nil {
+				return err
+			}
+		}
 		if game.Phase == phaseDrawings && expectedPhase != phaseDrawings {
 			if err := s.persistRound(game); err != nil {
 				return err
@@ -116,14 +200,14 @@ func (s *Server) autoAdvancePhase(gameID string, expectedPhase string) {
 				return err
 			}
 		}
-		return s.persistPhase(game, "game_advanced", EventPayload{Phase: game.Phase, Reason: "timeout"})
+		return s.persistPhase(game, "game_advanced", EventPayload{Phase: game.Phase, Reason: reason})
 	})
 	if err != nil {
 		log.Printf("game auto-advance failed game_id=%s phase=%s error=%v", gameID, expectedPhase, err)
 		return
 	}
 	if game.Phase != expectedPhase {
-		log.Printf("game auto-advanced game_id=%s from=%s to=%s", game.ID, expectedPhase, game.Phase)
+		log.Printf("game auto-advanced game_id=%s from=%s to=%s reason=%s", game.ID, expectedPhase, game.Phase, reason)
 	}
 	if game.Phase == phaseComplete {
 		s.cancelPhaseTimer(game.ID)
@@ -1,8 +1,11 @@
 package server
 
 import (
+	"errors"
 	"sort"
 	"strings"
+
+	domain "picture-this/internal/game"
 )
 
 func hasGuessForPlayer(round *RoundState, drawingIndex int, playerID int) bool {
@@ -41,6 +44,51 @@ func hasGuessText(round *RoundState, drawingIndex int, text string) bool {
 	return false
 }
 
+// checkGuessText vets a lie before it is recorded. It reports true when the
+// lie is really the drawing's title and the ruleset scores that as a correct
+// guess; near misses of the title or of another lie are rejected.
+func checkGuessText(game *Game, round *RoundState, drawingIndex int, playerID int, text string) (bool, error) {
+	if drawingIndex >= 0 && drawingIndex < len(round.Drawings) && domain.TitlesMatch(text, round.Drawings[drawingIndex].Prompt) {
+		if !titleGuessAllowed(game) {
+			return false, errors.New("that's too close to the real title; write a lie instead")
+		}
+		if hasTitleGuess(round, drawingIndex, playerID) {
+			return false, errors.New("you already guessed the real title; now write a lie")
+		}
+		return true, nil
+	}
+	if hasGuessText(round, drawingIndex, text) {
+		return false, errors.New("guess already used for this drawing")
+	}
+	if similarGuessExists(round, drawingIndex, text) {
+		return false, errors.New("someone already wrote a lie very close to that; try something different")
+	}
+	return false, nil
+}
+
+// similarGuessExists reports whether a lie for the drawing is too close to
+// text for voters to tell apart.
+func similarGuessExists(round *RoundState, drawingIndex int, text string) bool {
+	if round == nil {
+		return false
+	}
+	for _, guess := range round.Guesses {
+		if guess.DrawingIndex == drawingIndex && domain.TitlesMatch(guess.Text, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTitleGuess(round *RoundState, drawingIndex int, playerID int) bool {
+	for _, guess := range round.TitleGuesses {
+		if guess.DrawingIndex == drawingIndex && guess.PlayerID == playerID {
+			return true
+		}
+	}
+	return false
+}
+
 func nextGuessAssignment(game *Game, round *RoundState, playerID int) (int, bool) {
 	active := activeGuessDrawingIndex(game, round)
 	if active < 0 {
@@ -53,7 +101,7 @@ func nextGuessAssignment(game *Game, round *RoundState, playerID int) (int, bool
 	if drawing.PlayerID == playerID {
 		return -1, false
 	}
-	if hasGuessForPlayer(round, active, playerID) {
+	if guessSatisfiedForPlayer(game, round, active, playerID) {
 		return -1, false
 	}
 	return active, true
@@ -123,7 +171,7 @@ func pendingGuessersForIndex(game *Game, round *RoundState, drawingIndex int) []
 		if player.ID == drawingOwner {
 			continue
 		}
-		if hasGuessForPlayer(round, drawingIndex, player.ID) {
+		if guessSatisfiedForPlayer(game, round, drawingIndex, player.ID) {
 			continue
 		}
 		pending = append(pending, player.ID)
@@ -196,20 +244,10 @@ func firstAssignmentByOrder(game *Game, assignments map[int]int) (int, int, bool
 	return 0, -1, false
 }
 
+// requiredGuessCount is how many lies close the guesses phase. The phase
+// covers one drawing at a time, so it is the count for the active drawing.
 func requiredGuessCount(game *Game, round *RoundState) int {
-	if game == nil || round == nil || len(round.Drawings) == 0 {
-		return 0
-	}
-	count := 0
-	for _, drawing := range round.Drawings {
-		for _, player := range game.Players {
-			if player.ID == drawing.PlayerID {
-				continue
-			}
-			count++
-		}
-	}
-	return count
+	return requiredGuessCountForDrawing(game, round, normalizeDrawingIndex(round))
 }
 
 func requiredGuessCountForDrawing(game *Game, round *RoundState, drawingIndex int) int {
@@ -227,20 +265,10 @@ func requiredGuessCountForDrawing(game *Game, round *RoundState, drawingIndex in
 	return total
 }
 
+// requiredVoteCount is how many votes close the vote phase for the active
+// drawing.
 func requiredVoteCount(game *Game, round *RoundState) int {
-	if game == nil || round == nil || len(round.Drawings) == 0 {
-		return 0
-	}
-	count := 0
-	for _, drawing := range round.Drawings {
-		for _, player := range game.Players {
-			if player.ID == drawing.PlayerID {
-				continue
-			}
-			count++
-		}
-	}
-	return count
+	return requiredVoteCountForDrawing(game, round, normalizeDrawingIndex(round))
 }
 
 func requiredVoteCountForDrawing(game *Game, round *RoundState, drawingIndex int) int {
@@ -258,6 +286,41 @@ func requiredVoteCountForDrawing(game *Game, round *RoundState, drawingIndex int
 	return total
 }
 
+// phaseSubmissions reports how many submissions the current phase waits for
+// and how many are in. Phases that take no submissions require none.
+func phaseSubmissions(game *Game) (required int, submitted int) {
+	if game == nil {
+		return 0, 0
+	}
+	round := currentRound(game)
+	if round == nil {
+		return 0, 0
+	}
+	switch game.Phase {
+	case phaseDrawings:
+		return len(game.Players), len(round.Drawings)
+	case phaseGuesses:
+		required = requiredGuessCount(game, round)
+		return required, required - len(pendingGuessersForIndex(game, round, normalizeDrawingIndex(round)))
+	case phaseGuessVotes:
+		required = requiredVoteCount(game, round)
+		return required, required - len(pendingVotersForIndex(game, round, normalizeDrawingIndex(round)))
+	case phaseChainDraw, phaseChainDescribe:
+		if !telephoneEnabled(game) {
+			return 0, 0
+		}
+		return len(game.Players), len(game.Players) - len(pendingChainPlayers(game, round))
+	}
+	return 0, 0
+}
+
+// submissionsComplete reports whether every submission the current phase waits for
+// is in, so the phase can end before its timer.
+func submissionsComplete(game *Game) bool {
+	required, submitted := phaseSubmissions(game)
+	return required > 0 && submitted >= required
+}
+
 func remainingGuessesForPlayer(game *Game, round *RoundState, playerID int) int {
 	if game == nil || round == nil {
 		return 0
@@ -268,7 +331,7 @@ func remainingGuessesForPlayer(game *Game, round *RoundState, playerID int) int
 			continue
 		}
 		total++
-		if hasGuessForPlayer(round, drawingIndex, playerID) {
+		if guessSatisfiedForPlayer(game, round, drawingIndex, playerID) {
 			total--
 		}
 	}
@@ -309,7 +372,7 @@ func guessRemainingByPlayer(game *Game, round *RoundState) map[int]int {
 			result[player.ID] = 0
 			continue
 		}
-		if round.Drawings[drawingIndex].PlayerID == player.ID || hasGuessForPlayer(round, drawingIndex, player.ID) {
+		if round.Drawings[drawingIndex].PlayerID == player.ID || guessSatisfiedForPlayer(game, round, drawingIndex, player.ID) {
 			result[player.ID] = 0
 			continue
 		}
@@ -382,7 +445,7 @@ func guessAssignmentsPayload(game *Game, round *RoundState, assignments map[int]
 			"player_id":       player.ID,
 			"drawing_index":   drawingIndex,
 			"drawing_owner":   drawing.PlayerID,
-			"drawing_image":   encodeImageData(drawing.ImageData),
+			"drawing_image":   imageSource(drawing.ImageData, drawing.ImageHash),
 			"pending_for_one": pendingGuessersForDrawing(game, assignments, drawingIndex),
 		})
 	}
@@ -407,7 +470,7 @@ func voteAssignmentsPayload(game *Game, round *RoundState, assignments map[int]i
 			"player_id":       player.ID,
 			"drawing_index":   drawingIndex,
 			"drawing_owner":   drawing.PlayerID,
-			"drawing_image":   encodeImageData(drawing.ImageData),
+			"drawing_image":   imageSource(drawing.ImageData, drawing.ImageHash),
 			"options":         voteOptionsForPlayerPayload(game, voteOptionEntries(round, drawingIndex), player.ID),
 			"pending_for_one": pendingVotersForDrawing(game, assignments, drawingIndex),
 		})
@@ -416,14 +479,15 @@ func voteAssignmentsPayload(game *Game, round *RoundState, assignments map[int]i
 }
 
 func voteOptionsForPlayerPayload(game *Game, options []VoteOption, playerID int) []map[string]any {
-	if game == nil || game.Ruleset != rulesetDrawful {
+	if game == nil || (game.Ruleset != rulesetDrawful && !teamsEnabled(game)) {
 		return voteOptionsPayload(options)
 	}
 	result := make([]map[string]any, 0, len(options))
 	for _, option := range options {
 		result = append(result, map[string]any{
 			"id": option.ID, "text": option.Text,
-			"is_own": option.Type == voteChoiceGuess && option.OwnerID == playerID,
+			"is_own":   option.Type == voteChoiceGuess && (option.OwnerID == playerID || sameTeam(game, option.OwnerID, playerID)),
+			"team_lie": option.Type == voteChoiceGuess && sameTeam(game, option.OwnerID, playerID),
 		})
 	}
 	return result
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+)
+
+// completablePhases builds games one submission short of finishing each
+// phase, with finish adding the last one.
+var completablePhases = []struct {
+	name   string
+	next   string
+	game   func(startedAt time.Time) *Game
+	finish func(game *Game)
+}{
+	{
+		name: "drawings",
+		next: phaseGuesses,
+		game: func(startedAt time.Time) *Game {
+			game := restoredGuessGame(startedAt)
+			game.Phase = phaseDrawings
+			game.Rounds[0].Prompts = append(game.Rounds[0].Prompts, PromptEntry{PlayerID: 3, Text: "owl"})
+			return game
+		},
+		finish: func(game *Game) {
+			round := currentRound(game)
+			round.Drawings = append(round.Drawings, DrawingEntry{PlayerID: 3, Prompt: "owl", ImageData: []byte{3}})
+		},
+	},
+	{
+		name: "guesses",
+		next: phaseGuessVotes,
+		game: func(startedAt time.Time) *Game {
+			game := restoredGuessGame(startedAt)
+			game.Rounds[0].Guesses = []GuessEntry{{PlayerID: 2, DrawingIndex: 0, Text: "kitten"}}
+			return game
+		},
+		finish: func(game *Game) {
+			round := currentRound(game)
+			round.Guesses = append(round.Guesses, GuessEntry{PlayerID: 3, DrawingIndex: 0, Text: "lion"})
+		},
+	},
+	{
+		name: "votes",
+		next: phaseResults,
+		game: func(startedAt time.Time) *Game {
+			game := restoredGuessGame(startedAt)
+			game.Phase = phaseGuessVotes
+			game.Rounds[0].Guesses = []GuessEntry{{PlayerID: 2, DrawingIndex: 0, Text: "kitten"}, {PlayerID: 3, DrawingIndex: 0, Text: "lion"}}
+			game.Rounds[0].Votes = []VoteEntry{{PlayerID: 2, DrawingIndex: 0, ChoiceText: "cat", ChoiceType: voteChoicePrompt}}
+			return game
+		},
+		finish: func(game *Game) {
+			round := currentRound(game)
+			round.Votes = append(round.Votes, VoteEntry{PlayerID: 3, DrawingIndex: 0, ChoiceText: "kitten", ChoiceType: voteChoiceGuess})
+		},
+	},
+	{
+		name: "chain step",
+		next: phaseChainDescribe,
+		game: func(startedAt time.Time) *Game {
+			game := telephoneGame()
+			game.PhaseStartedAt = startedAt
+			round := currentRound(game)
+			for _, player := range game.Players[:2] {
+				task, _ := chainTaskForPlayer(game, round, player.ID)
+				round.ChainLinks = append(round.ChainLinks, ChainLinkEntry{ChainIndex: task.ChainIndex, PlayerID: player.ID, Kind: string(task.Kind), ImageData: []byte{1}})
+			}
+			return game
+		},
+		finish: func(game *Game) {
+			round := currentRound(game)
+			task, _ := chainTaskForPlayer(game, round, 3)
+			round.ChainLinks = append(round.ChainLinks, ChainLinkEntry{ChainIndex: task.ChainIndex, PlayerID: 3, Kind: string(task.Kind), ImageData: []byte{3}})
+		},
+	},
+}
+
+func TestCompletePhasesEndAfterGrace(t *testing.T) {
+	for _, tc := range completablePhases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Now().UTC()
+			cfg := config.Default()
+			cfg.PhaseGraceSeconds = 3
+			srv := New(nil, cfg)
+
+			game := tc.game(now.Add(-10 * time.Second))
+			if required, submitted := phaseSubmissions(game); required == 0 || submitted != required-1 {
+				t.Fatalf("expected one submission missing, got %d/%d", submitted, required)
+			}
+			if settled, err := srv.settleCompletePhase(game, now); settled || err != nil {
+				t.Fatalf("expected an incomplete phase to keep its clock, got %t (%v)", settled, err)
+			}
+			tc.finish(game)
+			if !submissionsComplete(game) {
+				t.Fatal("expected the phase to be complete")
+			}
+			phase := game.Phase
+			if settled, err := srv.settleCompletePhase(game, now); !settled || err != nil {
+				t.Fatalf("expected the deadline to be pulled in, got %t (%v)", settled, err)
+			}
+			if game.Phase != phase {
+				t.Fatalf("expected the phase to wait out its grace period, got %s", game.Phase)
+			}
+			if remaining := phaseRemaining(game, srv.phaseDuration(game), now); remaining != 3*time.Second {
+				t.Fatalf("expected 3s left on the clock, got %s", remaining)
+			}
+			if game.PhaseExtension != 0 || timerSettingsPayload(srv.cfg, game)["added_seconds"] != 0 {
+				t.Fatalf("expected an early end to leave the host's added time alone, got %s", game.PhaseExtension)
+			}
+			if settled, _ := srv.settleCompletePhase(game, now.Add(time.Second)); settled {
+				t.Fatal("expected a phase already in its grace period to be left alone")
+			}
+
+			srv.cfg.PhaseGraceSeconds = 0
+			game = tc.game(now)
+			tc.finish(game)
+			if settled, err := srv.settleCompletePhase(game, now); !settled || err != nil || game.Phase != tc.next {
+				t.Fatalf("expected no grace period to advance to %s, got %s (%t, %v)", tc.next, game.Phase, settled, err)
+			}
+		})
+	}
+}
+
+func TestLastVoteEndsPhaseAfterGrace(t *testing.T) {
+	cfg := config.Default()
+	cfg.PhaseGraceSeconds = 1
+	srv := New(nil, cfg)
+	game := restoredGuessGame(time.Now().UTC())
+	game.Phase = phaseGuessVotes
+	game.PlayerAuthTokens = map[int]string{2: "ben-secret", 3: "cam-secret"}
+	game.Rounds[0].Guesses = []GuessEntry{{PlayerID: 2, DrawingIndex: 0, Text: "kitten"}, {PlayerID: 3, DrawingIndex: 0, Text: "lion"}}
+	if err := srv.store.RestoreGame(game); err != nil {
+		t.Fatalf("restore game: %v", err)
+	}
+	srv.schedulePhaseTimer(game)
+	defer srv.cancelPhaseTimer(game.ID)
+
+	if _, err := srv.submitVote(game.ID, votesRequest{PlayerID: 2, AuthToken: "ben-secret", Choice: "cat"}); err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+	voted, err := srv.submitVote(game.ID, votesRequest{PlayerID: 3, AuthToken: "cam-secret", Choice: "kitten"})
+	if err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+	if voted.Phase != phaseGuessVotes {
+		t.Fatalf("expected votes to stay open for the grace period, got %s", voted.Phase)
+	}
+	endsAt, _ := time.Parse(time.RFC3339, srv.snapshotForPlayer(voted, 2)["phase_ends_at"].(string))
+	if until := time.Until(endsAt); until > 2*time.Second {
+		t.Fatalf("expected the broadcast deadline to be the grace period, got %s away", until)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		current, _ := srv.store.GetGame(game.ID)
+		if current.Phase == phaseResults {
+			if round := currentRound(current); len(round.Votes) != 2 || round.RevealStage != revealStageGuesses {
+				t.Fatalf("expected the reveal to start with both votes, got %d votes at %q", len(round.Votes), round.RevealStage)
+			}
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("expected the vote phase to end after its grace period")
+}
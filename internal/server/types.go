@@ -14,11 +14,18 @@ const (
 	phaseResults    = string(domain.PhaseResults)
 	phasePaused     = string(domain.PhasePaused)
 	phaseComplete   = string(domain.PhaseComplete)
+
+	phaseChainDraw     = string(domain.PhaseChainDraw)
+	phaseChainDescribe = string(domain.PhaseChainDescribe)
+	phaseChainReveal   = string(domain.PhaseChainReveal)
 )
 
 const (
 	rulesetLegacy  = string(domain.RulesetLegacy)
 	rulesetDrawful = string(domain.RulesetDrawful)
+	rulesetTeams   = string(domain.RulesetTeams)
+
+	rulesetTelephone = string(domain.RulesetTelephone)
 )
 
 const (
@@ -39,6 +46,7 @@ const (
 	revealStageGuesses = "guesses"
 	revealStageVotes   = "votes"
 	revealStageJoke    = "joke"
+	revealStageAwards  = "awards"
 )
 
 type GameSummary struct {
@@ -58,10 +66,21 @@ type Game struct {
 	MaxPlayers       int
 	LobbyLocked      bool
 	PausedPhase      string
+	PausedRemaining  time.Duration
+	PhaseExtension   time.Duration
+	PhaseDeadline    time.Time // early end once everyone has submitted
 	UsedPrompts      map[string]struct{}
 	KickedPlayers    map[string]struct{}
 	HostID           int
 	PlayerAuthTokens map[int]string
+	// PlayerAuthHashes holds the stored SHA-256 of tokens issued before the
+	// game was last loaded from the database.
+	PlayerAuthHashes map[int]string
+	// DisplayToken lets a big screen subscribe to the game's display
+	// fragments. Only its SHA-256 is stored, so after a reload the token is
+	// empty and screens opened earlier authenticate against DisplayTokenHash.
+	DisplayToken     string
+	DisplayTokenHash string
 	Audience         []AudienceMember
 	Players          []Player
 	Rounds           []RoundState
@@ -71,6 +90,9 @@ type Game struct {
 	AudienceEnabled  bool
 	JokesEnabled     bool
 	PublicReplay     bool
+	TeamCount        int
+	ScoringRules     domain.ScoringRules
+	Timers           domain.TimerSettings
 	Version          int64
 }
 
@@ -78,12 +100,14 @@ type Player struct {
 	ID           int
 	Name         string
 	Avatar       []byte
+	AvatarHash   string
 	AvatarLocked bool
 	IsHost       bool
 	DBID         uint
 	Color        string
 	Claimed      bool
 	RecoveryHash string
+	Team         int
 }
 
 type RoundState struct {
@@ -95,8 +119,32 @@ type RoundState struct {
 	Votes         []VoteEntry
 	AudienceVotes []AudienceVoteEntry
 	Likes         []LikeEntry
+	TitleGuesses  []TitleGuessEntry
 	RevealIndex   int
 	RevealStage   string
+	ChainStep     int
+	ChainLinks    []ChainLinkEntry
+}
+
+// ChainLinkEntry is one drawing or description added to a telephone chain.
+// Chain indexes follow the order of game.Players.
+type ChainLinkEntry struct {
+	ChainIndex int
+	Step       int
+	PlayerID   int
+	Kind       string
+	Text       string
+	ImageData  []byte
+	DBID       uint
+
+	ImageHash string
+}
+
+// TitleGuessEntry records a player typing the real title as their lie. The
+// guess is kept in title_guesses; the lie itself must still be written.
+type TitleGuessEntry struct {
+	PlayerID     int
+	DrawingIndex int
 }
 
 type LikeEntry struct {
@@ -114,11 +162,17 @@ type PromptEntry struct {
 	DBID          uint
 }
 
+// DrawingEntry holds the PNG every view shows, inline in ImageData or, with a
+// blob store configured, as ImageHash. Strokes is the vector document it was
+// rasterized from, when the client submitted one.
 type DrawingEntry struct {
 	PlayerID  int
 	ImageData []byte
 	Prompt    string
 	DBID      uint
+
+	Strokes   []byte
+	ImageHash string
 }
 
 type GuessEntry struct {
@@ -60,9 +60,23 @@ func registerValidators() {
 }
 
 func (s *Server) enforceRateLimit(c *gin.Context, action string) bool {
+	allowed, retry := s.allowRequest(action, requestClientIP(c.Request))
+	if allowed {
+		return true
+	}
+	c.Header("Retry-After", strconv.Itoa(max(1, int(retry.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": rateLimitMessage})
+	return false
+}
+
+const rateLimitMessage = "too many requests; try again shortly"
+
+// allowRequest counts one request for action from clientIP and reports
+// whether it is within the limit, and if not, how long until it will be.
+func (s *Server) allowRequest(action, clientIP string) (bool, time.Duration) {
 	limit, window := ratePolicy(action)
 	now := s.rateNow()
-	key := action + ":" + requestClientIP(c.Request)
+	key := action + ":" + clientIP
 	s.rateMu.Lock()
 	entry := s.rateEntries[key]
 	if entry == nil || now.Sub(entry.started) >= window {
@@ -80,12 +94,7 @@ func (s *Server) enforceRateLimit(c *gin.Context, action string) bool {
 		}
 	}
 	s.rateMu.Unlock()
-	if allowed {
-		return true
-	}
-	c.Header("Retry-After", strconv.Itoa(max(1, int(retry.Seconds()))))
-	c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many requests; try again shortly"})
-	return false
+	return allowed, retry
 }
 
 type rateEntry struct {
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
+	"time"
 
 	"picture-this/internal/web"
 
@@ -17,240 +20,351 @@ type gameWSURI struct {
 }
 
 type wsQuery struct {
-	Role string `form:"role"`
+	Role      string `form:"role"`
+	PlayerID  int    `form:"player_id"`
+	AuthToken string `form:"auth_token"`
+	// DisplayToken authorizes the display role; the host shares it through
+	// the big screen link.
+	DisplayToken string `form:"display_token"`
+	// Deltas opts into state_snapshot and state_delta messages. Stream and
+	// Since are the last stream id and revision the client applied.
+	Deltas bool   `form:"deltas"`
+	Stream string `form:"stream"`
+	Since  int64  `form:"since"`
 }
 
 type wsHTMLMessage struct {
-	Type   string `json:"type"`
-	Target string `json:"target"`
-	Swap   string `json:"swap"`
-	HTML   string `json:"html"`
+	Type       string `json:"type"`
+	Target     string `json:"target"`
+	Swap       string `json:"swap"`
+	HTML       string `json:"html"`
+	ServerTime int64  `json:"server_time,omitempty"`
 }
 
 func htmlMessage(target, swap, html string) wsHTMLMessage {
 	return wsHTMLMessage{
-		Type:   "html",
-		Target: target,
-		Swap:   swap,
-		HTML:   html,
+		Type:       "html",
+		Target:     target,
+		Swap:       swap,
+		HTML:       html,
+		ServerTime: serverTimeMillis(),
 	}
 }
 
 type wsHub struct {
-	mu      sync.Mutex
-	groups  map[string]map[*websocket.Conn]struct{}
-	hosts   map[string]map[*websocket.Conn]struct{}
-	display map[string]map[*websocket.Conn]struct{}
+	mu       sync.Mutex
+	groups   map[string]map[*wsClient]struct{}
+	hosts    map[string]map[*wsClient]struct{}
+	display  map[string]map[*wsClient]struct{}
+	timeouts wsTimeouts
+	// fragments is the last HTML sent per game, role and target, so
+	// unchanged fragments are not sent again.
+	fragments map[string]map[string]string
+	// hostIDs is the host each game's host group was last synced to.
+	hostIDs map[string]int
 }
 
 type homeHub struct {
-	mu    sync.Mutex
-	conns map[*websocket.Conn]struct{}
+	mu       sync.Mutex
+	conns    map[*wsClient]struct{}
+	timeouts wsTimeouts
 }
 
 func newWSHub() *wsHub {
 	return &wsHub{
-		groups:  make(map[string]map[*websocket.Conn]struct{}),
-		hosts:   make(map[string]map[*websocket.Conn]struct{}),
-		display: make(map[string]map[*websocket.Conn]struct{}),
+		groups:    make(map[string]map[*wsClient]struct{}),
+		hosts:     make(map[string]map[*wsClient]struct{}),
+		display:   make(map[string]map[*wsClient]struct{}),
+		timeouts:  defaultWSTimeouts,
+		fragments: make(map[string]map[string]string),
+		hostIDs:   make(map[string]int),
 	}
 }
 
 func newHomeHub() *homeHub {
 	return &homeHub{
-		conns: make(map[*websocket.Conn]struct{}),
+		conns:    make(map[*wsClient]struct{}),
+		timeouts: defaultWSTimeouts,
 	}
 }
 
-func (h *wsHub) Add(gameID string, conn *websocket.Conn, role string) {
+func (h *wsHub) Add(gameID string, client *wsClient, role string) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	if role == wsRoleDisplay || role == wsRoleHost {
+		// The new client is sent every fragment on connect; forgetting what
+		// the others were sent keeps the next broadcast from skipping any.
+		delete(h.fragments, gameID)
+	}
 	if role == wsRoleDisplay {
 		group := h.display[gameID]
 		if group == nil {
-			group = make(map[*websocket.Conn]struct{})
+			group = make(map[*wsClient]struct{})
 			h.display[gameID] = group
 		}
-		group[conn] = struct{}{}
+		group[client] = struct{}{}
 		return
 	}
 	group := h.groups[gameID]
 	if group == nil {
-		group = make(map[*websocket.Conn]struct{})
+		group = make(map[*wsClient]struct{})
 		h.groups[gameID] = group
 	}
-	group[conn] = struct{}{}
+	group[client] = struct{}{}
 	if role == wsRoleHost {
 		hostGroup := h.hosts[gameID]
 		if hostGroup == nil {
-			hostGroup = make(map[*websocket.Conn]struct{})
+			hostGroup = make(map[*wsClient]struct{})
 			h.hosts[gameID] = hostGroup
 		}
-		hostGroup[conn] = struct{}{}
+		hostGroup[client] = struct{}{}
 	}
 }
 
-func (h *homeHub) Add(conn *websocket.Conn) {
+func (h *homeHub) Add(client *wsClient) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.conns[conn] = struct{}{}
+	h.conns[client] = struct{}{}
 }
 
-func (h *homeHub) Remove(conn *websocket.Conn) {
+func (h *homeHub) Remove(client *wsClient) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	delete(h.conns, conn)
-	_ = conn.Close()
+	delete(h.conns, client)
+	client.Close()
 }
 
-func (h *homeHub) Send(conn *websocket.Conn, payload any) {
+func (h *homeHub) Send(client *wsClient, payload any) {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return
 	}
-	_ = conn.WriteMessage(websocket.TextMessage, data)
+	client.enqueue(data)
 }
 
 func (h *homeHub) Broadcast(payload any) {
-	h.mu.Lock()
-	conns := make([]*websocket.Conn, 0, len(h.conns))
-	for conn := range h.conns {
-		conns = append(conns, conn)
-	}
-	h.mu.Unlock()
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return
 	}
-	for _, conn := range conns {
-		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-			h.Remove(conn)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for client := range h.conns {
+		if !client.enqueue(data) {
+			log.Printf("home ws dropped slow client")
+			delete(h.conns, client)
 		}
 	}
 }
 
-func (h *wsHub) Remove(gameID string, conn *websocket.Conn, role string) {
+func (h *wsHub) Remove(gameID string, client *wsClient, role string) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	h.removeLocked(gameID, client, role)
+}
+
+func (h *wsHub) removeLocked(gameID string, client *wsClient, role string) {
+	client.Close()
 	if role == wsRoleDisplay {
-		group := h.display[gameID]
-		if group == nil {
-			return
+		if group := h.display[gameID]; group != nil {
+			delete(group, client)
+			if len(group) == 0 {
+				delete(h.display, gameID)
+			}
+		}
+	} else if group := h.groups[gameID]; group != nil {
+		delete(group, client)
+		if hostGroup := h.hosts[gameID]; hostGroup != nil {
+			delete(hostGroup, client)
+			if len(hostGroup) == 0 {
+				delete(h.hosts, gameID)
+			}
 		}
-		delete(group, conn)
-		_ = conn.Close()
 		if len(group) == 0 {
-			delete(h.display, gameID)
+			delete(h.groups, gameID)
 		}
-		return
 	}
-	group := h.groups[gameID]
-	if group == nil {
-		return
+	if len(h.groups[gameID]) == 0 && len(h.display[gameID]) == 0 {
+		delete(h.fragments, gameID)
+		delete(h.hostIDs, gameID)
 	}
-	delete(group, conn)
-	if hostGroup := h.hosts[gameID]; hostGroup != nil {
-		delete(hostGroup, conn)
-		if len(hostGroup) == 0 {
-			delete(h.hosts, gameID)
+}
+
+// SyncHosts brings the game's host group in line with hostID. Host sockets
+// of any other player are closed, since they were only authorized for an
+// earlier host. After a host change the new host's other sockets are
+// returned so they can be told to reconnect as host.
+func (h *wsHub) SyncHosts(gameID string, hostID int) []*wsClient {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for client := range h.hosts[gameID] {
+		if client.playerID != hostID {
+			h.removeLocked(gameID, client, wsRoleHost)
 		}
 	}
-	_ = conn.Close()
-	if len(group) == 0 {
-		delete(h.groups, gameID)
+	previous, known := h.hostIDs[gameID]
+	h.hostIDs[gameID] = hostID
+	if !known || previous == hostID {
+		return nil
 	}
+	promoted := make([]*wsClient, 0)
+	for client := range h.groups[gameID] {
+		if _, isHost := h.hosts[gameID][client]; client.playerID == hostID && !isHost {
+			promoted = append(promoted, client)
+		}
+	}
+	return promoted
 }
 
-func (h *wsHub) Send(conn *websocket.Conn, payload any) {
-	data, err := json.Marshal(payload)
-	if err != nil {
-		return
-	}
-	_ = conn.WriteMessage(websocket.TextMessage, data)
+func (h *wsHub) HasSubscribers(gameID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.groups[gameID]) > 0 || len(h.hosts[gameID]) > 0 || len(h.display[gameID]) > 0
 }
 
-func (h *wsHub) SendHTML(conn *websocket.Conn, payload any) {
+func (h *wsHub) Send(client *wsClient, payload any) {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return
 	}
-	_ = conn.WriteMessage(websocket.TextMessage, data)
+	client.enqueue(data)
 }
 
-func (h *wsHub) SendDisplay(conn *websocket.Conn, payload any) {
+// SendToPlayer queues the payload for every connection authenticated as the
+// player.
+func (h *wsHub) SendToPlayer(gameID string, playerID int, payload any) {
+	if playerID <= 0 {
+		return
+	}
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return
 	}
-	_ = conn.WriteMessage(websocket.TextMessage, data)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for client := range h.groups[gameID] {
+		if client.playerID == playerID && !client.enqueue(data) {
+			h.removeLocked(gameID, client, wsRolePlayer)
+		}
+	}
 }
 
-func (h *wsHub) Broadcast(gameID string, payload any) {
+// Views lists the state views that delta clients of the game subscribe to.
+func (h *wsHub) Views(gameID string) []string {
 	h.mu.Lock()
-	group := h.groups[gameID]
-	conns := make([]*websocket.Conn, 0, len(group))
-	for conn := range group {
-		conns = append(conns, conn)
+	defer h.mu.Unlock()
+	seen := make(map[string]bool)
+	views := make([]string, 0)
+	for client := range h.groups[gameID] {
+		if client.view != "" && !seen[client.view] {
+			seen[client.view] = true
+			views = append(views, client.view)
+		}
 	}
-	h.mu.Unlock()
+	return views
+}
 
+// SendView queues the payload for the game's delta clients on view.
+func (h *wsHub) SendView(gameID, view string, payload any) {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return
 	}
-	for _, conn := range conns {
-		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-			h.Remove(gameID, conn, wsRolePlayer)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for client := range h.groups[gameID] {
+		if client.view == view && !client.enqueue(data) {
+			log.Printf("ws dropped slow client game_id=%s view=%s", gameID, view)
+			h.removeLocked(gameID, client, wsRolePlayer)
 		}
 	}
 }
 
-func (h *wsHub) BroadcastHTML(gameID string, payload any) {
+// BroadcastFragments sends the role's clients the HTML fragments that
+// changed since the last broadcast. Display clients take a single fragment.
+func (h *wsHub) BroadcastFragments(gameID, role string, messages []wsHTMLMessage) {
 	h.mu.Lock()
-	group := h.hosts[gameID]
-	conns := make([]*websocket.Conn, 0, len(group))
-	for conn := range group {
-		conns = append(conns, conn)
+	sent := h.fragments[gameID]
+	if sent == nil {
+		sent = make(map[string]string)
+		h.fragments[gameID] = sent
+	}
+	fresh := make([]wsHTMLMessage, 0, len(messages))
+	for _, message := range messages {
+		key := role + " " + message.Target
+		if sent[key] == message.HTML {
+			continue
+		}
+		sent[key] = message.HTML
+		fresh = append(fresh, message)
 	}
 	h.mu.Unlock()
-	data, err := json.Marshal(payload)
-	if err != nil {
+	if len(fresh) == 0 {
 		return
 	}
-	for _, conn := range conns {
-		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-			h.Remove(gameID, conn, wsRoleHost)
-		}
+	if role == wsRoleDisplay {
+		h.broadcast(gameID, fresh[0], role)
+		return
 	}
+	h.broadcast(gameID, fresh, role)
 }
 
-func (h *wsHub) BroadcastDisplay(gameID string, payload any) {
-	h.mu.Lock()
-	group := h.display[gameID]
-	conns := make([]*websocket.Conn, 0, len(group))
-	for conn := range group {
-		conns = append(conns, conn)
-	}
-	h.mu.Unlock()
+func (h *wsHub) SendHTML(client *wsClient, payload any) {
+	h.Send(client, payload)
+}
+
+func (h *wsHub) SendDisplay(client *wsClient, payload any) {
+	h.Send(client, payload)
+}
+
+func (h *wsHub) Broadcast(gameID string, payload any) {
+	h.broadcast(gameID, payload, wsRolePlayer)
+}
+
+// broadcast queues the payload for every client in the role's group. Queuing
+// never blocks, so holding the lock is cheap; clients that have fallen behind
+// are dropped.
+func (h *wsHub) broadcast(gameID string, payload any, role string) {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return
 	}
-	for _, conn := range conns {
-		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-			h.Remove(gameID, conn, wsRoleDisplay)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	group := h.groups[gameID]
+	switch role {
+	case wsRoleHost:
+		group = h.hosts[gameID]
+	case wsRoleDisplay:
+		group = h.display[gameID]
+	}
+	for client := range group {
+		if role == wsRolePlayer && client.view != "" {
+			continue
+		}
+		if !client.enqueue(data) {
+			log.Printf("ws dropped slow client game_id=%s role=%s", gameID, role)
+			h.removeLocked(gameID, client, role)
 		}
 	}
 }
 
-func (s *Server) handleWebsocket(c *gin.Context) {
+// gameStreamRequest authorizes a realtime subscription to a game, over a
+// websocket or server-sent events, before the response is taken over. Host
+// sockets must authenticate as the host and display sockets must carry the
+// game's display token.
+func (s *Server) gameStreamRequest(c *gin.Context) (*Game, wsQuery, string, bool) {
 	var uri gameWSURI
 	if !bindURI(c, &uri) {
-		return
+		return nil, wsQuery{}, "", false
+	}
+	game, exists := s.store.GetGame(uri.GameID)
+	if !exists {
+		game, exists = s.clusterReplica(uri.GameID)
 	}
-	if _, exists := s.store.GetGame(uri.GameID); !exists {
+	if !exists {
 		c.Status(http.StatusNotFound)
-		return
+		return nil, wsQuery{}, "", false
 	}
 	var query wsQuery
 	_ = bindQuery(c, &query)
@@ -258,73 +372,207 @@ func (s *Server) handleWebsocket(c *gin.Context) {
 	if role == "" {
 		role = wsRolePlayer
 	}
-	upgrader := websocket.Upgrader{
-		CheckOrigin: func(r *http.Request) bool {
+	if !s.originAllowed(c.Request) {
+		rejectStream(c, game.ID, role, query.PlayerID, "origin not allowed")
+		return nil, wsQuery{}, "", false
+	}
+	switch role {
+	case wsRolePlayer, wsRoleAudience:
+	case wsRoleHost:
+		if _, err := s.authenticateHostRequest(game, query.PlayerID, query.AuthToken); err != nil {
+			rejectStream(c, game.ID, role, query.PlayerID, err.Error())
+			return nil, wsQuery{}, "", false
+		}
+	case wsRoleDisplay:
+		if err := authenticateDisplayRequest(game, query.DisplayToken); err != nil {
+			rejectStream(c, game.ID, role, query.PlayerID, err.Error())
+			return nil, wsQuery{}, "", false
+		}
+	default:
+		rejectStream(c, game.ID, role, query.PlayerID, "unknown role")
+		return nil, wsQuery{}, "", false
+	}
+	if query.PlayerID > 0 && role != wsRoleHost {
+		if _, err := s.authenticatePlayerRequest(game, query.PlayerID, query.AuthToken); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return nil, wsQuery{}, "", false
+		}
+	}
+	return game, query, role, true
+}
+
+// originAllowed reports whether a browser page may open a realtime
+// connection. Requests without an Origin header do not come from a page.
+func (s *Server) originAllowed(r *http.Request) bool {
+	origin := strings.TrimRight(r.Header.Get("Origin"), "/")
+	if origin == "" {
+		return true
+	}
+	if parsed, err := url.Parse(origin); err == nil && strings.EqualFold(parsed.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range s.cfg.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
 			return true
-		},
+		}
+	}
+	return false
+}
+
+// rejectStream refuses a realtime subscription and leaves an audit line.
+func rejectStream(c *gin.Context, gameID, role string, playerID int, reason string) {
+	log.Printf("stream rejected game_id=%s role=%q player_id=%d remote=%s origin=%q reason=%q", gameID, role, playerID, requestClientIP(c.Request), c.GetHeader("Origin"), reason)
+	c.JSON(http.StatusForbidden, gin.H{"error": reason})
+}
+
+// subscribeGameStream adds a new client to the hub and sends it what it
+// needs to catch up. A client that says it has seen lastVersion, the game's
+// current version, is not told to refetch.
+func (s *Server) subscribeGameStream(game *Game, client *wsClient, query wsQuery, role string, lastVersion int64) {
+	s.ws.Add(game.ID, client, role)
+	if current, ok := s.store.GetGame(game.ID); ok {
+		game = current
+	}
+	s.syncHostSockets(game)
+	switch {
+	case role == wsRoleDisplay:
+		s.ws.SendDisplay(client, htmlMessage("#displayContent", "outer", s.renderDisplayHTML(game)))
+	case client.view != "":
+		s.syncClientState(game, client, query.Stream, query.Since)
+	case lastVersion == 0 || lastVersion != game.Version:
+		s.ws.Send(client, stateChangedMessage{Type: "state_changed", Version: game.Version, ServerTime: serverTimeMillis()})
+	}
+	if role == wsRoleHost {
+		s.ws.SendHTML(client, s.renderGameHTMLMessages(game))
+	}
+	if client.playerID > 0 && s.presence.Connect(game.ID, client.playerID, time.Now().UTC()) {
+		s.broadcastPresence(game.ID)
 	}
+}
+
+func (s *Server) handleWebsocket(c *gin.Context) {
+	game, query, role, ok := s.gameStreamRequest(c)
+	if !ok {
+		return
+	}
+	upgrader := websocket.Upgrader{CheckOrigin: s.originAllowed}
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		return
 	}
-	log.Printf("ws connected game_id=%s remote=%s", uri.GameID, c.Request.RemoteAddr)
-	s.ws.Add(uri.GameID, conn, role)
-	if game, ok := s.store.GetGame(uri.GameID); ok {
-		if role == wsRoleDisplay {
-			s.ws.SendDisplay(conn, htmlMessage("#displayContent", "outer", s.renderDisplayHTML(game)))
-		} else {
-			s.ws.Send(conn, stateChangedMessage{Type: "state_changed", Version: game.Version})
-			if role == wsRoleHost {
-				s.ws.SendHTML(conn, s.renderGameHTMLMessages(game))
-			}
-		}
-	}
-	go s.readWS(uri.GameID, conn, role)
+	log.Printf("ws connected game_id=%s remote=%s", game.ID, c.Request.RemoteAddr)
+	client := newWSClient(conn, s.ws.timeouts)
+	client.playerID = query.PlayerID
+	client.clientIP = requestClientIP(c.Request)
+	if client.playerID > 0 {
+		client.authToken = query.AuthToken
+		conn.SetReadLimit(wsMaxCommandSize)
+	}
+	// The big screen renders server markup rather than snapshot JSON, so
+	// display sockets keep their fragment, which BroadcastFragments only
+	// resends when the markup changes.
+	if query.Deltas && role != wsRoleDisplay {
+		client.view = stateViewFor(role, client.playerID)
+	}
+	s.subscribeGameStream(game, client, query, role, 0)
+	go s.readWS(game.ID, client, role)
 }
 
 func (s *Server) handleHomeWebsocket(c *gin.Context) {
-	upgrader := websocket.Upgrader{
-		CheckOrigin: func(r *http.Request) bool {
-			return true
-		},
+	if !s.originAllowed(c.Request) {
+		log.Printf("home ws rejected remote=%s origin=%q reason=%q", requestClientIP(c.Request), c.GetHeader("Origin"), "origin not allowed")
+		c.JSON(http.StatusForbidden, gin.H{"error": "origin not allowed"})
+		return
 	}
+	upgrader := websocket.Upgrader{CheckOrigin: s.originAllowed}
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		return
 	}
 	log.Printf("ws connected home remote=%s", c.Request.RemoteAddr)
-	s.homeWS.Add(conn)
-	s.homeWS.Send(conn, htmlMessage("#activeGamesContent", "inner", s.renderHomeGamesHTML()))
-	go s.readHomeWS(conn)
+	client := newWSClient(conn, s.homeWS.timeouts)
+	s.homeWS.Add(client)
+	s.homeWS.Send(client, htmlMessage("#activeGamesContent", "inner", s.renderHomeGamesHTML()))
+	go s.readHomeWS(client)
 }
 
-func (s *Server) readWS(gameID string, conn *websocket.Conn, role string) {
-	defer s.ws.Remove(gameID, conn, role)
-	for {
-		if _, _, err := conn.ReadMessage(); err != nil {
-			log.Printf("ws disconnected game_id=%s error=%v", gameID, err)
+// wsClientMessage is a message from a game socket: activity, resync, a clock
+// ping, or a command named in wsCommands with a request id to acknowledge.
+type wsClientMessage struct {
+	Type      string          `json:"type"`
+	RequestID string          `json:"request_id"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func (s *Server) readWS(gameID string, client *wsClient, role string) {
+	err := client.readLoop(func(data []byte) {
+		received := serverTimeMillis()
+		var message wsClientMessage
+		parsed := json.Unmarshal(data, &message) == nil
+		// Clock pings are sent on a timer, so they say nothing about
+		// whether the player is still there.
+		if parsed && message.Type == "clock_ping" {
+			s.answerClockPing(client, message.Data, received)
+			return
+		}
+		if client.playerID > 0 && s.presence.Touch(gameID, client.playerID, time.Now().UTC()) {
+			s.broadcastPresence(gameID)
+		}
+		if !parsed {
+			return
+		}
+		if message.Type == "resync" && client.view != "" {
+			if game, ok := s.loadGame(gameID); ok {
+				s.syncClientState(game, client, "", 0)
+			}
 			return
 		}
+		if command, ok := wsCommands[message.Type]; ok {
+			s.handleWSCommand(gameID, client, command, message)
+		}
+	})
+	log.Printf("ws disconnected game_id=%s player_id=%d error=%v", gameID, client.playerID, err)
+	s.unsubscribeGameStream(gameID, client, role)
+}
+
+func (s *Server) unsubscribeGameStream(gameID string, client *wsClient, role string) {
+	s.ws.Remove(gameID, client, role)
+	if client.playerID > 0 && s.presence.Disconnect(gameID, client.playerID, time.Now().UTC()) {
+		s.broadcastPresence(gameID)
+	}
+	if !s.ws.HasSubscribers(gameID) {
+		s.streams.Forget(gameID)
 	}
 }
 
-func (s *Server) readHomeWS(conn *websocket.Conn) {
-	defer s.homeWS.Remove(conn)
-	for {
-		if _, _, err := conn.ReadMessage(); err != nil {
-			log.Printf("home ws disconnected error=%v", err)
-			return
-		}
+// broadcastPresence tells local clients that a player's presence changed.
+// Presence is tracked per node, so it is not published to the cluster.
+func (s *Server) broadcastPresence(gameID string) {
+	if game, ok := s.store.GetGame(gameID); ok {
+		s.broadcastLocalGameUpdate(game)
 	}
 }
 
+func (s *Server) readHomeWS(client *wsClient) {
+	defer s.homeWS.Remove(client)
+	err := client.readLoop(nil)
+	log.Printf("home ws disconnected error=%v", err)
+}
+
 func (s *Server) broadcastGameUpdate(game *Game) {
+	s.broadcastLocalGameUpdate(game)
+	s.publishGameUpdate(game)
+}
+
+func (s *Server) broadcastLocalGameUpdate(game *Game) {
 	if s.ws == nil {
 		return
 	}
-	s.ws.Broadcast(game.ID, stateChangedMessage{Type: "state_changed", Version: game.Version})
-	s.ws.BroadcastHTML(game.ID, s.renderGameHTMLMessages(game))
-	s.ws.BroadcastDisplay(game.ID, htmlMessage("#displayContent", "outer", s.renderDisplayHTML(game)))
+	s.syncHostSockets(game)
+	s.ws.Broadcast(game.ID, stateChangedMessage{Type: "state_changed", Version: game.Version, ServerTime: serverTimeMillis()})
+	s.publishState(game)
+	s.ws.BroadcastFragments(game.ID, wsRoleHost, s.renderGameHTMLMessages(game))
+	s.ws.BroadcastFragments(game.ID, wsRoleDisplay, []wsHTMLMessage{htmlMessage("#displayContent", "outer", s.renderDisplayHTML(game))})
 	s.broadcastHomeUpdate()
 }
 
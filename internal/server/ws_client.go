@@ -0,0 +1,155 @@
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsMaxMessageSize = 64 * 1024
+	// wsMaxCommandSize allows player sockets to send drawings.
+	wsMaxCommandSize = 1024 * 1024
+	wsSendQueue      = 32
+)
+
+// wsTimeouts are the keepalive settings for websocket clients. Peers must
+// answer a ping within pongWait or the connection is dropped.
+type wsTimeouts struct {
+	writeWait  time.Duration
+	pongWait   time.Duration
+	pingPeriod time.Duration
+	queue      int
+}
+
+var defaultWSTimeouts = wsTimeouts{
+	writeWait:  10 * time.Second,
+	pongWait:   60 * time.Second,
+	pingPeriod: 54 * time.Second,
+	queue:      wsSendQueue,
+}
+
+var (
+	// wsOpenClients counts connected websocket clients.
+	wsOpenClients atomic.Int64
+	// wsDroppedClients counts clients closed because their queue filled up.
+	wsDroppedClients atomic.Int64
+	// sseOpenClients counts connected server-sent event streams.
+	sseOpenClients atomic.Int64
+)
+
+// wsClient owns one websocket connection, or one server-sent event stream
+// when conn is nil. Messages are queued and written by a single writer
+// goroutine, which also sends keepalives, so a slow or stalled peer never
+// blocks the broadcaster or other clients.
+type wsClient struct {
+	conn      *websocket.Conn
+	send      chan []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+	timeouts  wsTimeouts
+
+	// playerID is the authenticated player on this connection, or zero.
+	playerID int
+	// authToken and clientIP are checked again for each command the
+	// player sends.
+	authToken string
+	clientIP  string
+	// view is the state view this client receives deltas for. Clients
+	// without one get state_changed notices and refetch over HTTP.
+	view string
+}
+
+func newWSClient(conn *websocket.Conn, timeouts wsTimeouts) *wsClient {
+	client := &wsClient{
+		conn:     conn,
+		send:     make(chan []byte, timeouts.queue),
+		closed:   make(chan struct{}),
+		timeouts: timeouts,
+	}
+	conn.SetReadLimit(wsMaxMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(timeouts.pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(timeouts.pongWait))
+	})
+	wsOpenClients.Add(1)
+	go client.writeLoop()
+	return client
+}
+
+// enqueue hands a message to the writer without blocking. A client whose
+// queue is full has fallen too far behind; it is closed instead of slowing
+// everyone else down, and will resync when it reconnects.
+func (c *wsClient) enqueue(data []byte) bool {
+	select {
+	case <-c.closed:
+		return false
+	default:
+	}
+	select {
+	case c.send <- data:
+		return true
+	default:
+		wsDroppedClients.Add(1)
+		c.Close()
+		return false
+	}
+}
+
+func (c *wsClient) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		if c.conn == nil {
+			sseOpenClients.Add(-1)
+			return
+		}
+		_ = c.conn.Close()
+		wsOpenClients.Add(-1)
+	})
+}
+
+// Done is closed once the client has been closed.
+func (c *wsClient) Done() <-chan struct{} {
+	return c.closed
+}
+
+func (c *wsClient) writeLoop() {
+	ticker := time.NewTicker(c.timeouts.pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.Close()
+	}()
+	for {
+		select {
+		case data := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(c.timeouts.writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.timeouts.writeWait)); err != nil {
+				return
+			}
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// readLoop consumes incoming frames until the peer goes away or misses a
+// pong. Any message from the peer also counts as a sign of life and is
+// passed to handle, which may be nil.
+func (c *wsClient) readLoop(handle func(data []byte)) error {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.timeouts.pongWait))
+		if handle != nil {
+			handle(data)
+		}
+	}
+}
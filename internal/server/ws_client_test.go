@@ -0,0 +1,115 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestWSHub serves a hub the way handleWebsocket does, minus the game
+// lookup, so tests can control the keepalive timings.
+func newTestWSHub(t *testing.T, timeouts wsTimeouts) (*wsHub, func() *websocket.Conn) {
+	t.Helper()
+	hub := newWSHub()
+	hub.timeouts = timeouts
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := newWSClient(conn, hub.timeouts)
+		hub.Add("game-1", client, wsRolePlayer)
+		go func() {
+			_ = client.readLoop(nil)
+			hub.Remove("game-1", client, wsRolePlayer)
+		}()
+	}))
+	t.Cleanup(server.Close)
+	dial := func() *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+		return conn
+	}
+	return hub, dial
+}
+
+func subscriberCount(hub *wsHub) int {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	return len(hub.groups["game-1"])
+}
+
+func waitFor(t *testing.T, timeout time.Duration, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWSBroadcastDropsStalledClient(t *testing.T) {
+	hub, dial := newTestWSHub(t, wsTimeouts{writeWait: 5 * time.Second, pongWait: time.Minute, pingPeriod: time.Minute, queue: 8})
+	dial() // never reads, so its socket buffers fill and its writer stalls
+	healthy := dial()
+	waitFor(t, time.Second, "both clients to register", func() bool { return subscriberCount(hub) == 2 })
+
+	const messages = 60
+	var received atomic.Int64
+	go func() {
+		for {
+			if _, _, err := healthy.ReadMessage(); err != nil {
+				return
+			}
+			received.Add(1)
+		}
+	}()
+
+	dropped := wsDroppedClients.Load()
+	payload := strings.Repeat("x", 256*1024)
+	for i := 0; i < messages; i++ {
+		started := time.Now()
+		hub.Broadcast("game-1", map[string]string{"type": "state_changed", "padding": payload})
+		if elapsed := time.Since(started); elapsed > 100*time.Millisecond {
+			t.Fatalf("broadcast %d blocked for %s", i, elapsed)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	waitFor(t, 2*time.Second, "the stalled client to be dropped", func() bool { return subscriberCount(hub) == 1 })
+	if wsDroppedClients.Load() == dropped {
+		t.Fatal("expected the stalled client to be dropped for a full queue")
+	}
+	waitFor(t, 2*time.Second, "the healthy client to get every message", func() bool { return received.Load() == messages })
+}
+
+func TestWSPingTimeoutReapsSilentPeer(t *testing.T) {
+	hub, dial := newTestWSHub(t, wsTimeouts{writeWait: time.Second, pongWait: 200 * time.Millisecond, pingPeriod: 50 * time.Millisecond, queue: 8})
+	// Pongs are only sent while the peer reads, so this one looks dead.
+	dial()
+	alive := dial()
+	go func() {
+		for {
+			if _, _, err := alive.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	waitFor(t, time.Second, "both clients to register", func() bool { return subscriberCount(hub) == 2 })
+	waitFor(t, 2*time.Second, "the silent client to be reaped", func() bool { return subscriberCount(hub) == 1 })
+
+	time.Sleep(600 * time.Millisecond)
+	if subscriberCount(hub) != 1 {
+		t.Fatal("expected the client answering pings to stay connected")
+	}
+}
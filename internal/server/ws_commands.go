@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// commandAckHistory is how many successful acks are kept so a command that
+// is resent after a reconnect is acknowledged again instead of re-run.
+const commandAckHistory = 512
+
+// wsCommandAck answers a websocket command. Status is the HTTP status the
+// same request would have got over REST.
+type wsCommandAck struct {
+	Type      string `json:"type"`
+	RequestID string `json:"request_id"`
+	OK        bool   `json:"ok"`
+	Status    int    `json:"status"`
+	Error     string `json:"error,omitempty"`
+	Version   int64  `json:"version,omitempty"`
+	Notice    string `json:"notice,omitempty"`
+}
+
+// wsCommand is a player action accepted over the game websocket. run goes
+// through the same function as the REST handler named by rate, which is
+// also the rate limit bucket it shares.
+type wsCommand struct {
+	rate string
+	run  func(s *Server, gameID string, client *wsClient, data json.RawMessage) (*Game, string, error)
+}
+
+var wsCommands = map[string]wsCommand{
+	"update_settings":   {rate: "settings", run: runCommand(settingsBinding, (*Server).updateSettings)},
+	"kick":              {rate: "kick", run: runCommand(kickBinding, (*Server).kickPlayer)},
+	"transfer_host":     {rate: "transfer_host", run: runCommand(transferHostBinding, (*Server).transferHost)},
+	"start_game":        {rate: "start", run: runCommand(startBinding, (*Server).startGame)},
+	"submit_drawing":    {rate: "drawings", run: runCommand(drawingsBinding, withBackground((*Server).submitDrawing))},
+	"submit_guess":      {rate: "guesses", run: runGuessCommand},
+	"submit_chain_link": {rate: "chain", run: runCommand(chainLinkBinding, withBackground((*Server).submitChainLink))},
+	"submit_vote":       {rate: "votes", run: runCommand(votesBinding, (*Server).submitVote)},
+	"like":              {run: runCommand(likesBinding, (*Server).likeGuess)},
+	"advance":           {rate: "advance", run: runCommand(advanceBinding, (*Server).advanceGame)},
+	"pause":             {rate: "pause", run: runCommand(pauseBinding, (*Server).pauseGame)},
+	"add_time":          {rate: "add_time", run: runCommand(addTimeBinding, (*Server).addPhaseTime)},
+	"resume":            {run: runCommand(resumeBinding, (*Server).resumeGame)},
+	"end_game":          {rate: "end", run: runCommand(endBinding, (*Server).endGame)},
+}
+
+func runCommand[T any](b requestBinding, run func(*Server, string, T) (*Game, error)) func(*Server, string, *wsClient, json.RawMessage) (*Game, string, error) {
+	return func(s *Server, gameID string, client *wsClient, data json.RawMessage) (*Game, string, error) {
+		var req T
+		if err := decodeCommand(data, client, &req, b); err != nil {
+			return nil, "", err
+		}
+		game, err := run(s, gameID, req)
+		return game, "", err
+	}
+}
+
+func withBackground[T any](run func(*Server, context.Context, string, T) (*Game, error)) func(*Server, string, T) (*Game, error) {
+	return func(s *Server, gameID string, req T) (*Game, error) {
+		return run(s, context.Background(), gameID, req)
+	}
+}
+
+func runGuessCommand(s *Server, gameID string, client *wsClient, data json.RawMessage) (*Game, string, error) {
+	var req guessesRequest
+	if err := decodeCommand(data, client, &req, guessesBinding); err != nil {
+		return nil, "", err
+	}
+	game, guessedTitle, err := s.submitGuess(gameID, req)
+	if guessedTitle {
+		return game, titleGuessNotice, err
+	}
+	return game, "", err
+}
+
+// decodeCommand decodes a command's data into req and validates it like the
+// REST handler would. The player is always the one the socket authenticated
+// as, whatever the data says.
+func decodeCommand(data json.RawMessage, client *wsClient, req any, b requestBinding) error {
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, req); err != nil {
+			return badCommand(resolveBindError(err, b.messages, b.fallback))
+		}
+	}
+	credentials, _ := json.Marshal(map[string]any{"player_id": client.playerID, "auth_token": client.authToken})
+	if err := json.Unmarshal(credentials, req); err != nil {
+		return badCommand(b.fallback)
+	}
+	return b.validate(req)
+}
+
+// handleWSCommand runs a command from a player's socket and acknowledges it.
+func (s *Server) handleWSCommand(gameID string, client *wsClient, command wsCommand, message wsClientMessage) {
+	key := gameID + "/" + strconv.Itoa(client.playerID) + "/" + message.RequestID
+	if message.RequestID != "" {
+		if ack, ok := s.acks.get(key); ok {
+			s.ws.Send(client, ack)
+			return
+		}
+	}
+	ack := wsCommandAck{Type: "ack", RequestID: message.RequestID, OK: true, Status: http.StatusOK}
+	game, notice, err := s.runWSCommand(gameID, client, command, message.Data)
+	if err != nil {
+		ack.OK, ack.Status, ack.Error = false, mutationErrorStatus(err), err.Error()
+		s.ws.Send(client, ack)
+		return
+	}
+	ack.Version, ack.Notice = game.Version, notice
+	if message.RequestID != "" {
+		s.acks.put(key, ack)
+	}
+	s.ws.Send(client, ack)
+}
+
+func (s *Server) runWSCommand(gameID string, client *wsClient, command wsCommand, data json.RawMessage) (*Game, string, error) {
+	if client.playerID <= 0 {
+		return nil, "", &commandError{status: http.StatusUnauthorized, message: "authentication required"}
+	}
+	if s.cluster != nil {
+		// Only the owner can change a game; other nodes just relay its
+		// updates, so the client must send the action over REST instead.
+		if _, ok := s.findGameInStore(gameID); !ok {
+			return nil, "", &commandError{status: http.StatusMisdirectedRequest, message: "game is hosted on another node"}
+		}
+	}
+	if command.rate != "" {
+		if allowed, _ := s.allowRequest(command.rate, client.clientIP); !allowed {
+			return nil, "", &commandError{status: http.StatusTooManyRequests, message: rateLimitMessage}
+		}
+	}
+	return command.run(s, gameID, client, data)
+}
+
+// commandAcks remembers recent successful acks by game, player and request
+// id, oldest first.
+type commandAcks struct {
+	mu    sync.Mutex
+	acks  map[string]wsCommandAck
+	order []string
+}
+
+func newCommandAcks() *commandAcks {
+	return &commandAcks{acks: make(map[string]wsCommandAck)}
+}
+
+func (a *commandAcks) get(key string) (wsCommandAck, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ack, ok := a.acks[key]
+	return ack, ok
+}
+
+func (a *commandAcks) put(key string, ack wsCommandAck) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.acks[key]; !ok {
+		a.order = append(a.order, key)
+	}
+	a.acks[key] = ack
+	for len(a.order) > commandAckHistory {
+		delete(a.acks, a.order[0])
+		a.order = a.order[1:]
+	}
+}
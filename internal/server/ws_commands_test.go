@@ -0,0 +1,91 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"picture-this/internal/config"
+
+	"github.com/gorilla/websocket"
+)
+
+func sendWSCommand(t *testing.T, conn *websocket.Conn, kind, requestID string, data any) wsCommandAck {
+	t.Helper()
+	encoded, _ := json.Marshal(data)
+	message, _ := json.Marshal(map[string]any{"type": kind, "request_id": requestID, "data": json.RawMessage(encoded)})
+	if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+		t.Fatalf("send %s: %v", kind, err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		_ = conn.SetReadDeadline(deadline)
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("waiting for ack of %s: %v", requestID, err)
+		}
+		var ack wsCommandAck
+		if json.Unmarshal(payload, &ack) == nil && ack.Type == "ack" && ack.RequestID == requestID {
+			return ack
+		}
+	}
+}
+
+func TestWebsocketCommandsShareRESTRules(t *testing.T) {
+	srv := New(nil, config.Default())
+	game := srv.store.CreateGame(1)
+	if _, err := srv.store.UpdateGame(game.ID, func(g *Game) error {
+		g.Players = []Player{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Ben"}}
+		g.HostID = 1
+		g.MinPlayers = 2
+		g.PlayerAuthTokens = map[int]string{1: "host-secret", 2: "ben-secret"}
+		return nil
+	}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	base := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/games/" + game.ID
+	dial := func(query string) *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial(base+query, nil)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	host := dial("?player_id=1&auth_token=host-secret")
+	player := dial("?player_id=2&auth_token=ben-secret")
+	watcher := dial("")
+
+	if ack := sendWSCommand(t, watcher, "advance", "w1", nil); ack.OK || ack.Status != http.StatusUnauthorized {
+		t.Fatalf("expected an anonymous socket to be refused, got %+v", ack)
+	}
+	if ack := sendWSCommand(t, player, "start_game", "p1", map[string]any{"player_id": 1, "auth_token": "host-secret"}); ack.OK || ack.Error != "only host can perform this action" {
+		t.Fatalf("expected the socket's own player to be used, got %+v", ack)
+	}
+	if ack := sendWSCommand(t, player, "submit_guess", "p2", map[string]any{"guess": ""}); ack.OK || ack.Status != http.StatusBadRequest || ack.Error != "guesses are required" {
+		t.Fatalf("expected the REST validation message, got %+v", ack)
+	}
+
+	started := sendWSCommand(t, host, "start_game", "h1", nil)
+	if !started.OK || started.Status != http.StatusOK {
+		t.Fatalf("expected start to succeed, got %+v", started)
+	}
+	current, _ := srv.store.GetGame(game.ID)
+	if current.Phase != phaseDrawings || started.Version != current.Version {
+		t.Fatalf("expected drawings at version %d, got %s at %d", started.Version, current.Phase, current.Version)
+	}
+	if again := sendWSCommand(t, host, "start_game", "h1", nil); again != started {
+		t.Fatalf("expected a resent command to get the same ack, got %+v", again)
+	}
+	if ack := sendWSCommand(t, host, "start_game", "h2", nil); ack.OK || ack.Status != http.StatusConflict || ack.Error != "game already started" {
+		t.Fatalf("expected a new start to conflict, got %+v", ack)
+	}
+	if after, _ := srv.store.GetGame(game.ID); after.Version != current.Version {
+		t.Fatalf("expected rejected commands to leave the game alone, version %d -> %d", current.Version, after.Version)
+	}
+}
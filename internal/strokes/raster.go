@@ -0,0 +1,144 @@
+package strokes
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+)
+
+const defaultBackground = "#ffffff"
+
+// Rasterize renders the document to a PNG for views that show drawings as
+// images.
+func Rasterize(doc Document) ([]byte, error) {
+	var out bytes.Buffer
+	if err := png.Encode(&out, Render(doc)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// Render paints the strokes onto a canvas the size of the document. Strokes
+// have round caps and joins, like the browser canvas they were drawn on.
+func Render(doc Document) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, doc.Width, doc.Height))
+	fill(img, mustColor(backgroundColor(doc)))
+	forEachSegment(doc, func(stroke Stroke, from, to Point) {
+		paint := mustColor(stroke.Color)
+		segmentSpans(img.Bounds(), from.X, from.Y, to.X, to.Y, stroke.Width/2, func(y, left, right int) {
+			for x := left; x <= right; x++ {
+				offset := img.PixOffset(x, y)
+				img.Pix[offset], img.Pix[offset+1], img.Pix[offset+2], img.Pix[offset+3] = paint.R, paint.G, paint.B, paint.A
+			}
+		})
+	})
+	return img
+}
+
+// forEachSegment walks the strokes in drawing order. A stroke with a single
+// point is a dot, visited as a segment from the point to itself.
+func forEachSegment(doc Document, visit func(stroke Stroke, from, to Point)) {
+	for _, stroke := range doc.Strokes {
+		if len(stroke.Points) == 1 {
+			visit(stroke, stroke.Points[0], stroke.Points[0])
+			continue
+		}
+		for i := 1; i < len(stroke.Points); i++ {
+			visit(stroke, stroke.Points[i-1], stroke.Points[i])
+		}
+	}
+}
+
+func backgroundColor(doc Document) string {
+	if doc.Background == "" {
+		return defaultBackground
+	}
+	return doc.Background
+}
+
+func mustColor(value string) color.RGBA {
+	rgb, err := parseColor(value)
+	if err != nil {
+		return color.RGBA{A: 0xff}
+	}
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xff}
+}
+
+func fill(img *image.RGBA, paint color.RGBA) {
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = paint.R, paint.G, paint.B, paint.A
+	}
+}
+
+// segmentSpans calls span with every run of pixels whose centres lie within
+// radius of the segment. The covered region is convex, so each row is a single
+// span: the union of the spans through the two end caps and the body between
+// them.
+func segmentSpans(bounds image.Rectangle, x0, y0, x1, y1, radius float64, span func(y, left, right int)) {
+	top := max(bounds.Min.Y, int(math.Floor(min(y0, y1)-radius)))
+	bottom := min(bounds.Max.Y-1, int(math.Ceil(max(y0, y1)+radius)))
+	dx, dy := x1-x0, y1-y0
+	length := math.Hypot(dx, dy)
+	for y := top; y <= bottom; y++ {
+		cy := float64(y) + 0.5
+		lo, hi := math.Inf(1), math.Inf(-1)
+		widen := func(a, b float64) {
+			if a <= b {
+				lo, hi = min(lo, a), max(hi, b)
+			}
+		}
+		widen(capSpan(x0, y0, cy, radius))
+		widen(capSpan(x1, y1, cy, radius))
+		if length > 0 {
+			widen(bodySpan(x0, y0, dx/length, dy/length, length, cy, radius))
+		}
+		if lo > hi {
+			continue
+		}
+		left := max(bounds.Min.X, int(math.Ceil(lo-0.5)))
+		right := min(bounds.Max.X-1, int(math.Floor(hi-0.5)))
+		if left <= right {
+			span(y, left, right)
+		}
+	}
+}
+
+func capSpan(cx, cy, y, radius float64) (float64, float64) {
+	dy := y - cy
+	if math.Abs(dy) > radius {
+		return 1, 0
+	}
+	half := math.Sqrt(radius*radius - dy*dy)
+	return cx - half, cx + half
+}
+
+// bodySpan is the part of row y within radius of the segment's interior:
+// the points whose projection onto the direction (ux, uy) falls within the
+// segment and whose distance from the line is at most radius.
+func bodySpan(x0, y0, ux, uy, length, y, radius float64) (float64, float64) {
+	lo, hi := math.Inf(-1), math.Inf(1)
+	ry := y - y0
+	// Along the segment: 0 <= ux*(x-x0) + uy*ry <= length.
+	lo, hi = clampLinear(lo, hi, ux, x0, uy*ry, 0, length)
+	// Across the segment: -radius <= -uy*(x-x0) + ux*ry <= radius.
+	lo, hi = clampLinear(lo, hi, -uy, x0, ux*ry, -radius, radius)
+	return lo, hi
+}
+
+// clampLinear narrows [lo, hi] to the x where a*(x-x0)+b lies in [min, max].
+func clampLinear(lo, hi, a, x0, b, minValue, maxValue float64) (float64, float64) {
+	if math.Abs(a) < 1e-12 {
+		if b < minValue || b > maxValue {
+			return 1, 0
+		}
+		return lo, hi
+	}
+	first := x0 + (minValue-b)/a
+	second := x0 + (maxValue-b)/a
+	if first > second {
+		first, second = second, first
+	}
+	return max(lo, first), min(hi, second)
+}
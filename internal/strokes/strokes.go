@@ -0,0 +1,168 @@
+// Package strokes is the vector drawing format submitted by the player canvas:
+// a canvas size, a background and the strokes in the order they were drawn,
+// each point stamped with milliseconds since the drawing started.
+package strokes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	Version = 1
+
+	MaxDocumentBytes = 512 * 1024
+	MaxDimension     = 1600
+	MaxStrokes       = 2000
+	MaxPoints        = 20000
+	MaxStrokeWidth   = 64
+	MaxDurationMS    = 60 * 60 * 1000
+
+	// maxInkRatio bounds how many times over the canvas area the strokes may
+	// paint, which caps rasterization cost for pathological documents.
+	maxInkRatio = 40
+)
+
+type Document struct {
+	Version    int      `json:"version"`
+	Width      int      `json:"width"`
+	Height     int      `json:"height"`
+	Background string   `json:"background,omitempty"`
+	Strokes    []Stroke `json:"strokes"`
+}
+
+type Stroke struct {
+	Color  string  `json:"color"`
+	Width  float64 `json:"width"`
+	Points []Point `json:"points"`
+}
+
+// Point is encoded as a compact [x, y, t] array.
+type Point struct {
+	X float64
+	Y float64
+	T int
+}
+
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal([3]float64{round2(p.X), round2(p.Y), float64(p.T)})
+}
+
+func (p *Point) UnmarshalJSON(data []byte) error {
+	var values []float64
+	if err := json.Unmarshal(data, &values); err != nil {
+		return errors.New("points must be [x, y, t] arrays")
+	}
+	if len(values) != 3 {
+		return errors.New("points must be [x, y, t] arrays")
+	}
+	if values[2] != math.Trunc(values[2]) {
+		return errors.New("point times must be whole milliseconds")
+	}
+	p.X, p.Y, p.T = values[0], values[1], int(values[2])
+	return nil
+}
+
+// Parse decodes and validates a stroke document.
+func Parse(data []byte) (Document, error) {
+	if len(data) > MaxDocumentBytes {
+		return Document{}, errors.New("drawing exceeds size limit")
+	}
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Document{}, fmt.Errorf("invalid stroke data: %w", err)
+	}
+	if err := doc.Validate(); err != nil {
+		return Document{}, err
+	}
+	return doc, nil
+}
+
+func (d Document) Validate() error {
+	if d.Version != Version {
+		return errors.New("unsupported stroke data version")
+	}
+	if d.Width < 1 || d.Height < 1 || d.Width > MaxDimension || d.Height > MaxDimension {
+		return fmt.Errorf("canvas must be between 1 and %d pixels on each side", MaxDimension)
+	}
+	if d.Background != "" {
+		if _, err := parseColor(d.Background); err != nil {
+			return err
+		}
+	}
+	if len(d.Strokes) > MaxStrokes {
+		return errors.New("drawing has too many strokes")
+	}
+	points := 0
+	lastT := 0
+	ink := 0.0
+	for _, stroke := range d.Strokes {
+		if _, err := parseColor(stroke.Color); err != nil {
+			return err
+		}
+		if stroke.Width < 1 || stroke.Width > MaxStrokeWidth {
+			return fmt.Errorf("stroke width must be between 1 and %d", MaxStrokeWidth)
+		}
+		if len(stroke.Points) == 0 {
+			return errors.New("strokes need at least one point")
+		}
+		points += len(stroke.Points)
+		if points > MaxPoints {
+			return errors.New("drawing has too many points")
+		}
+		for i, point := range stroke.Points {
+			if point.X < 0 || point.Y < 0 || point.X > float64(d.Width) || point.Y > float64(d.Height) {
+				return errors.New("stroke point outside the canvas")
+			}
+			if point.T < lastT || point.T > MaxDurationMS {
+				return errors.New("stroke times must not go backwards")
+			}
+			lastT = point.T
+			if i > 0 {
+				previous := stroke.Points[i-1]
+				ink += math.Hypot(point.X-previous.X, point.Y-previous.Y) * stroke.Width
+			}
+		}
+		ink += stroke.Width * stroke.Width
+	}
+	if ink > float64(maxInkRatio*d.Width*d.Height) {
+		return errors.New("drawing is too complex")
+	}
+	return nil
+}
+
+// Duration is the time from the first point to the last, in milliseconds.
+func (d Document) Duration() int {
+	first, last := -1, 0
+	for _, stroke := range d.Strokes {
+		for _, point := range stroke.Points {
+			if first < 0 {
+				first = point.T
+			}
+			last = point.T
+		}
+	}
+	if first < 0 {
+		return 0
+	}
+	return last - first
+}
+
+func parseColor(value string) ([3]uint8, error) {
+	if len(value) != 7 || !strings.HasPrefix(value, "#") {
+		return [3]uint8{}, errors.New("colors must be #rrggbb")
+	}
+	parsed, err := strconv.ParseUint(value[1:], 16, 32)
+	if err != nil {
+		return [3]uint8{}, errors.New("colors must be #rrggbb")
+	}
+	return [3]uint8{uint8(parsed >> 16), uint8(parsed >> 8), uint8(parsed)}, nil
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,82 @@
+package strokes
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+const sample = `{"version":1,"width":40,"height":30,"strokes":[
+	{"color":"#ff0000","width":4,"points":[[5,15,0],[35,15,120]]},
+	{"color":"#0000ff","width":6,"points":[[20,5,300]]}
+]}`
+
+func TestParseRoundTrips(t *testing.T) {
+	doc, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(doc.Strokes) != 2 || doc.Strokes[0].Points[1] != (Point{X: 35, Y: 15, T: 120}) {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+	if doc.Duration() != 300 {
+		t.Fatalf("expected a 300ms drawing, got %d", doc.Duration())
+	}
+}
+
+func TestParseRejectsInvalidDocuments(t *testing.T) {
+	cases := map[string]string{
+		"version":     `{"version":2,"width":10,"height":10,"strokes":[]}`,
+		"dimensions":  `{"version":1,"width":5000,"height":10,"strokes":[]}`,
+		"color":       `{"version":1,"width":10,"height":10,"strokes":[{"color":"red","width":2,"points":[[1,1,0]]}]}`,
+		"width":       `{"version":1,"width":10,"height":10,"strokes":[{"color":"#000000","width":100,"points":[[1,1,0]]}]}`,
+		"empty":       `{"version":1,"width":10,"height":10,"strokes":[{"color":"#000000","width":2,"points":[]}]}`,
+		"out of view": `{"version":1,"width":10,"height":10,"strokes":[{"color":"#000000","width":2,"points":[[11,1,0]]}]}`,
+		"backwards":   `{"version":1,"width":10,"height":10,"strokes":[{"color":"#000000","width":2,"points":[[1,1,50],[2,2,10]]}]}`,
+		"point shape": `{"version":1,"width":10,"height":10,"strokes":[{"color":"#000000","width":2,"points":[[1,1]]}]}`,
+		"too complex": `{"version":1,"width":10,"height":10,"strokes":[{"color":"#000000","width":64,"points":[[0,0,0],[10,10,1],[0,0,2],[10,10,3]]}]}`,
+	}
+	for name, data := range cases {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := Parse([]byte(strings.Repeat(" ", MaxDocumentBytes+1))); err == nil {
+		t.Error("expected oversized documents to be rejected")
+	}
+}
+
+func TestRasterizePaintsStrokes(t *testing.T) {
+	doc, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	data, err := Rasterize(doc)
+	if err != nil {
+		t.Fatalf("rasterize: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if img.Bounds().Dx() != 40 || img.Bounds().Dy() != 30 {
+		t.Fatalf("unexpected size %v", img.Bounds())
+	}
+	checks := []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{20, 15, color.RGBA{R: 0xff, A: 0xff}},
+		{5, 15, color.RGBA{R: 0xff, A: 0xff}},
+		{20, 5, color.RGBA{B: 0xff, A: 0xff}},
+		{20, 25, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}},
+		{0, 0, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}},
+	}
+	for _, check := range checks {
+		if got := color.RGBAModel.Convert(img.At(check.x, check.y)); got != check.want {
+			t.Errorf("pixel (%d,%d): got %v, want %v", check.x, check.y, got, check.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package strokes
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+)
+
+const (
+	// timeLapseFrames is how many steps the drawing is split into. Steps in
+	// which nothing was drawn are merged into the frame before them.
+	timeLapseFrames = 48
+	// timeLapsePlaybackMS caps how long the replay runs; slower drawings are
+	// sped up to fit while quicker ones play in real time.
+	timeLapsePlaybackMS = 6000
+	// timeLapseHoldMS keeps the finished picture on screen before looping.
+	timeLapseHoldMS = 2000
+	// minFrameDelay is the shortest delay, in hundredths of a second, that
+	// browsers honour.
+	minFrameDelay = 2
+)
+
+// TimeLapse encodes an animated GIF that replays the drawing stroke by stroke.
+// The first frame is the blank canvas and every later frame only carries the
+// rectangle painted since the one before it.
+func TimeLapse(doc Document) ([]byte, error) {
+	colors, indexOf := timeLapsePalette(doc)
+	bounds := image.Rect(0, 0, doc.Width, doc.Height)
+	canvas := image.NewPaletted(bounds, colors)
+	background := indexOf(mustColor(backgroundColor(doc)))
+	for i := range canvas.Pix {
+		canvas.Pix[i] = background
+	}
+
+	type segment struct {
+		stroke   Stroke
+		from, to Point
+		at       int
+	}
+	var segments []segment
+	forEachSegment(doc, func(stroke Stroke, from, to Point) {
+		segments = append(segments, segment{stroke: stroke, from: from, to: to, at: to.T})
+	})
+	total := doc.Duration()
+	if total == 0 {
+		// Without timing, replay the segments at an even pace.
+		for i := range segments {
+			segments[i].at = i + 1
+		}
+		total = len(segments)
+	} else if len(segments) > 0 {
+		start := segments[0].from.T
+		for i := range segments {
+			segments[i].at -= start
+		}
+	}
+
+	step := max(minFrameDelay, min(total, timeLapsePlaybackMS)/timeLapseFrames/10)
+	anim := &gif.GIF{Config: image.Config{ColorModel: colors, Width: doc.Width, Height: doc.Height}}
+	addFrame := func(rect image.Rectangle) {
+		if rect.Empty() && len(anim.Image) > 0 {
+			anim.Delay[len(anim.Delay)-1] += step
+			return
+		}
+		frame := image.NewPaletted(rect, colors)
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			copy(frame.Pix[frame.PixOffset(rect.Min.X, y):frame.PixOffset(rect.Max.X, y)], canvas.Pix[canvas.PixOffset(rect.Min.X, y):canvas.PixOffset(rect.Max.X, y)])
+		}
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, step)
+		anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+	}
+	addFrame(bounds)
+
+	dirty := image.Rectangle{}
+	next := 1
+	for _, seg := range segments {
+		for next < timeLapseFrames && seg.at > total*next/timeLapseFrames {
+			addFrame(dirty)
+			dirty = image.Rectangle{}
+			next++
+		}
+		paint := indexOf(mustColor(seg.stroke.Color))
+		segmentSpans(bounds, seg.from.X, seg.from.Y, seg.to.X, seg.to.Y, seg.stroke.Width/2, func(y, left, right int) {
+			row := canvas.PixOffset(0, y)
+			for x := left; x <= right; x++ {
+				canvas.Pix[row+x] = paint
+			}
+			dirty = dirty.Union(image.Rect(left, y, right+1, y+1))
+		})
+	}
+	if !dirty.Empty() {
+		addFrame(dirty)
+	}
+	anim.Delay[len(anim.Delay)-1] += timeLapseHoldMS / 10
+
+	var out bytes.Buffer
+	if err := gif.EncodeAll(&out, anim); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// timeLapsePalette uses the document's own colours when they fit in a GIF
+// palette, so the replay matches the final PNG exactly, and falls back to the
+// Plan 9 palette otherwise.
+func timeLapsePalette(doc Document) (color.Palette, func(color.RGBA) uint8) {
+	seen := map[color.RGBA]uint8{}
+	colors := color.Palette{}
+	add := func(value string) {
+		paint := mustColor(value)
+		if _, ok := seen[paint]; ok || len(colors) > 256 {
+			return
+		}
+		seen[paint] = uint8(len(colors))
+		colors = append(colors, paint)
+	}
+	add(backgroundColor(doc))
+	for _, stroke := range doc.Strokes {
+		add(stroke.Color)
+	}
+	if len(colors) > 256 {
+		plan9 := color.Palette(palette.Plan9)
+		return plan9, func(paint color.RGBA) uint8 { return uint8(plan9.Index(paint)) }
+	}
+	return colors, func(paint color.RGBA) uint8 { return seen[paint] }
+}
@@ -0,0 +1,60 @@
+package strokes
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"testing"
+)
+
+func TestTimeLapseEndsOnTheFinishedDrawing(t *testing.T) {
+	doc, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	data, err := TimeLapse(doc)
+	if err != nil {
+		t.Fatalf("time lapse: %v", err)
+	}
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(anim.Image) < 3 || anim.LoopCount != 0 {
+		t.Fatalf("expected a looping animation, got %d frames", len(anim.Image))
+	}
+	if anim.Image[0].Bounds() != image.Rect(0, 0, 40, 30) {
+		t.Fatalf("expected the first frame to be the blank canvas, got %v", anim.Image[0].Bounds())
+	}
+	if last := anim.Delay[len(anim.Delay)-1]; last < timeLapseHoldMS/10 {
+		t.Fatalf("expected the final frame to hold, got %d", last)
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, 40, 30))
+	for i, frame := range anim.Image {
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Src)
+		if i == 0 && color.RGBAModel.Convert(canvas.At(20, 15)) != (color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}) {
+			t.Fatal("expected the first frame to be blank")
+		}
+	}
+	if !bytes.Equal(canvas.Pix, Render(doc).Pix) {
+		t.Fatal("expected the last frame to match the rendered drawing")
+	}
+}
+
+func TestTimeLapseWithoutTiming(t *testing.T) {
+	doc := Document{Version: Version, Width: 10, Height: 10, Strokes: []Stroke{
+		{Color: "#000000", Width: 2, Points: []Point{{X: 2, Y: 2}}},
+		{Color: "#000000", Width: 2, Points: []Point{{X: 8, Y: 8}}},
+	}}
+	data, err := TimeLapse(doc)
+	if err != nil {
+		t.Fatalf("time lapse: %v", err)
+	}
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil || len(anim.Image) != 3 {
+		t.Fatalf("expected one frame per stroke after the blank canvas, got %v", err)
+	}
+}
@@ -12,6 +12,14 @@ func formatTime(value time.Time) string {
 	return value.Format("2006-01-02 15:04:05")
 }
 
+// imageSource prefers the blob store URL for images that have moved there.
+func imageSource(image []byte, hash string) string {
+	if hash != "" {
+		return "/blobs/" + hash
+	}
+	return encodeImageData(image)
+}
+
 func encodeImageData(image []byte) string {
 	if len(image) == 0 {
 		return ""
@@ -41,7 +41,7 @@ func AudienceView(gameID string, playerName string) templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<header class=\"hero\"><span class=\"tag\">Audience View</span><h1>Watch and vote</h1><p>Join the crowd and pick the real prompt each round.</p></header><section class=\"panel panel--split\"><div><h2>Game status</h2><p id=\"audienceJoinCode\" class=\"join-code\">Loading...</p></div><div class=\"status\"><span class=\"label\">Phase</span><p id=\"audiencePhase\" role=\"status\" aria-live=\"polite\">Loading...</p><span class=\"label\">Time left</span><p id=\"audienceTimer\" class=\"display-timer\">--:--</p></div></section><section id=\"audienceJoinPanel\" class=\"panel panel--stack\"><div><h2>Join audience</h2><p>Pick a display name to submit audience votes.</p></div><form id=\"audienceJoinForm\" class=\"join-form join-only\"><label class=\"field\"><span class=\"label\">Display name</span> ")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<header class=\"hero\"><span class=\"tag\">Audience View</span><h1>Watch and vote</h1><p>Join the crowd and pick the real prompt each round.</p></header><section class=\"panel panel--split\"><div><h2>Game status</h2><p id=\"audienceJoinCode\" class=\"join-code\">Loading...</p></div><div class=\"status\"><span class=\"label\">Phase</span><p id=\"audiencePhase\" role=\"status\" aria-live=\"polite\">Loading...</p><span class=\"label\">Host</span><p id=\"audienceHost\">Loading...</p><span class=\"label\">Time left</span><p id=\"audienceTimer\" class=\"display-timer\">--:--</p></div></section><section id=\"audienceJoinPanel\" class=\"panel panel--stack\"><div><h2>Join audience</h2><p>Pick a display name to submit audience votes.</p></div><form id=\"audienceJoinForm\" class=\"join-form join-only\"><label class=\"field\"><span class=\"label\">Display name</span> ")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -53,7 +53,7 @@ func AudienceView(gameID string, playerName string) templ.Component {
 				var templ_7745c5c3_Var3 string
 				templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(playerName)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/audience.templ`, Line: 33, Col: 108}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/audience.templ`, Line: 35, Col: 108}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 				if templ_7745c5c3_Err != nil {
@@ -76,7 +76,7 @@ func AudienceView(gameID string, playerName string) templ.Component {
 			var templ_7745c5c3_Var4 string
 			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(gameID)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/audience.templ`, Line: 58, Col: 46}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/audience.templ`, Line: 60, Col: 46}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 			if templ_7745c5c3_Err != nil {
@@ -34,6 +34,17 @@ func playerNamePtr(playerNames map[uint]string, playerID *uint) string {
 	return playerName(playerNames, *playerID)
 }
 
+// presenceLabel is the badge shown next to a player who is not playing along.
+func presenceLabel(presence string) string {
+	switch presence {
+	case "idle":
+		return "idle"
+	case "gone":
+		return "away"
+	}
+	return ""
+}
+
 func pageURL(base string, page, perPage int) string {
 	if strings.Contains(base, "?") {
 		return base + "&page=" + itoa(page) + "&per_page=" + itoa(perPage)
@@ -54,14 +54,14 @@ func PlayerView(gameID string, playerID int, playerName string) templ.Component
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</strong>.</p></div><ul id=\"playerList\" class=\"player-list\"></ul></section><section id=\"hostSection\" class=\"panel panel--stack host-panel is-hidden\"><div><h2>Host controls</h2><p id=\"hostHelp\" class=\"hint\">Only the host can control game flow.</p><p id=\"hostLobbyStatus\" class=\"hint\"></p></div><div class=\"canvas-actions\"><button type=\"button\" id=\"hostStartGame\" class=\"primary\">Start game</button> <button type=\"button\" id=\"hostAdvanceGame\" class=\"secondary\">Advance</button> <button type=\"button\" id=\"hostEndGame\" class=\"secondary\">End game</button></div><form id=\"hostSettingsForm\" class=\"settings-form\"><label><span class=\"label\">Rounds</span> <input id=\"hostRoundsInput\" name=\"rounds\" type=\"number\" min=\"1\" max=\"10\" value=\"2\" required></label> <label class=\"checkbox\"><input id=\"hostLobbyLocked\" name=\"lobby_locked\" type=\"checkbox\"> <span>Lock lobby to new players</span></label> <details><summary>Picture This extensions</summary> <label class=\"checkbox\"><input id=\"hostAvatarsEnabled\" type=\"checkbox\"><span>Lobby avatars</span></label> <label class=\"checkbox\"><input id=\"hostAudienceEnabled\" type=\"checkbox\"><span>Audience voting</span></label> <label class=\"checkbox\"><input id=\"hostJokesEnabled\" type=\"checkbox\"><span>Narrated jokes</span></label> <label class=\"checkbox\"><input id=\"hostPublicReplay\" type=\"checkbox\"><span>Public replay</span></label></details><div class=\"settings-actions\"><button type=\"submit\" class=\"secondary\">Save settings</button> <span id=\"hostSettingsStatus\" class=\"result\" role=\"status\" aria-live=\"polite\"></span></div></form><div><h3>Players</h3><div id=\"hostPlayerActions\" class=\"player-actions\"></div></div></section><section id=\"avatarSection\" class=\"panel panel--stack avatar-panel\"><div><h2>Lobby portrait</h2><p>Draw a quick avatar to represent you while everyone joins. Saving locks it for this game.</p><p id=\"avatarLockedHint\" class=\"hint is-hidden\">Avatar saved and locked for this game.</p></div><div id=\"avatarCanvasWrap\" class=\"canvas-wrap\"><canvas id=\"avatarCanvas\" class=\"avatar-canvas media-frame\" width=\"800\" height=\"600\" aria-label=\"Avatar canvas\"></canvas><div class=\"canvas-actions\"><button type=\"button\" id=\"saveAvatar\" class=\"secondary\">Save avatar</button></div></div></section><section id=\"scoreboardSection\" class=\"panel panel--stack scoreboard-panel\"><div><h2>Scoreboard</h2><p id=\"scoreboardStatus\">Round update pending.</p></div><div id=\"scoreboardList\" class=\"results-scores\"></div></section><section id=\"drawSection\" class=\"panel panel--stack draw-panel\"><div><h2>Draw your prompt</h2><p>Use your finger or mouse to sketch. Resolution is fixed for fair play.</p></div><div class=\"prompt-card card-surface\"><span class=\"label\">Your prompt</span><p id=\"promptText\" class=\"prompt-text\">Loading...</p></div><div class=\"canvas-wrap\"><canvas id=\"drawCanvas\" class=\"media-frame\" width=\"800\" height=\"600\" aria-label=\"Drawing canvas\"></canvas><div class=\"canvas-actions\"><button type=\"button\" id=\"saveCanvas\" class=\"primary\">Save drawing</button></div></div></section><section id=\"guessSection\" class=\"panel panel--stack guess-panel\"><div><h2>Guess the prompt</h2><p id=\"guessStatus\" role=\"status\" aria-live=\"polite\">Waiting for your turn to guess.</p></div><div class=\"guess-card\"><img id=\"guessImage\" class=\"guess-image media-frame\" alt=\"Drawing to guess\"><form id=\"guessForm\" class=\"guess-form\"><label class=\"field\"><span class=\"label\">Your guess</span> <input id=\"guessInput\" name=\"guess\" placeholder=\"Type your guess\" autocomplete=\"off\" required></label> <button type=\"submit\" class=\"primary\">Submit guess</button></form></div></section><section id=\"voteSection\" class=\"panel panel--stack vote-panel\"><div><h2>Pick the real prompt</h2><p id=\"voteStatus\" role=\"status\" aria-live=\"polite\">Waiting for your turn to vote.</p></div><div class=\"vote-card\"><img id=\"voteImage\" class=\"guess-image media-frame\" alt=\"Drawing to vote on\"><form id=\"voteForm\" class=\"vote-form\"><div id=\"voteOptions\" class=\"vote-options\"></div><button type=\"submit\" class=\"primary\">Submit vote</button></form></div></section><section id=\"resultsSection\" class=\"panel panel--stack results-panel\"><div><h2>Results</h2><p>See who guessed what and which prompts won the vote.</p></div><div id=\"revealSection\" class=\"reveal-card\"></div><div id=\"resultsScores\" class=\"results-scores\"></div><div id=\"resultsList\" class=\"results-list\"></div></section><p id=\"playerError\" class=\"result error\" role=\"alert\"></p><audio id=\"avatarSavedSound\" src=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</strong>.</p></div><ul id=\"playerList\" class=\"player-list\"></ul></section><section id=\"hostSection\" class=\"panel panel--stack host-panel is-hidden\"><div><h2>Host controls</h2><p id=\"hostHelp\" class=\"hint\">Only the host can control game flow.</p><p id=\"hostLobbyStatus\" class=\"hint\"></p><p id=\"hostPresenceAlert\" class=\"hint\" role=\"status\"></p></div><div class=\"canvas-actions\"><button type=\"button\" id=\"hostStartGame\" class=\"primary\">Start game</button> <button type=\"button\" id=\"hostAdvanceGame\" class=\"secondary\">Advance</button> <button type=\"button\" id=\"hostPauseGame\" class=\"secondary\">Pause</button> <button type=\"button\" id=\"hostAddTime\" class=\"secondary\">+30s</button> <button type=\"button\" id=\"hostEndGame\" class=\"secondary\">End game</button> <a id=\"hostDisplayLink\" class=\"secondary\" target=\"_blank\" rel=\"noopener\">Open big screen</a></div><form id=\"hostSettingsForm\" class=\"settings-form\"><label><span class=\"label\">Rounds</span> <input id=\"hostRoundsInput\" name=\"rounds\" type=\"number\" min=\"1\" max=\"10\" value=\"2\" required></label> <label class=\"checkbox\"><input id=\"hostLobbyLocked\" name=\"lobby_locked\" type=\"checkbox\"> <span>Lock lobby to new players</span></label> <label><span class=\"label\">Ruleset</span> <select id=\"hostRulesetSelect\" name=\"ruleset\"><option value=\"drawful_v1\">Classic</option> <option value=\"teams_v1\">Teams</option> <option value=\"telephone_v1\">Telephone</option> <option value=\"picture_this_v1\">Picture This (legacy)</option></select></label> <label><span class=\"label\">Timers</span> <select id=\"hostTimerPreset\" name=\"timer_preset\"><option value=\"family\">Family (relaxed)</option> <option value=\"standard\">Standard</option> <option value=\"speed\">Speed</option> <option value=\"custom\" disabled>Custom</option></select></label> <label id=\"hostTeamCountLabel\"><span class=\"label\">Teams</span> <input id=\"hostTeamCountInput\" name=\"team_count\" type=\"number\" min=\"2\" max=\"4\" value=\"2\"></label> <details id=\"hostScoringRules\"><summary>Scoring</summary> <label><span class=\"label\">Correct guess</span><input data-scoring-rule=\"correct_guess\" type=\"number\" min=\"0\" max=\"10000\" step=\"50\" value=\"1000\"></label> <label><span class=\"label\">Per fooled player</span><input data-scoring-rule=\"fooled_player\" type=\"number\" min=\"0\" max=\"10000\" step=\"50\" value=\"500\"></label> <label><span class=\"label\">Artist bonus</span><input data-scoring-rule=\"artist_bonus\" type=\"number\" min=\"0\" max=\"10000\" step=\"50\" value=\"500\"></label> <label><span class=\"label\">Final round multiplier</span><input data-scoring-rule=\"final_round_multiplier\" type=\"number\" min=\"1\" max=\"5\" value=\"1\"></label> <label><span class=\"label\">Per like received</span><input data-scoring-rule=\"like_points\" type=\"number\" min=\"0\" max=\"10000\" step=\"50\" value=\"0\"></label> <label><span class=\"label\">Guessing the real title</span><input data-scoring-rule=\"title_guess_bonus\" type=\"number\" min=\"0\" max=\"10000\" step=\"50\" value=\"250\"></label></details> <details><summary>Picture This extensions</summary> <label class=\"checkbox\"><input id=\"hostAvatarsEnabled\" type=\"checkbox\"><span>Lobby avatars</span></label> <label class=\"checkbox\"><input id=\"hostAudienceEnabled\" type=\"checkbox\"><span>Audience voting</span></label> <label class=\"checkbox\"><input id=\"hostJokesEnabled\" type=\"checkbox\"><span>Narrated jokes</span></label> <label class=\"checkbox\"><input id=\"hostPublicReplay\" type=\"checkbox\"><span>Public replay</span></label></details><div class=\"settings-actions\"><button type=\"submit\" class=\"secondary\">Save settings</button> <span id=\"hostSettingsStatus\" class=\"result\" role=\"status\" aria-live=\"polite\"></span></div></form><div><h3>Players</h3><div id=\"hostPlayerActions\" class=\"player-actions\"></div></div></section><section id=\"avatarSection\" class=\"panel panel--stack avatar-panel\"><div><h2>Lobby portrait</h2><p>Draw a quick avatar to represent you while everyone joins. Saving locks it for this game.</p><p id=\"avatarLockedHint\" class=\"hint is-hidden\">Avatar saved and locked for this game.</p></div><div id=\"avatarCanvasWrap\" class=\"canvas-wrap\"><canvas id=\"avatarCanvas\" class=\"avatar-canvas media-frame\" width=\"800\" height=\"600\" aria-label=\"Avatar canvas\"></canvas><div class=\"canvas-actions\"><button type=\"button\" id=\"saveAvatar\" class=\"secondary\">Save avatar</button></div></div></section><section id=\"scoreboardSection\" class=\"panel panel--stack scoreboard-panel\"><div><h2>Scoreboard</h2><p id=\"scoreboardStatus\">Round update pending.</p></div><div id=\"scoreboardList\" class=\"results-scores\"></div></section><section id=\"drawSection\" class=\"panel panel--stack draw-panel\"><div><h2>Draw your prompt</h2><p>Use your finger or mouse to sketch. Resolution is fixed for fair play.</p></div><div class=\"prompt-card card-surface\"><span class=\"label\">Your prompt</span><p id=\"promptText\" class=\"prompt-text\">Loading...</p></div><div class=\"canvas-wrap\"><canvas id=\"drawCanvas\" class=\"media-frame\" width=\"800\" height=\"600\" aria-label=\"Drawing canvas\"></canvas><div class=\"canvas-actions\"><button type=\"button\" id=\"saveCanvas\" class=\"primary\">Save drawing</button></div></div></section><section id=\"guessSection\" class=\"panel panel--stack guess-panel\"><div><h2>Guess the prompt</h2><p id=\"guessStatus\" role=\"status\" aria-live=\"polite\">Waiting for your turn to guess.</p></div><div class=\"guess-card\"><img id=\"guessImage\" class=\"guess-image media-frame\" alt=\"Drawing to guess\"><form id=\"guessForm\" class=\"guess-form\"><label class=\"field\"><span class=\"label\">Your guess</span> <input id=\"guessInput\" name=\"guess\" placeholder=\"Type your guess\" autocomplete=\"off\" required></label> <button type=\"submit\" class=\"primary\">Submit guess</button></form></div></section><section id=\"voteSection\" class=\"panel panel--stack vote-panel\"><div><h2>Pick the real prompt</h2><p id=\"voteStatus\" role=\"status\" aria-live=\"polite\">Waiting for your turn to vote.</p></div><div class=\"vote-card\"><img id=\"voteImage\" class=\"guess-image media-frame\" alt=\"Drawing to vote on\"><form id=\"voteForm\" class=\"vote-form\"><div id=\"voteOptions\" class=\"vote-options\"></div><button type=\"submit\" class=\"primary\">Submit vote</button></form></div></section><section id=\"resultsSection\" class=\"panel panel--stack results-panel\"><div><h2>Results</h2><p>See who guessed what and which prompts won the vote.</p></div><div id=\"revealSection\" class=\"reveal-card\"></div><div id=\"resultsScores\" class=\"results-scores\"></div><div id=\"resultsList\" class=\"results-list\"></div></section><p id=\"playerError\" class=\"result error\" role=\"alert\"></p><audio id=\"avatarSavedSound\" src=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			var templ_7745c5c3_Var4 string
 			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(assetPath("/static/sounds/join.ogg"))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/player.templ`, Line: 157, Col: 73}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/player.templ`, Line: 192, Col: 73}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 			if templ_7745c5c3_Err != nil {
@@ -74,7 +74,7 @@ func PlayerView(gameID string, playerID int, playerName string) templ.Component
 			var templ_7745c5c3_Var5 string
 			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(gameID)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/player.templ`, Line: 158, Col: 44}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/player.templ`, Line: 193, Col: 44}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 			if templ_7745c5c3_Err != nil {
@@ -87,7 +87,7 @@ func PlayerView(gameID string, playerID int, playerName string) templ.Component
 			var templ_7745c5c3_Var6 string
 			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(playerID)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/player.templ`, Line: 158, Col: 72}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/player.templ`, Line: 193, Col: 72}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
 			if templ_7745c5c3_Err != nil {
@@ -100,7 +100,7 @@ func PlayerView(gameID string, playerID int, playerName string) templ.Component
 			var templ_7745c5c3_Var7 string
 			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(playerName)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/player.templ`, Line: 158, Col: 104}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/player.templ`, Line: 193, Col: 104}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 			if templ_7745c5c3_Err != nil {
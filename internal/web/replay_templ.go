@@ -54,14 +54,14 @@ func ReplayView(gameID string) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</p></div><div class=\"status\"><span class=\"label\">Status</span><p id=\"replayStatus\" role=\"status\" aria-live=\"polite\">Loading...</p></div></section><section class=\"panel panel--stack replay-panel\"><div><h2>Playback</h2><p>Use the controls to move through rounds and events.</p></div><div class=\"replay-controls\"><button id=\"prevEvent\" class=\"secondary\" type=\"button\">Prev</button> <button id=\"nextEvent\" class=\"secondary\" type=\"button\">Next</button> <select id=\"roundSelect\"></select></div><div id=\"eventCard\" class=\"replay-card card-surface\"></div></section><p id=\"replayError\" class=\"result error\" role=\"alert\"></p><div id=\"replayMeta\" data-game-id=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</p></div><div class=\"status\"><span class=\"label\">Status</span><p id=\"replayStatus\" role=\"status\" aria-live=\"polite\">Loading...</p></div></section><section class=\"panel panel--stack replay-panel\"><div><h2>Playback</h2><p>Use the controls to move through rounds and events.</p></div><div class=\"replay-controls\"><button id=\"prevEvent\" class=\"secondary\" type=\"button\">Prev</button> <button id=\"nextEvent\" class=\"secondary\" type=\"button\">Next</button> <select id=\"roundSelect\"></select></div><div id=\"eventCard\" class=\"replay-card card-surface\"></div></section><section class=\"panel panel--stack replay-panel\"><div><h2>Drawings</h2><p>Watch how each drawing came together.</p></div><div id=\"replayDrawings\" class=\"replay-drawings\"></div></section><p id=\"replayError\" class=\"result error\" role=\"alert\"></p><div id=\"replayMeta\" data-game-id=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			var templ_7745c5c3_Var4 string
 			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(gameID)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/replay.templ`, Line: 36, Col: 44}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/web/replay.templ`, Line: 44, Col: 44}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 			if templ_7745c5c3_Err != nil {
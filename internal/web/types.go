@@ -11,11 +11,26 @@ type DisplayPlayer struct {
 	Name   string
 	Avatar string
 	IsHost bool
+	// Presence is "connected", "idle", "gone", or empty when unknown.
+	Presence string
 }
 
 type DisplayScore struct {
 	Name  string
 	Score int
+	Team  int
+}
+
+type DisplayChainLink struct {
+	PlayerName string
+	Text       string
+	Image      string
+}
+
+type DisplayAward struct {
+	Title      string
+	PlayerName string
+	Detail     string
 }
 
 type DisplayState struct {
@@ -23,6 +38,7 @@ type DisplayState struct {
 	JoinCode           string
 	Phase              string
 	PhaseEndsAt        string
+	PausedSeconds      int
 	RevealStage        string
 	RevealJokeAudio    string
 	RevealVoteSequence string
@@ -38,8 +54,11 @@ type DisplayState struct {
 	StageStatus        string
 	StageImage         string
 	Options            []string
+	ChainLinks         []DisplayChainLink
+	Awards             []DisplayAward
 	Players            []DisplayPlayer
 	Scores             []DisplayScore
+	TeamScores         []DisplayScore
 	ShowScoreboard     bool
 	ShowFinal          bool
 	PlayerCount        int
@@ -1,10 +1,12 @@
 import { gameAPIPath, requestJSON } from "./api_client.js";
-import { createPhaseTimer, createPolling, createReconnect, formatTime } from "./realtime.js";
+import { createClockSync, createPhaseTimer, createPolling, createReconnect, createTransportFallback, formatTime, openEventStream } from "./realtime.js";
+import { createStateStream } from "./state_stream.js";
 
 const els = {
   meta: document.getElementById("audienceMeta"),
   joinCode: document.getElementById("audienceJoinCode"),
   phase: document.getElementById("audiencePhase"),
+  host: document.getElementById("audienceHost"),
   timer: document.getElementById("audienceTimer"),
   joinPanel: document.getElementById("audienceJoinPanel"),
   joinForm: document.getElementById("audienceJoinForm"),
@@ -20,12 +22,15 @@ const els = {
 
 const state = {
   timerEndsAt: 0,
+  timerPaused: 0,
   socket: null,
+  eventSource: null,
   audience: null,
   snapshot: null,
   gameMissing: false
 };
 
+const clock = createClockSync();
 const phaseTimer = createPhaseTimer((endsAt) => {
   state.timerEndsAt = endsAt;
   renderTimer();
@@ -80,6 +85,8 @@ function markGameMissing() {
     state.socket = null;
     socket.close();
   }
+  state.eventSource?.close();
+  state.eventSource = null;
   if (els.phase) {
     els.phase.textContent = "game not found";
   }
@@ -90,15 +97,21 @@ function markGameMissing() {
 
 function renderTimer() {
   if (!els.timer) return;
+  if (state.timerPaused > 0) {
+    els.timer.textContent = formatTime(state.timerPaused);
+    return;
+  }
   if (!state.timerEndsAt) {
     els.timer.textContent = "--:--";
     return;
   }
-  const remaining = Math.max(0, Math.round((state.timerEndsAt - Date.now()) / 1000));
+  const remaining = Math.max(0, Math.round((state.timerEndsAt - clock.now()) / 1000));
   els.timer.textContent = formatTime(remaining);
 }
 
 function syncTimer(snapshot) {
+  clock.observe(snapshot.server_time);
+  state.timerPaused = snapshot.paused ? Number(snapshot.paused_remaining || 0) : 0;
   phaseTimer.setEndsAt(snapshot.phase_ends_at || "");
 }
 
@@ -157,6 +170,9 @@ function renderSnapshot(snapshot) {
   if (els.phase) {
     els.phase.textContent = snapshot.phase || "unknown";
   }
+  if (els.host) {
+    els.host.textContent = snapshot.host_name || "No host yet";
+  }
   if (els.error) {
     els.error.textContent = "";
   }
@@ -243,49 +259,100 @@ async function loadSnapshot() {
   renderSnapshot(data);
 }
 
+const stateStream = createStateStream((snapshot) => renderSnapshot(snapshot));
+
+const transport = createTransportFallback();
+
+function handleRealtimeMessage(raw, socket) {
+  try {
+    const payload = JSON.parse(raw);
+    if (clock.handle(payload)) {
+      renderTimer();
+      return;
+    }
+    if (stateStream.handle(payload, socket)) {
+      return;
+    }
+    if (payload?.type === "state_changed") {
+      loadSnapshot();
+      return;
+    }
+    if (payload && typeof payload === "object" && payload.phase) {
+      renderSnapshot(payload);
+    }
+  } catch {
+    // Ignore non-JSON and HTML-targeted payloads.
+  }
+}
+
+async function handleDisconnect() {
+  if (state.gameMissing) {
+    return;
+  }
+  polling.start();
+  await loadSnapshot();
+  if (state.gameMissing) {
+    return;
+  }
+  reconnect.schedule(() => !state.gameMissing);
+}
+
+function connectEventStream(gameId) {
+  if (state.eventSource) return;
+  state.eventSource = openEventStream(gameId, { role: "audience" }, {
+    onOpen: () => {
+      polling.stop();
+      reconnect.reset();
+    },
+    onMessage: (raw) => handleRealtimeMessage(raw, null),
+    onClosed: () => {
+      state.eventSource?.close();
+      state.eventSource = null;
+      handleDisconnect();
+    }
+  });
+}
+
 function connectWS() {
   if (state.gameMissing) return;
   const gameId = els.meta?.dataset.gameId || "";
+  if (gameId && transport.useEventStream()) {
+    connectEventStream(gameId);
+    return;
+  }
   if (!gameId || typeof WebSocket === "undefined") {
     polling.start();
     return;
   }
   const protocol = window.location.protocol === "https:" ? "wss" : "ws";
+  const query = new URLSearchParams({ role: "audience", ...stateStream.query() });
   const socket = new WebSocket(
-    `${protocol}://${window.location.host}/ws/games/${encodeURIComponent(gameId)}?role=audience`
+    `${protocol}://${window.location.host}/ws/games/${encodeURIComponent(gameId)}?${query}`
   );
   state.socket = socket;
+  let opened = false;
 
   socket.addEventListener("open", () => {
+    opened = true;
+    transport.opened();
     polling.stop();
     reconnect.reset();
-    loadSnapshot();
+    clock.start(() => state.socket);
   });
 
   socket.addEventListener("message", (event) => {
-    try {
-      const payload = JSON.parse(event.data);
-      if (payload && typeof payload === "object" && payload.phase) {
-        renderSnapshot(payload);
-      }
-    } catch {
-      // Ignore non-JSON and HTML-targeted payloads.
-    }
+    handleRealtimeMessage(event.data, socket);
   });
 
-  socket.addEventListener("close", async () => {
+  socket.addEventListener("close", () => {
     if (state.socket === socket) {
       state.socket = null;
+      clock.stop();
     }
-    if (state.gameMissing) {
-      return;
-    }
-    polling.start();
-    await loadSnapshot();
-    if (state.gameMissing) {
-      return;
+    if (!opened) {
+      transport.failed();
     }
-    reconnect.schedule(() => !state.gameMissing);
+    handleDisconnect();
   });
 
   socket.addEventListener("error", () => {
@@ -1,4 +1,4 @@
-import { createPhaseTimer, createReconnect, formatTime } from "./realtime.js";
+import { createClockSync, createPhaseTimer, createReconnect, createTransportFallback, formatTime, openEventStream } from "./realtime.js";
 import { applyHTMLMessage } from "./ws_html.js";
 
 let displayContent = document.getElementById("displayContent");
@@ -59,6 +59,7 @@ const state = {
   muted: false,
   phase: "",
   phaseEndsAt: 0,
+  pausedSeconds: 0,
   revealStage: "",
   revealJokeAudio: "",
   revealVoteSequenceRaw: "[]",
@@ -84,6 +85,7 @@ const state = {
   voteRevealKey: "",
   voteRevealTimer: null,
   socket: null,
+  eventSource: null,
   gameMissing: false
 };
 
@@ -101,6 +103,9 @@ const phaseTimer = createPhaseTimer((endsAt) => {
   renderTimer();
 });
 
+// Deadlines are server times, so the countdown runs on the server's clock.
+const clock = createClockSync();
+
 const reconnect = createReconnect(() => connectWS(), {
   baseDelayMs: 2000
 });
@@ -165,6 +170,8 @@ function markGameMissing() {
     state.socket = null;
     socket.close();
   }
+  state.eventSource?.close();
+  state.eventSource = null;
   resetRealtimeMediaState();
   if (gameError) {
     gameError.textContent = "game not found";
@@ -196,11 +203,15 @@ function stopAllMusic() {
 function renderTimer() {
   const timerEl = document.getElementById("displayTimer");
   if (!timerEl) return;
+  if (state.pausedSeconds > 0) {
+    timerEl.textContent = formatTime(state.pausedSeconds);
+    return;
+  }
   if (!state.phaseEndsAt) {
     timerEl.textContent = "--:--";
     return;
   }
-  const remaining = Math.max(0, Math.round((state.phaseEndsAt - Date.now()) / 1000));
+  const remaining = Math.max(0, Math.round((state.phaseEndsAt - clock.now()) / 1000));
   timerEl.textContent = formatTime(remaining);
   if (remaining === 0 && timerEndSound) {
     const key = `${state.phase}:${state.round || 0}`;
@@ -339,7 +350,7 @@ function detectProgressEvents(next) {
   }
 
   if (state.phase === "drawings" && next.phase === "guesses") {
-    const timedOut = Boolean(state.phaseEndsAt) && state.phaseEndsAt <= Date.now();
+    const timedOut = Boolean(state.phaseEndsAt) && state.phaseEndsAt <= clock.now();
     const text = timedOut ? "Drawing time ended" : "All drawings in";
     triggerDisplayEvent(text, "impact-phase", timerEndSound, 0.95, 0.9);
   }
@@ -558,6 +569,9 @@ function syncFromContent() {
   const guessRequiredCount = Number(displayContent.dataset.guessRequiredCount || 0);
   const voteSubmittedCount = Number(displayContent.dataset.voteSubmittedCount || 0);
   const voteRequiredCount = Number(displayContent.dataset.voteRequiredCount || 0);
+  // A paused phase shows the time it was frozen with.
+  const pausedSeconds = Number(displayContent.dataset.pausedSeconds || 0);
+  state.pausedSeconds = Number.isNaN(pausedSeconds) ? 0 : pausedSeconds;
   phaseTimer.setEndsAt(displayContent.dataset.phaseEndsAt || "");
   const countValue = Number(displayContent.dataset.playerCount || 0);
   const roundValue = Number(displayContent.dataset.round || 0);
@@ -648,46 +662,100 @@ document.addEventListener(
   { once: true }
 );
 
+const transport = createTransportFallback();
+// The host's big screen link carries the token the display socket needs.
+const displayToken = new URLSearchParams(window.location.search).get("token") || "";
+
+function handleRealtimeMessage(raw) {
+  let message;
+  try {
+    message = JSON.parse(raw);
+  } catch {
+    return;
+  }
+  if (clock.handle(message)) {
+    renderTimer();
+    return;
+  }
+  const result = applyHTMLMessage(message);
+  if (result && result.target) {
+    displayContent = result.target;
+    syncFromContent();
+  }
+}
+
+function handleConnected() {
+  state.connected = true;
+  if (gameError) {
+    gameError.textContent = "";
+  }
+  syncFromContent();
+}
+
+async function handleDisconnect(gameId) {
+  state.connected = false;
+  resetRealtimeMediaState();
+  if (state.gameMissing) {
+    return;
+  }
+  const exists = await gameStillExists(gameId);
+  if (!exists) {
+    markGameMissing();
+    return;
+  }
+  scheduleReconnect();
+}
+
 function connectWS() {
   if (!displayContent || state.gameMissing) return;
   const gameId = displayContent.dataset.gameId;
   if (!gameId) return;
+  if (!displayToken) {
+    if (gameError) {
+      gameError.textContent = "Open this screen from the host's big screen link.";
+    }
+    return;
+  }
   reconnect.clear();
+  const params = new URLSearchParams({ role: "display", display_token: displayToken });
+  if (transport.useEventStream()) {
+    if (state.eventSource) return;
+    state.eventSource = openEventStream(gameId, params, {
+      onOpen: handleConnected,
+      onMessage: handleRealtimeMessage,
+      onClosed: () => {
+        state.eventSource?.close();
+        state.eventSource = null;
+        handleDisconnect(gameId);
+      }
+    });
+    return;
+  }
   const protocol = window.location.protocol === "https:" ? "wss" : "ws";
-  const socket = new WebSocket(`${protocol}://${window.location.host}/ws/games/${encodeURIComponent(gameId)}?role=display`);
+  const socket = new WebSocket(`${protocol}://${window.location.host}/ws/games/${encodeURIComponent(gameId)}?${params}`);
   state.socket = socket;
+  let opened = false;
 
   socket.addEventListener("open", () => {
-    state.connected = true;
-    if (gameError) {
-      gameError.textContent = "";
-    }
-    syncFromContent();
+    opened = true;
+    transport.opened();
+    clock.start(() => state.socket);
+    handleConnected();
   });
 
   socket.addEventListener("message", (event) => {
-    const result = applyHTMLMessage(event.data);
-    if (result && result.target) {
-      displayContent = result.target;
-      syncFromContent();
-    }
+    handleRealtimeMessage(event.data);
   });
 
-  socket.addEventListener("close", async () => {
+  socket.addEventListener("close", () => {
     if (state.socket === socket) {
       state.socket = null;
+      clock.stop();
     }
-    state.connected = false;
-    resetRealtimeMediaState();
-    if (state.gameMissing) {
-      return;
-    }
-    const exists = await gameStillExists(gameId);
-    if (!exists) {
-      markGameMissing();
-      return;
+    if (!opened) {
+      transport.failed();
     }
-    scheduleReconnect();
+    handleDisconnect(gameId);
   });
 
   socket.addEventListener("error", () => {
@@ -3,21 +3,28 @@ import {
   fetchPrompt,
   postAdvance,
   postAvatar,
+  postChainLink,
   postEndGame,
   postDrawing,
   postGuess,
   postLike,
+  postPause,
+  postAddTime,
 	postResume,
   postKick,
+  postTransferHost,
   postSettings,
   postStartGame,
-  postVote
+  postVote,
+  useCommandChannel
 } from "./player_api.js";
 import { applyBrushColor, clearCanvas, setupCanvas } from "./player_canvas.js";
-import { createPhaseTimer, createPolling, createReconnect, formatTime } from "./realtime.js";
+import { createClockSync, createPhaseTimer, createPolling, createReconnect, createTransportFallback, formatTime, openEventStream } from "./realtime.js";
 import { updateFromSnapshot } from "./player_view.js";
 import { applyHTMLMessage } from "./ws_html.js";
-import { getPlayerRecoveryCredentials } from "./api_client.js";
+import { createStateStream } from "./state_stream.js";
+import { createCommandChannel } from "./ws_commands.js";
+import { getPlayerAuthToken, getPlayerRecoveryCredentials } from "./api_client.js";
 
 const ctx = {
   els: {
@@ -57,9 +64,13 @@ const ctx = {
     hostSection: document.getElementById("hostSection"),
     hostStartGame: document.getElementById("hostStartGame"),
     hostAdvanceGame: document.getElementById("hostAdvanceGame"),
+    hostPauseGame: document.getElementById("hostPauseGame"),
+    hostAddTime: document.getElementById("hostAddTime"),
     hostEndGame: document.getElementById("hostEndGame"),
+    hostDisplayLink: document.getElementById("hostDisplayLink"),
     hostHelp: document.getElementById("hostHelp"),
     hostLobbyStatus: document.getElementById("hostLobbyStatus"),
+    hostPresenceAlert: document.getElementById("hostPresenceAlert"),
     hostSettingsForm: document.getElementById("hostSettingsForm"),
     hostRoundsInput: document.getElementById("hostRoundsInput"),
     hostLobbyLocked: document.getElementById("hostLobbyLocked"),
@@ -67,6 +78,11 @@ const ctx = {
 		hostAudienceEnabled: document.getElementById("hostAudienceEnabled"),
 		hostJokesEnabled: document.getElementById("hostJokesEnabled"),
 		hostPublicReplay: document.getElementById("hostPublicReplay"),
+		hostRulesetSelect: document.getElementById("hostRulesetSelect"),
+		hostTimerPreset: document.getElementById("hostTimerPreset"),
+		hostTeamCountInput: document.getElementById("hostTeamCountInput"),
+		hostTeamCountLabel: document.getElementById("hostTeamCountLabel"),
+		hostScoringRules: document.getElementById("hostScoringRules"),
     hostSettingsStatus: document.getElementById("hostSettingsStatus"),
     hostPlayerActions: document.getElementById("hostPlayerActions"),
     phaseTimer: document.getElementById("phaseTimer"),
@@ -83,6 +99,7 @@ const ctx = {
     lastPhase: "",
     lastVoteKey: "",
     lastGuessKey: "",
+    lastChainKey: "",
     lastResultsKey: "",
     brushColor: "#1a1a1a",
     brushSize: 4,
@@ -91,12 +108,15 @@ const ctx = {
     canvasHeight: 600,
     avatarCtx: null,
     timerEndsAt: 0,
+    timerPaused: 0,
     authToken: "",
     avatarLocked: false,
     wsConn: null,
+    eventSource: null,
     unloading: false,
     gameMissing: false,
-    recoveryCode: ""
+    recoveryCode: "",
+    presenceAlertPlayer: 0
   },
   actions: {}
 };
@@ -130,6 +150,7 @@ ctx.actions.applyAvatarColor = () => {
 };
 ctx.actions.fetchPrompt = () => fetchPromptForPlayer();
 ctx.actions.clearCanvas = () => clearCanvas(ctx);
+const clock = createClockSync();
 const phaseTimer = createPhaseTimer((endsAt) => {
   ctx.state.timerEndsAt = endsAt;
   renderTimer();
@@ -146,6 +167,15 @@ const wsReconnect = createReconnect(() => {
   maxExponent: 5
 });
 
+function readScoringRules(container) {
+  if (!container) return undefined;
+  const rules = {};
+  container.querySelectorAll("[data-scoring-rule]").forEach((input) => {
+    rules[input.dataset.scoringRule] = Number(input.value || 0);
+  });
+  return rules;
+}
+
 function playAudio(audio) {
   if (!audio) return;
   const playPromise = audio.play();
@@ -158,15 +188,21 @@ function playAudio(audio) {
 
 function renderTimer() {
   if (!ctx.els.phaseTimer) return;
+  if (ctx.state.timerPaused > 0) {
+    ctx.els.phaseTimer.textContent = formatTime(ctx.state.timerPaused);
+    return;
+  }
   if (!ctx.state.timerEndsAt) {
     ctx.els.phaseTimer.textContent = "--:--";
     return;
   }
-  const remaining = Math.max(0, Math.round((ctx.state.timerEndsAt - Date.now()) / 1000));
+  const remaining = Math.max(0, Math.round((ctx.state.timerEndsAt - clock.now()) / 1000));
   ctx.els.phaseTimer.textContent = formatTime(remaining);
 }
 
 function syncTimer(data) {
+  clock.observe(data.server_time);
+  ctx.state.timerPaused = data.paused ? Number(data.paused_remaining || 0) : 0;
   phaseTimer.setEndsAt(data.phase_ends_at || "");
 }
 
@@ -182,6 +218,8 @@ function markGameMissing() {
     ctx.state.wsConn = null;
     socket.close();
   }
+  ctx.state.eventSource?.close();
+  ctx.state.eventSource = null;
   if (ctx.els.joinCode) {
     ctx.els.joinCode.textContent = "Unavailable";
   }
@@ -224,6 +262,7 @@ async function loadPlayerView() {
 async function handleWSDisconnect(socket) {
   if (ctx.state.wsConn === socket) {
     ctx.state.wsConn = null;
+    clock.stop();
   }
   if (ctx.state.unloading || ctx.state.gameMissing) {
     return;
@@ -236,45 +275,139 @@ async function handleWSDisconnect(socket) {
   wsReconnect.schedule(() => !ctx.state.unloading && !ctx.state.gameMissing && Boolean(ctx.els.meta));
 }
 
+const activityIntervalMs = 15000;
+let lastActivitySent = 0;
+
+// reportActivity lets the server tell an idle player from one who is still
+// playing along; it sends at most one message per interval.
+function reportActivity() {
+  const socket = ctx.state.wsConn;
+  if (!socket || socket.readyState !== WebSocket.OPEN) return;
+  const now = Date.now();
+  if (now - lastActivitySent < activityIntervalMs) return;
+  lastActivitySent = now;
+  socket.send(JSON.stringify({ type: "activity" }));
+}
+
+["pointerdown", "keydown", "visibilitychange"].forEach((name) => {
+  document.addEventListener(name, () => {
+    if (document.visibilityState === "visible") reportActivity();
+  }, { passive: true });
+});
+
+function showPresenceAlert(data) {
+  if (!ctx.els.hostPresenceAlert) return;
+  const name = data.player_name || "A player";
+  ctx.state.presenceAlertPlayer = data.player_id;
+  ctx.els.hostPresenceAlert.textContent = `${name} has been away for ${data.away_seconds}s. Their turns will be skipped until they reconnect.`;
+}
+
+const stateStream = createStateStream((data) => {
+  if (ctx.els.playerError) {
+    ctx.els.playerError.textContent = "";
+  }
+  updateFromSnapshot(ctx, data);
+  syncTimer(data);
+});
+
+const commands = createCommandChannel({
+  getSocket: () => ctx.state.wsConn,
+  getState: () => stateStream.state()
+});
+useCommandChannel(commands);
+
+const transport = createTransportFallback();
+
+function handleRealtimeMessage(raw, socket) {
+  const htmlResult = applyHTMLMessage(raw);
+  if (htmlResult) {
+    return;
+  }
+  try {
+    const data = JSON.parse(raw);
+    if (clock.handle(data)) {
+      renderTimer();
+      return;
+    }
+    if (stateStream.handle(data, socket)) {
+      return;
+    }
+    if (commands.handle(data)) {
+      return;
+    }
+    if (data.type === "state_changed") {
+      loadPlayerView();
+      return;
+    }
+    if (data.type === "presence_alert") {
+      showPresenceAlert(data);
+      return;
+    }
+    updateFromSnapshot(ctx, data);
+    syncTimer(data);
+  } catch {
+    // ignore invalid payloads
+  }
+}
+
+// connectEventStream is used instead of the websocket on networks that
+// block upgrades. Actions then go over REST.
+function connectEventStream() {
+  if (ctx.state.eventSource) return;
+  const gameId = ctx.els.meta.dataset.gameId;
+  const playerId = ctx.els.meta.dataset.playerId;
+  ctx.state.eventSource = openEventStream(gameId, { player_id: playerId, auth_token: getPlayerAuthToken(gameId, playerId) }, {
+    onOpen: () => {
+      wsReconnect.reset();
+      polling.stop();
+    },
+    onMessage: (raw) => handleRealtimeMessage(raw, null),
+    onClosed: () => {
+      ctx.state.eventSource?.close();
+      ctx.state.eventSource = null;
+      handleWSDisconnect(null);
+    }
+  });
+}
+
 function connectWS() {
   if (!ctx.els.meta || ctx.state.gameMissing) return;
+  if (transport.useEventStream()) {
+    connectEventStream();
+    return;
+  }
   const existing = ctx.state.wsConn;
   if (existing && (existing.readyState === WebSocket.OPEN || existing.readyState === WebSocket.CONNECTING)) {
     return;
   }
   const gameId = ctx.els.meta.dataset.gameId;
+  const playerId = ctx.els.meta.dataset.playerId;
   const protocol = window.location.protocol === "https:" ? "wss" : "ws";
-  const socket = new WebSocket(`${protocol}://${window.location.host}/ws/games/${encodeURIComponent(gameId)}`);
+  const query = new URLSearchParams({ player_id: playerId, auth_token: getPlayerAuthToken(gameId, playerId), ...stateStream.query() });
+  const socket = new WebSocket(`${protocol}://${window.location.host}/ws/games/${encodeURIComponent(gameId)}?${query}`);
   ctx.state.wsConn = socket;
+  let opened = false;
 
   socket.addEventListener("open", () => {
+    opened = true;
+    transport.opened();
     if (ctx.state.wsConn !== socket) {
       return;
     }
     wsReconnect.reset();
     polling.stop();
-    loadPlayerView();
+    clock.start(() => ctx.state.wsConn);
+    commands.resend(socket);
   });
 
-	socket.addEventListener("message", (event) => {
-    const htmlResult = applyHTMLMessage(event.data);
-    if (htmlResult) {
-      return;
-    }
-		try {
-			const data = JSON.parse(event.data);
-			if (data.type === "state_changed") {
-				loadPlayerView();
-				return;
-			}
-      updateFromSnapshot(ctx, data);
-      syncTimer(data);
-    } catch {
-      // ignore invalid payloads
-    }
+  socket.addEventListener("message", (event) => {
+    handleRealtimeMessage(event.data, socket);
   });
 
   socket.addEventListener("close", () => {
+    if (!opened) {
+      transport.failed();
+    }
     handleWSDisconnect(socket);
   });
 
@@ -408,6 +541,44 @@ if (ctx.els.hostAdvanceGame) {
   });
 }
 
+if (ctx.els.hostPauseGame) {
+  ctx.els.hostPauseGame.addEventListener("click", async () => {
+    if (!ctx.els.meta) return;
+    const gameId = ctx.els.meta.dataset.gameId;
+    const playerId = Number(ctx.els.meta.dataset.playerId);
+    const { res, data } = await postPause(gameId, playerId, ctx.state.authToken);
+    if (!res.ok) {
+      if (ctx.els.playerError) {
+        ctx.els.playerError.textContent = data.error || "Unable to pause game.";
+      }
+      return;
+    }
+    if (ctx.els.playerError) {
+      ctx.els.playerError.textContent = "";
+    }
+    updateFromSnapshot(ctx, data);
+  });
+}
+
+if (ctx.els.hostAddTime) {
+  ctx.els.hostAddTime.addEventListener("click", async () => {
+    if (!ctx.els.meta) return;
+    const gameId = ctx.els.meta.dataset.gameId;
+    const playerId = Number(ctx.els.meta.dataset.playerId);
+    const { res, data } = await postAddTime(gameId, playerId, ctx.state.authToken);
+    if (!res.ok) {
+      if (ctx.els.playerError) {
+        ctx.els.playerError.textContent = data.error || "Unable to add time.";
+      }
+      return;
+    }
+    if (ctx.els.playerError) {
+      ctx.els.playerError.textContent = "";
+    }
+    updateFromSnapshot(ctx, data);
+  });
+}
+
 if (ctx.els.hostEndGame) {
   ctx.els.hostEndGame.addEventListener("click", async () => {
     if (!ctx.els.meta) return;
@@ -446,7 +617,14 @@ if (ctx.els.hostSettingsForm) {
 			avatars_enabled: Boolean(ctx.els.hostAvatarsEnabled?.checked),
 			audience_enabled: Boolean(ctx.els.hostAudienceEnabled?.checked),
 			jokes_enabled: Boolean(ctx.els.hostJokesEnabled?.checked),
-			public_replay: Boolean(ctx.els.hostPublicReplay?.checked)
+			public_replay: Boolean(ctx.els.hostPublicReplay?.checked),
+			ruleset: ctx.els.hostRulesetSelect?.value || "",
+			team_count: Number(ctx.els.hostTeamCountInput?.value || 0),
+			scoring_rules: readScoringRules(ctx.els.hostScoringRules),
+			// Custom timers can only be set through the API, so leave them alone.
+			timers: ctx.els.hostTimerPreset && ctx.els.hostTimerPreset.value !== "custom"
+				? { preset: ctx.els.hostTimerPreset.value }
+				: undefined
     });
     if (!res.ok) {
       if (ctx.els.hostSettingsStatus) {
@@ -479,14 +657,15 @@ if (ctx.els.hostPlayerActions) {
     }
     const gameId = ctx.els.meta.dataset.gameId;
     const playerId = Number(ctx.els.meta.dataset.playerId);
-    const { res, data } = await postKick(gameId, {
+    const transfer = target.dataset.action === "transfer-host";
+    const { res, data } = await (transfer ? postTransferHost : postKick)(gameId, {
       player_id: playerId,
       auth_token: ctx.state.authToken,
       target_id: targetID
     });
     if (!res.ok) {
       if (ctx.els.playerError) {
-        ctx.els.playerError.textContent = data.error || "Unable to remove player.";
+        ctx.els.playerError.textContent = data.error || (transfer ? "Unable to transfer host." : "Unable to remove player.");
       }
       return;
     }
@@ -507,7 +686,9 @@ if (ctx.els.guessForm) {
     }
     const gameId = ctx.els.meta.dataset.gameId;
     const playerId = Number(ctx.els.meta.dataset.playerId);
-    const { res, data } = await postGuess(gameId, playerId, guess);
+    const { res, data } = ctx.state.lastPhase === "chain-describe"
+      ? await postChainLink(gameId, playerId, { description: guess })
+      : await postGuess(gameId, playerId, guess);
     if (!res.ok) {
       if (ctx.els.playerError) {
         ctx.els.playerError.textContent = data.error || "Unable to submit guess.";
@@ -518,6 +699,12 @@ if (ctx.els.guessForm) {
       ctx.els.playerError.textContent = "";
     }
     updateFromSnapshot(ctx, data);
+    if (data.guess_notice) {
+      ctx.els.guessInput.value = "";
+      if (ctx.els.guessStatus) {
+        ctx.els.guessStatus.textContent = data.guess_notice;
+      }
+    }
   });
 }
 
@@ -560,11 +747,13 @@ if (ctx.els.voteForm) {
   });
 }
 
-setupCanvas(ctx, async (dataUrl) => {
+setupCanvas(ctx, async (dataUrl, strokes) => {
   if (!ctx.els.meta) return;
   const gameId = ctx.els.meta.dataset.gameId;
   const playerId = Number(ctx.els.meta.dataset.playerId);
-  const { res, data } = await postDrawing(gameId, playerId, dataUrl, ctx.state.assignedPrompt);
+  const { res, data } = ctx.state.lastPhase === "chain-draw"
+    ? await postChainLink(gameId, playerId, { imageData: dataUrl })
+    : await postDrawing(gameId, playerId, strokes, ctx.state.assignedPrompt);
   if (!res.ok) {
     if (ctx.els.playerError) {
       ctx.els.playerError.textContent = data.error || "Unable to submit drawing.";
@@ -608,6 +797,7 @@ window.addEventListener("beforeunload", () => {
     ctx.state.wsConn.close();
     ctx.state.wsConn = null;
   }
+  ctx.state.eventSource?.close();
 });
 
 loadPlayerView();
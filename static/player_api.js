@@ -1,5 +1,17 @@
 import { gameAPIPath, getPlayerAuthToken, requestJSON } from "./api_client.js";
 
+let commands = null;
+
+// useCommandChannel sends the actions below over the game websocket while it
+// is open, falling back to REST otherwise.
+export function useCommandChannel(channel) {
+  commands = channel;
+}
+
+function sendCommand(type, data, fallback) {
+  return commands ? commands.send(type, data, fallback) : fallback();
+}
+
 export async function fetchSnapshot(gameId, playerId) {
 	const authToken = getPlayerAuthToken(gameId, playerId);
 	const query = authToken ? `?auth_token=${encodeURIComponent(authToken)}` : "";
@@ -13,11 +25,11 @@ export async function fetchPrompt(gameId, playerId) {
 }
 
 export async function postStartGame(gameId, playerId, authToken) {
-  return requestJSON(gameAPIPath(gameId, "/start"), {
+  return sendCommand("start_game", {}, () => requestJSON(gameAPIPath(gameId, "/start"), {
     method: "POST",
     headers: { "Content-Type": "application/json" },
     body: JSON.stringify({ player_id: playerId, auth_token: authToken || "" })
-  });
+  }));
 }
 
 export async function postAvatar(gameId, playerId, avatarData) {
@@ -29,87 +41,117 @@ export async function postAvatar(gameId, playerId, avatarData) {
   });
 }
 
-export async function postDrawing(gameId, playerId, imageData, prompt) {
+export async function postDrawing(gameId, playerId, strokes, prompt) {
   const authToken = getPlayerAuthToken(gameId, playerId);
-  return requestJSON(gameAPIPath(gameId, "/drawings"), {
+  return sendCommand("submit_drawing", { strokes, prompt }, () => requestJSON(gameAPIPath(gameId, "/drawings"), {
     method: "POST",
     headers: { "Content-Type": "application/json" },
     body: JSON.stringify({
       player_id: playerId,
-      image_data: imageData,
+      strokes,
       prompt,
       auth_token: authToken
     })
-  });
+  }));
 }
 
 export async function postGuess(gameId, playerId, guess) {
   const authToken = getPlayerAuthToken(gameId, playerId);
-  return requestJSON(gameAPIPath(gameId, "/guesses"), {
+  return sendCommand("submit_guess", { guess }, () => requestJSON(gameAPIPath(gameId, "/guesses"), {
     method: "POST",
     headers: { "Content-Type": "application/json" },
     body: JSON.stringify({ player_id: playerId, guess, auth_token: authToken })
-  });
+  }));
+}
+
+export async function postChainLink(gameId, playerId, link) {
+  const authToken = getPlayerAuthToken(gameId, playerId);
+  const entry = { image_data: link.imageData || "", description: link.description || "" };
+  return sendCommand("submit_chain_link", entry, () => requestJSON(gameAPIPath(gameId, "/chain"), {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ player_id: playerId, ...entry, auth_token: authToken })
+  }));
 }
 
 export async function postVote(gameId, playerId, payload) {
   const authToken = getPlayerAuthToken(gameId, playerId);
-  return requestJSON(gameAPIPath(gameId, "/votes"), {
+  const vote = { choice_id: payload?.choice_id || "", choice: payload?.choice || "" };
+  return sendCommand("submit_vote", vote, () => requestJSON(gameAPIPath(gameId, "/votes"), {
     method: "POST",
     headers: { "Content-Type": "application/json" },
-    body: JSON.stringify({
-      player_id: playerId,
-      choice_id: payload?.choice_id || "",
-      choice: payload?.choice || "",
-      auth_token: authToken
-    })
-  });
+    body: JSON.stringify({ player_id: playerId, ...vote, auth_token: authToken })
+  }));
 }
 
 export async function postLike(gameId, playerId, drawingIndex, choiceId) {
   const authToken = getPlayerAuthToken(gameId, playerId);
-  return requestJSON(gameAPIPath(gameId, "/likes"), {
+  return sendCommand("like", { drawing_index: drawingIndex, choice_id: choiceId }, () => requestJSON(gameAPIPath(gameId, "/likes"), {
     method: "POST",
     headers: { "Content-Type": "application/json" },
     body: JSON.stringify({ player_id: playerId, drawing_index: drawingIndex, choice_id: choiceId, auth_token: authToken })
-  });
+  }));
 }
 
 export async function postAdvance(gameId, playerId, authToken) {
-  return requestJSON(gameAPIPath(gameId, "/advance"), {
+  return sendCommand("advance", {}, () => requestJSON(gameAPIPath(gameId, "/advance"), {
     method: "POST",
     headers: { "Content-Type": "application/json" },
     body: JSON.stringify({ player_id: playerId, auth_token: authToken || "" })
-  });
+  }));
+}
+
+export async function postPause(gameId, playerId, authToken) {
+  return sendCommand("pause", {}, () => requestJSON(gameAPIPath(gameId, "/pause"), {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ player_id: playerId, auth_token: authToken || "" })
+  }));
+}
+
+export async function postAddTime(gameId, playerId, authToken) {
+  return sendCommand("add_time", {}, () => requestJSON(gameAPIPath(gameId, "/add-time"), {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ player_id: playerId, auth_token: authToken || "" })
+  }));
 }
 
 export async function postResume(gameId, playerId, authToken) {
-  return requestJSON(gameAPIPath(gameId, "/resume"), {
+  return sendCommand("resume", {}, () => requestJSON(gameAPIPath(gameId, "/resume"), {
     method: "POST", headers: { "Content-Type": "application/json" },
     body: JSON.stringify({ player_id: playerId, auth_token: authToken || "" })
-  });
+  }));
 }
 
 export async function postEndGame(gameId, playerId, authToken) {
-  return requestJSON(gameAPIPath(gameId, "/end"), {
+  return sendCommand("end_game", {}, () => requestJSON(gameAPIPath(gameId, "/end"), {
     method: "POST",
     headers: { "Content-Type": "application/json" },
     body: JSON.stringify({ player_id: playerId, auth_token: authToken || "" })
-  });
+  }));
 }
 
 export async function postSettings(gameId, payload) {
-  return requestJSON(gameAPIPath(gameId, "/settings"), {
+  return sendCommand("update_settings", payload, () => requestJSON(gameAPIPath(gameId, "/settings"), {
     method: "POST",
     headers: { "Content-Type": "application/json" },
     body: JSON.stringify(payload)
-  });
+  }));
 }
 
 export async function postKick(gameId, payload) {
-  return requestJSON(gameAPIPath(gameId, "/kick"), {
+  return sendCommand("kick", payload, () => requestJSON(gameAPIPath(gameId, "/kick"), {
     method: "POST",
     headers: { "Content-Type": "application/json" },
     body: JSON.stringify(payload)
-  });
+  }));
+}
+
+export async function postTransferHost(gameId, payload) {
+  return sendCommand("transfer_host", payload, () => requestJSON(gameAPIPath(gameId, "/transfer-host"), {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(payload)
+  }));
 }
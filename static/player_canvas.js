@@ -11,9 +11,11 @@ export function setupCanvas(ctx, onSave) {
   ctx2d.strokeStyle = state.brushColor;
   ctx2d.fillStyle = "#ffffff";
   ctx2d.fillRect(0, 0, state.canvasWidth, state.canvasHeight);
+  resetStrokes(state);
 
   let drawing = false;
   let lastPoint = null;
+  let currentStroke = null;
 
   function getPoint(event) {
     const rect = els.canvas.getBoundingClientRect();
@@ -24,12 +26,32 @@ export function setupCanvas(ctx, onSave) {
     }
     const x = (clientX - rect.left) * (els.canvas.width / rect.width);
     const y = (clientY - rect.top) * (els.canvas.height / rect.height);
-    return { x, y };
+    return {
+      x: Math.min(Math.max(x, 0), els.canvas.width),
+      y: Math.min(Math.max(y, 0), els.canvas.height)
+    };
+  }
+
+  function recordPoint(point) {
+    if (!currentStroke) return;
+    if (state.strokeStartedAt == null) {
+      state.strokeStartedAt = performance.now();
+    }
+    const t = Math.round(performance.now() - state.strokeStartedAt);
+    currentStroke.points.push([Math.round(point.x * 100) / 100, Math.round(point.y * 100) / 100, t]);
   }
 
   function startDraw(event) {
     drawing = true;
     lastPoint = getPoint(event);
+    if (!lastPoint) return;
+    currentStroke = { color: state.brushColor, width: ctx2d.lineWidth, points: [] };
+    state.strokes.push(currentStroke);
+    recordPoint(lastPoint);
+    ctx2d.beginPath();
+    ctx2d.arc(lastPoint.x, lastPoint.y, ctx2d.lineWidth / 2, 0, Math.PI * 2);
+    ctx2d.fillStyle = state.brushColor;
+    ctx2d.fill();
   }
 
   function moveDraw(event) {
@@ -40,12 +62,14 @@ export function setupCanvas(ctx, onSave) {
     ctx2d.moveTo(lastPoint.x, lastPoint.y);
     ctx2d.lineTo(point.x, point.y);
     ctx2d.stroke();
+    recordPoint(point);
     lastPoint = point;
   }
 
   function endDraw() {
     drawing = false;
     lastPoint = null;
+    currentStroke = null;
   }
 
   els.canvas.addEventListener("pointerdown", (event) => {
@@ -71,7 +95,7 @@ export function setupCanvas(ctx, onSave) {
   if (els.saveCanvas) {
     els.saveCanvas.addEventListener("click", () => {
       const dataUrl = els.canvas.toDataURL("image/png");
-      onSave(dataUrl);
+      onSave(dataUrl, strokeDocument(state));
     });
   }
 }
@@ -86,4 +110,22 @@ export function clearCanvas(ctx) {
   if (!ctx2d) return;
   ctx2d.fillStyle = "#ffffff";
   ctx2d.fillRect(0, 0, ctx.state.canvasWidth, ctx.state.canvasHeight);
+  resetStrokes(ctx.state);
+}
+
+function resetStrokes(state) {
+  state.strokes = [];
+  state.strokeStartedAt = null;
+}
+
+// strokeDocument is the vector form of the canvas the server validates and
+// rasterizes; see internal/strokes.
+export function strokeDocument(state) {
+  return {
+    version: 1,
+    width: state.canvasWidth,
+    height: state.canvasHeight,
+    background: "#ffffff",
+    strokes: state.strokes.filter((stroke) => stroke.points.length > 0)
+  };
 }
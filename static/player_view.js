@@ -1,3 +1,5 @@
+import { timeLapseControls } from "./timelapse.js";
+
 function normalizePhase(phase) {
   if (phase === "votes") {
     return "guesses-votes";
@@ -26,9 +28,12 @@ export function updateFromSnapshot(ctx, data) {
   const avatarMap = data.player_avatars || {};
   const avatarLocks = data.player_avatar_locks || {};
   const playerIDs = Array.isArray(data.player_ids) ? data.player_ids : [];
+  const teamMap = data.player_teams || {};
+  const presenceMap = data.player_presence || {};
   players.forEach((player, index) => {
     const item = document.createElement("li");
     item.className = "player-entry";
+    item.dataset.presence = presenceMap[String(playerIDs[index] || "")] || "";
     const dot = document.createElement("span");
     dot.className = "player-dot";
     const colorKey = String(playerIDs[index] || "");
@@ -49,8 +54,16 @@ export function updateFromSnapshot(ctx, data) {
       item.appendChild(dot);
     }
     const name = document.createElement("span");
-    name.textContent = player;
+    const label = Number(playerIDs[index] || 0) === data.host_id ? `${player}*` : player;
+    name.textContent = teamMap[colorKey] ? `${label} (Team ${teamMap[colorKey]})` : label;
     item.appendChild(name);
+    const presenceLabel = { idle: "idle", gone: "away" }[item.dataset.presence];
+    if (presenceLabel) {
+      const badge = document.createElement("span");
+      badge.className = "player-presence";
+      badge.textContent = presenceLabel;
+      item.appendChild(badge);
+    }
     els.playerList.appendChild(item);
   });
 
@@ -69,6 +82,13 @@ export function updateFromSnapshot(ctx, data) {
 
   state.lastPhase = phase;
 
+  if (state.presenceAlertPlayer && presenceMap[String(state.presenceAlertPlayer)] !== "gone") {
+    state.presenceAlertPlayer = 0;
+    if (els.hostPresenceAlert) {
+      els.hostPresenceAlert.textContent = "";
+    }
+  }
+
   state.hostId = data.host_id || 0;
   const playerId = Number(els.meta?.dataset.playerId || 0);
   const playerNameValue = els.meta?.dataset.playerName || "";
@@ -79,7 +99,7 @@ export function updateFromSnapshot(ctx, data) {
     } else if (isHost) {
       els.playerName.textContent = `Signed in as ${playerNameValue}. You're the host.`;
     } else {
-      els.playerName.textContent = `Signed in as ${playerNameValue}. Waiting for the host to begin.`;
+      els.playerName.textContent = `Signed in as ${playerNameValue}. Waiting for ${data.host_name || "the host"} to begin.`;
     }
   }
   if (els.hostSection) {
@@ -102,6 +122,8 @@ export function updateFromSnapshot(ctx, data) {
         els.hostHelp.textContent = "Only the host can control game flow.";
       } else if (phase === "complete") {
         els.hostHelp.textContent = "Game complete.";
+      } else if (phase === "paused") {
+        els.hostHelp.textContent = "Game paused. Resume when everyone is ready.";
       } else if (phase !== "lobby") {
         els.hostHelp.textContent = "Use Advance when everyone is ready for the next step.";
       } else if (!enoughPlayers) {
@@ -111,11 +133,29 @@ export function updateFromSnapshot(ctx, data) {
       }
     }
   }
+  if (els.hostPauseGame) {
+    const canPause = isHost && phase !== "lobby" && phase !== "complete" && phase !== "paused";
+    els.hostPauseGame.disabled = !canPause;
+    els.hostPauseGame.style.display = canPause ? "inline-flex" : "none";
+  }
+  if (els.hostAddTime) {
+    const canAddTime = isHost && phase !== "paused" && Boolean(data.phase_ends_at);
+    els.hostAddTime.disabled = !canAddTime;
+    els.hostAddTime.style.display = canAddTime ? "inline-flex" : "none";
+  }
   if (els.hostEndGame) {
     const canEnd = isHost && phase !== "complete";
     els.hostEndGame.disabled = !canEnd;
     els.hostEndGame.style.display = isHost ? "inline-flex" : "none";
   }
+  if (els.hostDisplayLink) {
+    // Only the host's snapshot carries the display token.
+    const token = isHost ? data.display_token || "" : "";
+    els.hostDisplayLink.style.display = token ? "inline-flex" : "none";
+    if (token) {
+      els.hostDisplayLink.href = `/display/${encodeURIComponent(data.game_id)}?token=${encodeURIComponent(token)}`;
+    }
+  }
   if (els.hostLobbyStatus) {
     const minPlayers = data.min_players > 0 ? data.min_players : 2;
     const maxPlayers = data.max_players > 0 ? data.max_players : 10;
@@ -132,6 +172,16 @@ export function updateFromSnapshot(ctx, data) {
 	if (els.hostAudienceEnabled) els.hostAudienceEnabled.checked = Boolean(data.audience_enabled);
 	if (els.hostJokesEnabled) els.hostJokesEnabled.checked = Boolean(data.jokes_enabled);
 	if (els.hostPublicReplay) els.hostPublicReplay.checked = Boolean(data.public_replay);
+	if (els.hostRulesetSelect && data.ruleset) els.hostRulesetSelect.value = data.ruleset;
+	if (els.hostTimerPreset && data.timers?.preset) els.hostTimerPreset.value = data.timers.preset;
+	if (els.hostTeamCountInput) els.hostTeamCountInput.value = data.team_count || 2;
+	if (els.hostTeamCountLabel) els.hostTeamCountLabel.style.display = data.ruleset === "teams_v1" ? "" : "none";
+	if (els.hostScoringRules && data.scoring_rules) {
+		els.hostScoringRules.querySelectorAll("[data-scoring-rule]").forEach((input) => {
+			const value = data.scoring_rules[input.dataset.scoringRule];
+			if (value !== undefined && document.activeElement !== input) input.value = value;
+		});
+	}
   if (els.hostSettingsForm) {
     const disabled = phase !== "lobby" || !isHost;
     Array.from(els.hostSettingsForm.elements).forEach((el) => {
@@ -192,6 +242,7 @@ export function updateFromSnapshot(ctx, data) {
   updateScoreboard(ctx, data, phase);
   updateGuessPhase(ctx, data, phase);
   updateVotePhase(ctx, data, phase);
+  updateChainPhase(ctx, data, phase);
   updateResultsPhase(ctx, data, phase);
 }
 
@@ -219,8 +270,16 @@ function renderHostPlayerActions(ctx, players, playerIDs, phase, isHost, hostId)
     if (playerID === hostId || phase !== "lobby") {
       kickButton.disabled = true;
     }
+    const hostButton = document.createElement("button");
+    hostButton.type = "button";
+    hostButton.className = "secondary";
+    hostButton.textContent = "Make host";
+    hostButton.dataset.playerId = String(playerID);
+    hostButton.dataset.action = "transfer-host";
+    hostButton.disabled = playerID === hostId || phase === "complete";
     row.appendChild(label);
     row.appendChild(kickButton);
+    row.appendChild(hostButton);
     els.hostPlayerActions.appendChild(row);
   });
 }
@@ -279,6 +338,61 @@ function updateGuessPhase(ctx, data, phase) {
   }
 }
 
+function updateChainPhase(ctx, data, phase) {
+  const { els, state, actions } = ctx;
+  if (phase !== "chain-draw" && phase !== "chain-describe") {
+    state.lastChainKey = "";
+    return;
+  }
+  const playerId = Number(els.meta?.dataset.playerId || 0);
+  const assignments = Array.isArray(data.chain_assignments) ? data.chain_assignments : [];
+  const assignment = assignments.find((entry) => Number(entry.player_id) === playerId) || null;
+  const chainKey = assignment ? `${assignment.chain_index}:${assignment.step}` : "none";
+  if (chainKey !== state.lastChainKey) {
+    if (actions.clearCanvas) {
+      actions.clearCanvas();
+    }
+    if (els.guessInput) {
+      els.guessInput.value = "";
+    }
+    state.lastChainKey = chainKey;
+  }
+  if (els.drawSection) {
+    const drawing = phase === "chain-draw" && Boolean(assignment);
+    els.drawSection.style.display = drawing ? "grid" : "none";
+    if (drawing && els.promptText) {
+      els.promptText.textContent = assignment.text || "";
+    }
+  }
+  if (!els.guessSection) return;
+  els.guessSection.style.display = "grid";
+  const describing = phase === "chain-describe" && Boolean(assignment);
+  if (els.guessStatus) {
+    if (describing) {
+      els.guessStatus.textContent = "Describe this drawing for the next player.";
+    } else if (assignment) {
+      els.guessStatus.textContent = "Draw what you're given, then save.";
+    } else {
+      els.guessStatus.textContent = "Submitted. Waiting for the rest of the chain.";
+    }
+  }
+  if (els.guessImage) {
+    const drawingImage = describing ? assignment.drawing_image : "";
+    els.guessImage.src = drawingImage || "";
+    els.guessImage.style.display = drawingImage ? "block" : "none";
+  }
+  if (els.guessForm) {
+    els.guessForm.style.display = describing ? "grid" : "none";
+    const submitButton = els.guessForm.querySelector("button");
+    if (submitButton) {
+      submitButton.disabled = !describing;
+    }
+    if (els.guessInput) {
+      els.guessInput.disabled = !describing;
+    }
+  }
+}
+
 function updateVotePhase(ctx, data, phase) {
   const { els, state } = ctx;
   if (!els.voteSection) return;
@@ -338,27 +452,58 @@ function updateVotePhase(ctx, data, phase) {
 function updateResultsPhase(ctx, data, phase) {
   const { els, state } = ctx;
   if (!els.resultsSection) return;
-  if (phase !== "results" && phase !== "complete") {
+  if (phase !== "results" && phase !== "complete" && phase !== "chain-reveal") {
     els.resultsSection.style.display = "none";
     return;
   }
   els.resultsSection.style.display = "grid";
+  if (phase === "chain-reveal" || Array.isArray(data.chains)) {
+    const chains = phase === "chain-reveal" ? [data.chain_reveal].filter(Boolean) : data.chains;
+    const chainsKey = JSON.stringify({ chains, phase });
+    if (chainsKey !== state.lastResultsKey) {
+      renderReveal(ctx, null);
+      renderChains(ctx, chains);
+      state.lastResultsKey = chainsKey;
+    }
+    return;
+  }
   const results = data.results || [];
   const scores = data.scores || [];
   const reveal = data.reveal || null;
-  const resultsKey = JSON.stringify({ results, scores, reveal, phase });
+  const awards = Array.isArray(data.awards) ? data.awards : [];
+  const resultsKey = JSON.stringify({ results, scores, reveal, awards, phase });
   if (resultsKey !== state.lastResultsKey) {
     if (phase === "results") {
-      renderReveal(ctx, reveal);
+      renderReveal(ctx, reveal && reveal.stage !== "awards" ? reveal : null);
       renderResults(ctx, [], scores);
     } else {
       renderReveal(ctx, null);
       renderResults(ctx, results, scores);
     }
+    renderAwards(ctx, awards);
     state.lastResultsKey = resultsKey;
   }
 }
 
+function renderAwards(ctx, awards) {
+  const { els } = ctx;
+  if (!els.resultsScores || awards.length === 0) return;
+  const card = document.createElement("div");
+  card.className = "result-card card-surface";
+  const title = document.createElement("h3");
+  title.textContent = "Awards";
+  const list = document.createElement("ul");
+  list.className = "reveal-list";
+  awards.forEach((award) => {
+    const item = document.createElement("li");
+    item.textContent = `${award.title}: ${award.player_name || "Player"} (${award.detail || award.count})`;
+    list.appendChild(item);
+  });
+  card.appendChild(title);
+  card.appendChild(list);
+  els.resultsScores.prepend(card);
+}
+
 function updateScoreboard(ctx, data, phase) {
   const { els, state } = ctx;
   if (!els.scoreboardSection || !els.scoreboardList) return;
@@ -377,10 +522,10 @@ function updateScoreboard(ctx, data, phase) {
         ? `Round ${roundNumber} is starting. Here are the scores so far.`
         : "Here are the scores so far.";
   }
-  renderScoreList(els.scoreboardList, scores);
+  renderScoreList(els.scoreboardList, scores, data.team_scores);
 }
 
-function renderScoreList(container, scores) {
+function renderScoreList(container, scores, teamScores) {
   container.innerHTML = "";
   if (!Array.isArray(scores) || scores.length === 0) {
     const note = document.createElement("p");
@@ -389,11 +534,22 @@ function renderScoreList(container, scores) {
     container.appendChild(note);
     return;
   }
+  if (Array.isArray(teamScores) && teamScores.length > 0) {
+    const teams = document.createElement("ul");
+    teams.className = "score-list";
+    teamScores.forEach((entry) => {
+      const item = document.createElement("li");
+      item.textContent = `Team ${entry.team}: ${entry.score}`;
+      teams.appendChild(item);
+    });
+    container.appendChild(teams);
+  }
   const list = document.createElement("ul");
   list.className = "score-list";
   scores.forEach((entry) => {
     const item = document.createElement("li");
-    item.textContent = `${entry.player_name || "Player"}: ${entry.score}`;
+    const team = entry.team ? ` (Team ${entry.team})` : "";
+    item.textContent = `${entry.player_name || "Player"}${team}: ${entry.score}`;
     list.appendChild(item);
   });
   container.appendChild(list);
@@ -434,7 +590,7 @@ function renderVoteOptions(ctx, options, noteText, likeOnly = false) {
     const span = document.createElement("span");
     span.textContent = choice.text || "";
     if (input.disabled) {
-      span.textContent = `${choice.text || ""} (your lie)`;
+      span.textContent = `${choice.text || ""} ${choice.team_lie ? "(your team's lie)" : "(your lie)"}`;
     }
     if (choice.type === "prompt") {
       label.classList.add("vote-option-prompt");
@@ -472,7 +628,8 @@ function renderResults(ctx, results, scores) {
     list.className = "score-list";
     scores.forEach((entry) => {
       const item = document.createElement("li");
-      item.textContent = `${entry.player_name || "Player"}: ${entry.score}`;
+      const likes = entry.likes ? ` (${entry.likes} likes)` : "";
+      item.textContent = `${entry.player_name || "Player"}: ${entry.score}${likes}`;
       list.appendChild(item);
     });
     card.appendChild(title);
@@ -512,7 +669,8 @@ function renderResults(ctx, results, scores) {
     guessesList.className = "reveal-list";
     (entry.guesses || []).forEach((guess) => {
       const item = document.createElement("li");
-      item.textContent = `${guess.player_name || "Player"}: ${guess.text}`;
+      const likes = guess.likes ? ` (${guess.likes} likes)` : "";
+      item.textContent = `${guess.player_name || "Player"}: ${guess.text}${likes}`;
       guessesList.appendChild(item);
     });
     guesses.appendChild(guessesList);
@@ -564,6 +722,10 @@ function renderResults(ctx, results, scores) {
 
     card.appendChild(header);
     card.appendChild(image);
+    const timeLapse = timeLapseControls(image, entry);
+    if (timeLapse) {
+      card.appendChild(timeLapse);
+    }
     card.appendChild(guesses);
     card.appendChild(votes);
     if (Array.isArray(entry.score_deltas) && entry.score_deltas.length > 0) {
@@ -575,7 +737,7 @@ function renderResults(ctx, results, scores) {
       deltaList.className = "reveal-list";
       entry.score_deltas.forEach((delta) => {
         const item = document.createElement("li");
-        item.textContent = `${delta.player_name || "Player"}: +${delta.delta || 0}`;
+        item.textContent = formatScoreDelta(delta);
         deltaList.appendChild(item);
       });
       deltaBlock.appendChild(deltaTitle);
@@ -594,6 +756,45 @@ function renderResults(ctx, results, scores) {
   });
 }
 
+function formatScoreDelta(entry) {
+  const reasons = Array.isArray(entry.reasons) ? entry.reasons : [];
+  const text = `${entry.player_name || "Player"}: +${entry.delta || 0}`;
+  return reasons.length > 0 ? `${text} (${reasons.join(", ")})` : text;
+}
+
+function renderChains(ctx, chains) {
+  const { els } = ctx;
+  if (!els.resultsList) return;
+  els.resultsList.innerHTML = "";
+  chains.forEach((chain) => {
+    const card = document.createElement("div");
+    card.className = "result-card card-surface";
+    const title = document.createElement("h3");
+    title.textContent = `${chain.owner_name || "Player"}'s chain`;
+    card.appendChild(title);
+    const prompt = document.createElement("p");
+    prompt.className = "prompt-text";
+    prompt.textContent = `Prompt: ${chain.prompt || ""}`;
+    card.appendChild(prompt);
+    (chain.links || []).forEach((link) => {
+      if (link.kind === "drawing") {
+        const image = document.createElement("img");
+        image.className = "guess-image media-frame";
+        image.alt = `Drawing by ${link.player_name || "Player"}`;
+        image.src = link.drawing_image || "";
+        card.appendChild(image);
+      }
+      const caption = document.createElement("p");
+      caption.className = "meta";
+      caption.textContent = link.kind === "drawing"
+        ? `Drawn by ${link.player_name || "Player"}`
+        : `${link.player_name || "Player"}: ${link.text || ""}`;
+      card.appendChild(caption);
+    });
+    els.resultsList.appendChild(card);
+  });
+}
+
 function renderReveal(ctx, reveal) {
   const { els } = ctx;
   if (!els.revealSection) return;
@@ -685,6 +886,10 @@ function renderReveal(ctx, reveal) {
 
   els.revealSection.appendChild(header);
   els.revealSection.appendChild(image);
+  const timeLapse = timeLapseControls(image, reveal);
+  if (timeLapse) {
+    els.revealSection.appendChild(timeLapse);
+  }
   els.revealSection.appendChild(owner);
   if (promptEl) {
     els.revealSection.appendChild(promptEl);
@@ -701,7 +906,7 @@ function renderReveal(ctx, reveal) {
     deltas.className = "reveal-list";
     reveal.score_deltas.forEach((entry) => {
       const item = document.createElement("li");
-      item.textContent = `${entry.player_name || "Player"}: +${entry.delta || 0}`;
+      item.textContent = formatScoreDelta(entry);
       deltas.appendChild(item);
     });
     els.revealSection.appendChild(deltas);
@@ -47,6 +47,80 @@ export function createPhaseTimer(onTick) {
   };
 }
 
+// createClockSync estimates how far the server's clock is from this one, so
+// countdowns can run on server time. Over a websocket it pings NTP style and
+// trusts the sample with the shortest round trip, since it has the least
+// room for error. Until a pong arrives, or over an event stream, the
+// server_time stamped on messages gives a rough offset that ignores latency.
+export function createClockSync({ burst = 4, intervalMs = 30000, samples = 8 } = {}) {
+  let offset = 0;
+  let latency = 0;
+  let pinged = false;
+  let history = [];
+  let interval = null;
+
+  const ping = (socket) => {
+    if (!socket || socket.readyState !== WebSocket.OPEN) return;
+    socket.send(JSON.stringify({ type: "clock_ping", data: { client_time: Date.now() } }));
+  };
+
+  const observe = (serverTime) => {
+    const value = Number(serverTime);
+    if (pinged || !Number.isFinite(value) || value <= 0) return;
+    offset = value - Date.now();
+  };
+
+  // handle takes any realtime message; it returns true for pongs, which
+  // need no further handling.
+  const handle = (message) => {
+    if (!message || typeof message !== "object") return false;
+    if (message.type !== "clock_pong") {
+      observe(message.server_time);
+      return false;
+    }
+    const received = Date.now();
+    const sent = Number(message.client_time);
+    const serverReceived = Number(message.server_received);
+    const serverSent = Number(message.server_time);
+    if (![sent, serverReceived, serverSent].every(Number.isFinite) || sent <= 0) return true;
+    const roundTrip = Math.max(0, received - sent - (serverSent - serverReceived));
+    history.push({ roundTrip, offset: (serverReceived - sent + (serverSent - received)) / 2 });
+    if (history.length > samples) history = history.slice(-samples);
+    const best = history.reduce((min, sample) => (sample.roundTrip < min.roundTrip ? sample : min));
+    offset = best.offset;
+    latency = best.roundTrip / 2;
+    pinged = true;
+    return true;
+  };
+
+  const stop = () => {
+    if (interval) {
+      clearInterval(interval);
+      interval = null;
+    }
+  };
+
+  // start sends a quick burst of pings on a fresh socket, then keeps the
+  // estimate current with one every interval.
+  const start = (getSocket) => {
+    stop();
+    for (let i = 0; i < burst; i += 1) {
+      setTimeout(() => ping(getSocket()), i * 250);
+    }
+    interval = setInterval(() => ping(getSocket()), intervalMs);
+  };
+
+  return {
+    handle,
+    latency: () => latency,
+    now: () => Date.now() + offset,
+    observe,
+    offset: () => offset,
+    start,
+    stop
+  };
+}
+
 export function createPolling(loadFn, intervalMs = 3000) {
   let handle = null;
 
@@ -122,3 +196,36 @@ export function createReconnect(connectFn, options = {}) {
     schedule
   };
 }
+
+// createTransportFallback decides when to give up on websockets: once
+// `limit` sockets in a row have closed without ever opening, the upgrade is
+// probably blocked and pages switch to the server-sent event stream.
+export function createTransportFallback(limit = 2) {
+  let failures = 0;
+  return {
+    opened: () => {
+      failures = 0;
+    },
+    failed: () => {
+      failures += 1;
+    },
+    useEventStream: () => typeof EventSource !== "undefined" && (typeof WebSocket === "undefined" || failures >= limit)
+  };
+}
+
+// openEventStream subscribes to a game's server-sent events, which carry the
+// same messages as its websocket. The browser reconnects by itself and
+// resumes from the last game version it saw; onClosed runs only when it
+// gives up, for example because the game is gone.
+export function openEventStream(gameId, params, { onOpen, onMessage, onClosed }) {
+  const query = new URLSearchParams(params);
+  const source = new EventSource(`/sse/games/${encodeURIComponent(gameId)}?${query}`);
+  source.addEventListener("open", () => onOpen?.());
+  source.addEventListener("message", (event) => onMessage(event.data));
+  source.addEventListener("error", () => {
+    if (source.readyState === EventSource.CLOSED) {
+      onClosed?.();
+    }
+  });
+  return source;
+}
@@ -1,4 +1,5 @@
 import { gameAPIPath, requestJSON } from "./api_client.js";
+import { timeLapseControls } from "./timelapse.js";
 
 const meta = document.getElementById("replayMeta");
 const status = document.getElementById("replayStatus");
@@ -7,6 +8,7 @@ const eventCard = document.getElementById("eventCard");
 const prevEvent = document.getElementById("prevEvent");
 const nextEvent = document.getElementById("nextEvent");
 const roundSelect = document.getElementById("roundSelect");
+const drawingsList = document.getElementById("replayDrawings");
 
 let events = [];
 let currentIndex = 0;
@@ -31,6 +33,37 @@ async function loadReplay() {
   renderRoundOptions();
   currentIndex = 0;
   renderEvent();
+  renderDrawings(Array.isArray(data.drawings) ? data.drawings : []);
+}
+
+function renderDrawings(drawings) {
+  if (!drawingsList) return;
+  drawingsList.innerHTML = "";
+  if (!drawings.length) {
+    drawingsList.textContent = "No drawings yet.";
+    return;
+  }
+  drawings.forEach((entry) => {
+    const card = document.createElement("div");
+    card.className = "replay-card card-surface";
+    const title = document.createElement("h3");
+    title.textContent = entry.prompt || `Round ${entry.round_number} • Drawing ${entry.drawing_index + 1}`;
+    const artist = document.createElement("p");
+    artist.className = "meta";
+    artist.textContent = `Artist: ${entry.drawing_owner_name || "Unknown"}`;
+    const image = document.createElement("img");
+    image.className = "guess-image media-frame";
+    image.alt = "Drawing";
+    image.src = entry.drawing_image || "";
+    card.appendChild(title);
+    card.appendChild(artist);
+    card.appendChild(image);
+    const timeLapse = timeLapseControls(image, entry);
+    if (timeLapse) {
+      card.appendChild(timeLapse);
+    }
+    drawingsList.appendChild(card);
+  });
 }
 
 function buildRoundMap() {
@@ -0,0 +1,91 @@
+// Applies an RFC 6902 patch made of add, remove and replace operations.
+export function applyPatch(doc, ops) {
+  let root = doc;
+  ops.forEach((op) => {
+    const tokens = op.path === "" ? [] : op.path.slice(1).split("/").map((token) => token.replace(/~1/g, "/").replace(/~0/g, "~"));
+    if (tokens.length === 0) {
+      root = op.op === "remove" ? null : op.value;
+      return;
+    }
+    let parent = root;
+    tokens.slice(0, -1).forEach((token) => {
+      parent = parent[Array.isArray(parent) ? Number(token) : token];
+      if (parent === undefined || parent === null) {
+        throw new Error(`path not found: ${op.path}`);
+      }
+    });
+    const last = tokens[tokens.length - 1];
+    if (Array.isArray(parent)) {
+      const index = last === "-" ? parent.length : Number(last);
+      if (op.op === "add") {
+        parent.splice(index, 0, op.value);
+      } else if (op.op === "remove") {
+        parent.splice(index, 1);
+      } else {
+        parent[index] = op.value;
+      }
+      return;
+    }
+    if (op.op === "remove") {
+      delete parent[last];
+    } else {
+      parent[last] = op.value;
+    }
+  });
+  return root;
+}
+
+// createStateStream keeps a copy of the server's state from state_snapshot
+// and state_delta messages. When a delta does not follow the last revision it
+// asks the server for a fresh snapshot.
+export function createStateStream(onState) {
+  let stream = "";
+  let rev = 0;
+  let state = null;
+
+  const resync = (socket) => {
+    if (socket && socket.readyState === WebSocket.OPEN) {
+      socket.send(JSON.stringify({ type: "resync" }));
+    }
+  };
+
+  const handle = (message, socket) => {
+    if (!message || typeof message !== "object") {
+      return false;
+    }
+    if (message.type === "state_snapshot") {
+      stream = message.stream;
+      rev = message.rev;
+      state = message.state;
+      onState(state);
+      return true;
+    }
+    if (message.type !== "state_delta") {
+      return false;
+    }
+    if (state !== null && message.stream === stream && message.rev <= rev) {
+      return true;
+    }
+    if (state === null || message.stream !== stream || message.base !== rev) {
+      resync(socket);
+      return true;
+    }
+    try {
+      state = applyPatch(state, message.ops || []);
+      rev = message.rev;
+      onState(state);
+    } catch {
+      state = null;
+      resync(socket);
+    }
+    return true;
+  };
+
+  return {
+    handle,
+    state: () => state,
+    // query is appended to the websocket URL so a reconnect only gets the
+    // deltas it missed.
+    query: () => ({ deltas: "1", stream, since: String(rev) })
+  };
+}
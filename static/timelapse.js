@@ -0,0 +1,28 @@
+// timeLapseControls returns buttons that swap a drawing's image for its
+// time-lapse GIF and back, plus a download link, or null when the drawing
+// has no recorded strokes.
+export function timeLapseControls(image, entry) {
+  const url = entry ? entry.timelapse_url : "";
+  if (!image || !url) return null;
+  const still = entry.drawing_image || image.src;
+  const row = document.createElement("div");
+  row.className = "inline-actions";
+  const toggle = document.createElement("button");
+  toggle.type = "button";
+  toggle.className = "secondary";
+  toggle.textContent = "Watch it being drawn";
+  toggle.addEventListener("click", () => {
+    const playing = image.dataset.timelapse === "playing";
+    // Re-adding the query restarts the GIF from its first frame.
+    image.src = playing ? still : `${url}?play=${Date.now()}`;
+    image.dataset.timelapse = playing ? "" : "playing";
+    toggle.textContent = playing ? "Watch it being drawn" : "Show finished drawing";
+  });
+  const download = document.createElement("a");
+  download.className = "meta";
+  download.href = `${url}?download=1`;
+  download.textContent = "Download GIF";
+  row.appendChild(toggle);
+  row.appendChild(download);
+  return row;
+}
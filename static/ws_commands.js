@@ -0,0 +1,65 @@
+// createCommandChannel sends player actions over the game websocket and
+// resolves each with the server's ack, shaped like a requestJSON result so
+// callers need not care which path was used. Without an open socket, or when
+// the game lives on another server, the REST fallback is used instead.
+export function createCommandChannel({ getSocket, getState, timeoutMs = 15000 }) {
+  const pending = new Map();
+  let counter = 0;
+
+  const write = (socket, requestId, entry) => {
+    socket.send(JSON.stringify({ type: entry.type, request_id: requestId, data: entry.data }));
+  };
+
+  const send = (type, data, fallback) => {
+    const socket = getSocket();
+    if (!socket || socket.readyState !== WebSocket.OPEN) {
+      return fallback();
+    }
+    counter += 1;
+    const requestId = `${Date.now().toString(36)}-${counter}`;
+    return new Promise((resolve) => {
+      const entry = { type, data, fallback, resolve };
+      entry.timer = setTimeout(() => {
+        pending.delete(requestId);
+        resolve({ res: { ok: false, status: 0 }, data: { error: "Connection lost. Please try again." } });
+      }, timeoutMs);
+      pending.set(requestId, entry);
+      write(socket, requestId, entry);
+    });
+  };
+
+  const handle = (message) => {
+    if (!message || message.type !== "ack") {
+      return false;
+    }
+    const entry = pending.get(message.request_id);
+    if (!entry) {
+      return true;
+    }
+    pending.delete(message.request_id);
+    clearTimeout(entry.timer);
+    if (message.status === 421) {
+      entry.fallback().then(entry.resolve);
+      return true;
+    }
+    if (!message.ok) {
+      entry.resolve({ res: { ok: false, status: message.status }, data: { error: message.error } });
+      return true;
+    }
+    // The state deltas for the change arrive before the ack.
+    const data = { ...(getState() || {}) };
+    if (message.notice) {
+      data.guess_notice = message.notice;
+    }
+    entry.resolve({ res: { ok: true, status: message.status }, data });
+    return true;
+  };
+
+  // resend repeats commands still waiting for an ack once the socket
+  // reconnects; the server acks a repeated request_id without re-running it.
+  const resend = (socket) => {
+    pending.forEach((entry, requestId) => write(socket, requestId, entry));
+  };
+
+  return { handle, resend, send };
+}